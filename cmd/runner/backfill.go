@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/config"
+	"reserve-watch/internal/ingest"
+	"reserve-watch/internal/store"
+)
+
+// runBackfill implements the `backfill` subcommand:
+//
+//	reserve-watch backfill [-series DTWEXBGS,VIXCLS] [-start 2010-01-01] [-end 2020-12-31] [-restart] [-status]
+//
+// Progress is saved after every page, so Ctrl+C followed by a re-run resumes where it stopped.
+func runBackfill(cfg *config.Config, db *store.Store, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	series := fs.String("series", "", "comma-separated series IDs (default: all series with history)")
+	start := fs.String("start", "", "override start date (YYYY-MM-DD)")
+	end := fs.String("end", "", "end date (YYYY-MM-DD, default: today)")
+	restart := fs.Bool("restart", false, "ignore recorded progress and start over")
+	pause := fs.Duration("pause", 500*time.Millisecond, "delay between page requests")
+	status := fs.Bool("status", false, "print recorded coverage and exit")
+	fs.Parse(args)
+
+	if *status {
+		return printBackfillStatus(db)
+	}
+
+	opts := ingest.BackfillOptions{
+		Start:   *start,
+		End:     *end,
+		Restart: *restart,
+		Pause:   *pause,
+	}
+	if *series != "" {
+		opts.SeriesIDs = strings.Split(*series, ",")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	registry := ingest.NewDefaultRegistry(cfg)
+	if err := ingest.Backfill(ctx, db, registry, opts); err != nil {
+		return err
+	}
+
+	// Index history depends on the backfilled inputs
+	saved, err := analytics.UpdateIndexHistory(db, "")
+	if err != nil {
+		return fmt.Errorf("failed to update index history: %w", err)
+	}
+	fmt.Printf("Index history updated (%d points)\n", saved)
+	return nil
+}
+
+func printBackfillStatus(db *store.Store) error {
+	progress, err := db.ListBackfillProgress()
+	if err != nil {
+		return err
+	}
+
+	if len(progress) == 0 {
+		fmt.Println("No backfills recorded")
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "%-16s %-12s %-12s %8s  %s\n", "SERIES", "FROM", "THROUGH", "POINTS", "STATUS")
+	for _, p := range progress {
+		state := "in progress"
+		if p.CompletedAt != nil {
+			state = "complete " + p.CompletedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(os.Stdout, "%-16s %-12s %-12s %8d  %s\n", p.SeriesID, p.StartDate, p.CoveredThrough, p.PointsSaved, state)
+	}
+	return nil
+}
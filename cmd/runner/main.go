@@ -1,15 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"reserve-watch/internal/agents"
 	"reserve-watch/internal/alerts"
+	"reserve-watch/internal/analytics"
 	"reserve-watch/internal/compose"
 	"reserve-watch/internal/config"
 	"reserve-watch/internal/ingest"
@@ -17,6 +19,7 @@ import (
 	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
 	"reserve-watch/internal/web"
+	"reserve-watch/migrations"
 
 	"github.com/robfig/cron/v3"
 )
@@ -37,45 +40,74 @@ func main() {
 	}
 	defer db.Close()
 
-	migrationsDir := filepath.Join(".", "migrations")
-	if err := db.Migrate(migrationsDir); err != nil {
+	// migrate manages the schema itself, so it runs before the automatic upgrade
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			util.ErrorLogger.Fatalf("migrate failed: %v", err)
+		}
+		return
+	}
+
+	applied, err := db.Migrate(migrations.FS)
+	if err != nil {
 		util.ErrorLogger.Fatalf("Failed to run migrations: %v", err)
 	}
+	for _, m := range applied {
+		util.InfoLogger.Printf("Applied migration %s", m.Name)
+	}
+
+	registry := ingest.NewDefaultRegistry(cfg)
+	if err := db.SyncSeries(append(registry.Catalog(), analytics.IndexCatalog()...)); err != nil {
+		util.ErrorLogger.Fatalf("Failed to update series catalog: %v", err)
+	}
 
 	util.InfoLogger.Println("Database initialized")
 
+	if err := analytics.SetRulesFile(cfg.SignalRulesFile); err != nil {
+		util.ErrorLogger.Fatalf("Failed to load signal rules: %v", err)
+	}
+
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, db, os.Args[1], os.Args[2:]); err != nil {
+			util.ErrorLogger.Fatalf("%s failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// No mock data - all data will be fetched from real APIs
 	// If APIs fail, tiles will show "Gathering data..." status
 
 	app := &App{
 		cfg:       cfg,
 		store:     db,
-		fred:      ingest.NewFREDClient(cfg.FREDAPIKey),
-		yahoo:     ingest.NewYahooFinanceClient(),
-		imf:       ingest.NewIMFClient(),
-		swift:     ingest.NewSWIFTClient(),
-		cips:      ingest.NewCIPSClient(),
-		wgc:       ingest.NewWGCClient(),
 		composer:  compose.New("templates", "output"),
 		linkedin:  publish.NewLinkedInPublisher(cfg.LinkedInAccessToken, cfg.LinkedInOrgURN, cfg.DryRun),
 		mailchimp: publish.NewMailchimpPublisher(cfg.MailchimpAPIKey, cfg.MailchimpServer, cfg.MailchimpListID, cfg.DryRun),
+		notifiers: alerts.NewNotifiers(cfg),
+	}
+	app.ingest = ingest.NewRunner(registry, db, cfg.IngestConcurrency)
+	app.subscribe()
+
+	// Each source runs on its own schedule (intraday DXY, daily FRED, weekly polls for
+	// monthly/quarterly releases) so a slow endpoint never delays the others. A panicking
+	// job is logged and recovered rather than taking the server down.
+	c := cron.New(cron.WithLocation(time.UTC), cron.WithChain(cron.Recover(cron.PrintfLogger(util.ErrorLogger))))
+	if err := app.ingest.Schedule(c); err != nil {
+		util.ErrorLogger.Fatalf("Failed to schedule sources: %v", err)
+	}
+	// A slow retry pass is skipped over rather than overlapped by the next one
+	retries := cron.NewChain(cron.SkipIfStillRunning(cron.PrintfLogger(util.ErrorLogger))).Then(cron.FuncJob(app.retryDeliveries))
+	if _, err := c.AddJob("@every 1m", retries); err != nil {
+		util.ErrorLogger.Fatalf("Failed to schedule webhook retries: %v", err)
 	}
-
-	c := cron.New()
-
-	// Daily update at 6:00 AM EST (11:00 AM UTC)
-	// Gives traders 3.5 hours to review before market open (9:30 AM EST)
-	c.AddFunc("0 11 * * *", func() {
-		util.InfoLogger.Println("Running daily data update (6 AM EST / 3 AM PST)...")
-		if err := app.RunDailyCheck(); err != nil {
-			util.ErrorLogger.Printf("Daily check failed: %v", err)
-		}
-	})
 
 	util.InfoLogger.Println("Running initial check...")
-	if err := app.RunDailyCheck(); err != nil {
+	if err := app.ingest.RunAll(context.Background()); err != nil {
 		util.ErrorLogger.Printf("Initial check failed: %v", err)
 	}
+	db.Events().Wait()
+	app.updateIndexHistory("")
+	app.recordSignalTransitions()
 
 	c.Start()
 	util.InfoLogger.Println("Cron scheduler started. Press Ctrl+C to exit.")
@@ -86,7 +118,7 @@ func main() {
 		port = "8080"
 	}
 
-	webServer := web.NewServer(db, port, cfg.StripeSecretKey, cfg.StripePriceProMonthly, cfg.StripePriceProAnnual)
+	webServer := web.NewServer(db, port, cfg.StripeSecretKey, cfg.StripePriceProMonthly, cfg.StripePriceProAnnual, app.notifiers)
 	go func() {
 		if err := webServer.Start(); err != nil {
 			util.ErrorLogger.Printf("Web server error: %v", err)
@@ -103,139 +135,117 @@ func main() {
 
 	util.InfoLogger.Println("Shutting down...")
 	c.Stop()
+	db.Events().Wait()
+}
+
+// runCommand dispatches one-shot subcommands instead of starting the service
+func runCommand(cfg *config.Config, db *store.Store, name string, args []string) error {
+	switch name {
+	case "backfill":
+		return runBackfill(cfg, db, args)
+	default:
+		return fmt.Errorf("unknown command %q (available: backfill, migrate)", name)
+	}
 }
 
 type App struct {
 	cfg       *config.Config
 	store     *store.Store
-	fred      *ingest.FREDClient
-	yahoo     *ingest.YahooFinanceClient
-	imf       *ingest.IMFClient
-	swift     *ingest.SWIFTClient
-	cips      *ingest.CIPSClient
-	wgc       *ingest.WGCClient
+	ingest    *ingest.Runner
 	composer  *compose.Composer
 	linkedin  *publish.LinkedInPublisher
 	mailchimp *publish.MailchimpPublisher
+	notifiers alerts.Notifiers
 }
 
-func (app *App) RunDailyCheck() error {
-	// Fetch real-time data from Yahoo Finance
-	util.InfoLogger.Println("Fetching real-time DXY from Yahoo Finance...")
-	yahooPoint, err := app.yahoo.FetchDXY()
-	if err != nil {
-		util.ErrorLogger.Printf("Yahoo Finance fetch failed: %v", err)
-	} else {
-		util.InfoLogger.Printf("Yahoo DXY: %.4f (date: %s)", yahooPoint.Value, yahooPoint.Date)
-		if err := app.store.SavePoints("DXY_REALTIME", []store.SeriesPoint{yahooPoint}, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save Yahoo data: %v", err)
-		}
-	}
+// contentSeriesID is the series whose updates trigger blog/social content
+const contentSeriesID = "DTWEXBGS"
+
+// subscribe runs the work that follows new observations whenever SavePoints
+// publishes a series update, whichever source or job saved it
+func (app *App) subscribe() {
+	events := app.store.Events()
+	events.Subscribe("alerts", app.checkAlerts)
+	events.Subscribe("indices", app.updateIndexInputs)
+	events.Subscribe("signals", func([]store.SeriesUpdated) { app.recordSignalTransitions() })
+	events.Subscribe("content", app.publishUpdates)
+}
 
-	// Fetch IMF COFER data (CNY reserve share)
-	util.InfoLogger.Println("Fetching IMF COFER data...")
-	coferPoint, err := app.imf.FetchCOFER()
-	if err != nil {
-		util.ErrorLogger.Printf("IMF COFER fetch failed: %v", err)
-	} else {
-		util.InfoLogger.Printf("IMF COFER CNY: %.2f%%", coferPoint.Value)
-		if err := app.store.SavePoints("COFER_CNY", []store.SeriesPoint{coferPoint}, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save COFER data: %v", err)
-		}
+// checkAlerts evaluates the alerts that read the updated series
+func (app *App) checkAlerts(events []store.SeriesUpdated) {
+	seriesIDs := make([]string, len(events))
+	for i, e := range events {
+		seriesIDs[i] = e.SeriesID
 	}
 
-	// Fetch SWIFT RMB Tracker data
-	util.InfoLogger.Println("Fetching SWIFT RMB Tracker...")
-	swiftPoint, err := app.swift.FetchRMBTrackerData()
-	if err != nil {
-		util.ErrorLogger.Printf("SWIFT fetch failed: %v", err)
-	} else {
-		util.InfoLogger.Printf("SWIFT RMB: %.2f%% of global payments", swiftPoint.Value)
-		if err := app.store.SavePoints("SWIFT_RMB", []store.SeriesPoint{swiftPoint}, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save SWIFT data: %v", err)
-		}
+	util.InfoLogger.Printf("Checking alerts after %s update...", strings.Join(seriesIDs, ", "))
+	if err := alerts.CheckSeriesAlerts(app.store, app.notifiers, seriesIDs); err != nil {
+		util.ErrorLogger.Printf("Failed to check alerts: %v", err)
 	}
+}
 
-	// Fetch CIPS network stats
-	util.InfoLogger.Println("Fetching CIPS network stats...")
-	cipsPoints, err := app.cips.GetCIPSSeriesPoints()
-	if err != nil {
-		util.ErrorLogger.Printf("CIPS fetch failed: %v", err)
-	} else {
-		for _, point := range cipsPoints {
-			seriesID := point.Meta["series_id"]
-			util.InfoLogger.Printf("CIPS %s: %.2f", seriesID, point.Value)
-			if err := app.store.SavePoints(seriesID, []store.SeriesPoint{point}, time.Now()); err != nil {
-				util.ErrorLogger.Printf("Failed to save CIPS data: %v", err)
-			}
-		}
+// updateIndexInputs recomputes the index history once per burst of input updates,
+// from the earliest observation that changed
+func (app *App) updateIndexInputs(events []store.SeriesUpdated) {
+	if since, ok := analytics.EarliestIndexInputDate(events); ok {
+		app.updateIndexHistory(since)
 	}
+}
 
-	// Fetch World Gold Council data
-	util.InfoLogger.Println("Fetching World Gold Council data...")
-	wgcCBPurchases, err := app.wgc.FetchCentralBankPurchases()
-	if err != nil {
-		util.ErrorLogger.Printf("WGC CB purchases fetch failed: %v", err)
-	} else {
-		util.InfoLogger.Printf("WGC CB Purchases: %.0f tonnes", wgcCBPurchases.Value)
-		if err := app.store.SavePoints("WGC_CB_PURCHASES", []store.SeriesPoint{wgcCBPurchases}, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save WGC data: %v", err)
+// publishUpdates generates content when the content series gets a new latest observation
+func (app *App) publishUpdates(events []store.SeriesUpdated) {
+	var update *store.SeriesUpdated
+	for i, e := range events {
+		if e.SeriesID == contentSeriesID && e.LatestChanged() {
+			update = &events[i]
 		}
 	}
-
-	// WGC Gold Reserve Share - API integration not yet implemented
-	// Will show "Gathering data..." on homepage until implemented
-	util.InfoLogger.Println("WGC Gold Reserve Share API not yet implemented")
-
-	// Fetch VIX (Volatility Index) from FRED for Trigger Watch
-	util.InfoLogger.Println("Fetching VIX from FRED...")
-	vixResult := app.fred.FetchSeries("VIXCLS")
-	if vixResult.Err == nil && len(vixResult.Points) > 0 {
-		util.InfoLogger.Printf("VIX: %.2f", vixResult.Points[0].Value)
-		if err := app.store.SavePoints("VIXCLS", vixResult.Points, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save VIX: %v", err)
-		}
+	if update == nil {
+		return
 	}
 
-	// Fetch BBB OAS (Credit Spread) from FRED for Trigger Watch
-	util.InfoLogger.Println("Fetching BBB OAS from FRED...")
-	bbbResult := app.fred.FetchSeries("BAMLC0A4CBBB")
-	if bbbResult.Err == nil && len(bbbResult.Points) > 0 {
-		util.InfoLogger.Printf("BBB OAS: %.0f bps", bbbResult.Points[0].Value)
-		if err := app.store.SavePoints("BAMLC0A4CBBB", bbbResult.Points, time.Now()); err != nil {
-			util.ErrorLogger.Printf("Failed to save BBB OAS: %v", err)
-		}
+	if err := app.publishContent(update.SeriesID, update.Previous, update.Latest); err != nil {
+		util.ErrorLogger.Printf("Content generation for %s failed: %v", update.SeriesID, err)
 	}
+}
 
-	// Fetch official USD Index data from FRED
-	seriesID := "DTWEXBGS"
-	util.InfoLogger.Printf("Fetching FRED series: %s", seriesID)
-	result := app.fred.FetchSeries(seriesID)
-	if result.Err != nil {
-		return fmt.Errorf("failed to fetch FRED data: %w", result.Err)
+// retryDeliveries re-sends failed webhook deliveries whose backoff has passed
+func (app *App) retryDeliveries() {
+	attempted, err := alerts.RetryDeliveries(app.store)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to retry webhook deliveries: %v", err)
+		return
 	}
-
-	if len(result.Points) == 0 {
-		util.InfoLogger.Println("No new FRED data points")
-		return nil
+	if attempted > 0 {
+		util.InfoLogger.Printf("Retried %d webhook deliveries", attempted)
 	}
+}
 
-	latest := result.Points[0]
-	existing, err := app.store.GetLatestPoint(seriesID)
+// updateIndexHistory recomputes the persisted index series from since; empty
+// recomputes all of it
+func (app *App) updateIndexHistory(since string) {
+	saved, err := analytics.UpdateIndexHistory(app.store, since)
 	if err != nil {
-		return fmt.Errorf("failed to get latest point: %w", err)
+		util.ErrorLogger.Printf("Failed to update index history: %v", err)
+		return
 	}
+	util.InfoLogger.Printf("Index history updated (%d points)", saved)
+}
 
-	if existing != nil && existing.Date == latest.Date && existing.Value == latest.Value {
-		util.InfoLogger.Println("No changes detected")
-		return nil
+// recordSignalTransitions persists signals whose status changed with the new data
+func (app *App) recordSignalTransitions() {
+	transitions, err := analytics.RecordSignalTransitions(app.store)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to record signal transitions: %v", err)
+		return
 	}
-
-	util.InfoLogger.Println("New data detected, saving to database...")
-	if err := app.store.SavePoints(seriesID, result.Points, time.Now()); err != nil {
-		return fmt.Errorf("failed to save points: %w", err)
+	for _, t := range transitions {
+		util.InfoLogger.Printf("Signal %s: %s -> %s (%s)", t.SignalKey, t.FromStatus, t.ToStatus, t.Rule)
 	}
+}
 
+// publishContent composes and (optionally) publishes posts for a changed series
+func (app *App) publishContent(seriesID string, existing *store.SeriesPoint, latest store.SeriesPoint) error {
 	util.InfoLogger.Println("Generating content...")
 	changeDesc := "showing movement in global currency markets"
 	if existing != nil {
@@ -317,12 +327,6 @@ func (app *App) RunDailyCheck() error {
 		})
 	}
 
-	// Check and trigger alerts
-	util.InfoLogger.Println("Checking alerts...")
-	if err := alerts.CheckAlerts(app.store); err != nil {
-		util.ErrorLogger.Printf("Failed to check alerts: %v", err)
-	}
-
 	return nil
 }
 
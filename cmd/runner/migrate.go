@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/migrations"
+)
+
+// runMigrate implements the `migrate` subcommand:
+//
+//	reserve-watch migrate status
+//	reserve-watch migrate up
+//	reserve-watch migrate down [n]
+//
+// It runs before the automatic migration on startup, so `down` isn't undone by it.
+func runMigrate(db *store.Store, args []string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "status":
+		return printMigrationStatus(db)
+	case "up":
+		applied, err := db.Migrate(migrations.FS)
+		for _, m := range applied {
+			fmt.Printf("Applied %s\n", m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("Schema is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+			steps = n
+		}
+		reverted, err := db.MigrateDown(migrations.FS, steps)
+		for _, m := range reverted {
+			fmt.Printf("Reverted %s\n", m.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("No migrations applied")
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown migrate action %q (available: status, up, down)", action)
+	}
+}
+
+func printMigrationStatus(db *store.Store) error {
+	statuses, err := db.MigrationStatus(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%-8s %-32s %s\n", "VERSION", "NAME", "STATUS")
+	for _, st := range statuses {
+		state := "pending"
+		if st.AppliedAt != nil {
+			state = "applied " + st.AppliedAt.Format("2006-01-02 15:04")
+		}
+		if st.Modified {
+			state += " (modified since applied)"
+		}
+		fmt.Fprintf(os.Stdout, "%-8d %-32s %s\n", st.Version, st.Name, state)
+	}
+	return nil
+}
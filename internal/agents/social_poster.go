@@ -12,7 +12,7 @@ import (
 	"reserve-watch/internal/util"
 )
 
-// SocialPoster automatically posts to Twitter when signals change to watch/crisis
+// SocialPoster automatically posts to Twitter when signals transition to watch/crisis
 type SocialPoster struct {
 	store     *store.Store
 	twitterV2 string // Twitter API v2 Bearer token
@@ -25,50 +25,64 @@ func NewSocialPoster(db *store.Store, twitterToken string) *SocialPoster {
 	}
 }
 
-// CheckAndPost checks all signals and posts if status is watch/crisis and not already posted
+// CheckAndPost posts each signal that transitioned to watch/crisis and hasn't been posted since
 func (sp *SocialPoster) CheckAndPost() error {
 	if sp.twitterV2 == "" {
 		util.InfoLogger.Println("Twitter token not configured, skipping social posts")
 		return nil
 	}
 
-	signals, err := analytics.GetAllSignals(sp.store)
+	// Record any status changes since the last ingest, then post each signal's
+	// current watch/crisis state once
+	if _, err := analytics.RecordSignalTransitions(sp.store); err != nil {
+		util.ErrorLogger.Printf("Failed to record signal transitions: %v", err)
+	}
+
+	states, err := sp.store.GetCurrentSignalStates()
 	if err != nil {
-		return fmt.Errorf("failed to get signals: %w", err)
+		return fmt.Errorf("failed to get signal states: %w", err)
 	}
 
-	labels := map[string]string{
-		"dtwexbgs":           "USD Index",
-		"swift_rmb":          "SWIFT RMB",
-		"cofer_cny":          "COFER CNY",
-		"cips_participants":  "CIPS Network",
-		"wgc_cb_purchases":   "CB Gold Buying",
-		"vix":                "VIX",
-		"bbb_oas":            "BBB Credit Spreads",
+	// Posts use the catalog's short label for each signal's series
+	catalog, err := sp.store.ListSeries()
+	if err != nil {
+		return fmt.Errorf("failed to load series catalog: %w", err)
+	}
+	labels := make(map[string]string, len(catalog))
+	for _, series := range catalog {
+		labels[series.ID] = series.Label
 	}
 
-	for key, sig := range signals {
-		status := string(sig.Status)
+	for _, state := range states {
+		key, status := state.SignalKey, state.ToStatus
 		
 		// Only post for watch or crisis
 		if status != "watch" && status != "crisis" {
 			continue
 		}
 
-		// Check if we already posted this signal+status combo recently (within 24h)
+		// Skip if we already posted since the signal entered this status
 		lastPost, err := sp.store.GetLastSocialPost(key, status)
 		if err != nil {
 			util.ErrorLogger.Printf("Error checking last post: %v", err)
 			continue
 		}
 
-		if lastPost != nil && time.Since(lastPost.PostedAt) < 24*time.Hour {
-			// Already posted recently, skip
+		if lastPost != nil && !lastPost.PostedAt.Before(state.CreatedAt) {
 			continue
 		}
 
+		sig := analytics.Signal{
+			SeriesID: state.SeriesID,
+			Value:    state.Value,
+			AsOf:     state.AsOf,
+			Status:   analytics.SignalStatus(status),
+			Why:      state.Why,
+			Rule:     state.Rule,
+		}
+
 		// Generate post content
-		label := labels[key]
+		label := labels[state.SeriesID]
 		if label == "" {
 			label = key
 		}
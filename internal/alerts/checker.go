@@ -1,26 +1,31 @@
 package alerts
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
+	"fmt"
+	"strings"
 	"time"
 
 	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
 )
 
-// CheckAlerts checks all active alerts against current data
-func CheckAlerts(db *store.Store) error {
+// CheckSeriesAlerts checks the active alerts that read any of the given series
+func CheckSeriesAlerts(db *store.Store, notifiers Notifiers, seriesIDs []string) error {
 	alerts, err := db.GetActiveAlerts()
 	if err != nil {
 		return err
 	}
 
-	util.InfoLogger.Printf("Checking %d active alerts", len(alerts))
+	updated := make(map[string]bool, len(seriesIDs))
+	for _, id := range seriesIDs {
+		updated[id] = true
+	}
 
 	for _, alert := range alerts {
-		if err := checkAlert(db, &alert); err != nil {
+		if !watches(&alert, updated) {
+			continue
+		}
+		if err := checkAlert(db, notifiers, &alert); err != nil {
 			util.ErrorLogger.Printf("Failed to check alert %d: %v", alert.ID, err)
 		}
 	}
@@ -28,103 +33,235 @@ func CheckAlerts(db *store.Store) error {
 	return nil
 }
 
-func checkAlert(db *store.Store, alert *store.Alert) error {
-	// Get latest value for the series
-	points, err := db.GetRecentPoints(alert.SeriesID, 1)
-	if err != nil || len(points) == 0 {
-		util.InfoLogger.Printf("No data found for series %s", alert.SeriesID)
+// watches reports whether an alert reads any of the series
+func watches(alert *store.Alert, series map[string]bool) bool {
+	if !IsExpression(alert.Condition) {
+		return series[alert.SeriesID]
+	}
+
+	expr, err := ParseExpr(alert.Condition)
+	if err != nil {
+		return series[alert.SeriesID]
+	}
+	for _, id := range expr.Series() {
+		if series[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func checkAlert(db *store.Store, notifiers Notifiers, alert *store.Alert) error {
+	if IsExpression(alert.Condition) {
+		return checkExpressionAlert(db, notifiers, alert)
+	}
+
+	points, err := newObservations(db, alert)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
 		return nil
 	}
 
-	latestValue := points[0].Value
-	triggered := false
+	// The first evaluation only establishes which side of the threshold the series is on;
+	// an alert created while already beyond it waits for the next crossing
+	if alert.LastObservedDate == "" {
+		latest := points[len(points)-1]
+		alert.State = store.AlertArmed
+		if breached(alert, latest.Value) {
+			alert.State = store.AlertTriggered
+		}
+		alert.LastObservedDate = latest.Date
+		return db.SaveAlertState(alert, false)
+	}
 
-	// Check condition
-	switch alert.Condition {
-	case "above":
-		if latestValue > alert.Threshold {
-			triggered = true
+	fired := false
+	for _, p := range points {
+		if advance(alert, p.Value, time.Now()) {
+			fired = true
+			fire(db, notifiers, alert, alert.SeriesID, p.Value, alert.Threshold)
 		}
-	case "below":
-		if latestValue < alert.Threshold {
-			triggered = true
+		alert.LastObservedDate = p.Date
+	}
+
+	return db.SaveAlertState(alert, fired)
+}
+
+// IsExpression reports whether an alert condition is an expression rather than
+// a plain 'above'/'below' threshold
+func IsExpression(condition string) bool {
+	return condition != "" && condition != "above" && condition != "below"
+}
+
+// checkExpressionAlert evaluates an expression alert whenever any series it reads has
+// a new latest observation. Matching counts as being beyond the threshold.
+func checkExpressionAlert(db *store.Store, notifiers Notifiers, alert *store.Alert) error {
+	expr, err := ParseExpr(alert.Condition)
+	if err != nil {
+		return fmt.Errorf("invalid expression %q: %w", alert.Condition, err)
+	}
+
+	// The observed marker is each series' latest date, so an update to any one of
+	// them re-evaluates the expression even when they share a date
+	dates := make([]string, 0, len(expr.Series()))
+	for _, id := range expr.Series() {
+		points, err := db.GetRecentPoints(id, 1)
+		if err != nil {
+			return err
+		}
+		if len(points) == 0 {
+			return nil
 		}
+		dates = append(dates, points[0].Date)
+	}
+	latest := strings.Join(dates, "|")
+	if latest == alert.LastObservedDate {
+		return nil
 	}
 
-	if !triggered {
+	result, err := expr.Eval(db.GetRecentPoints)
+	if err != nil {
+		util.InfoLogger.Printf("Alert %d not evaluated: %v", alert.ID, err)
 		return nil
 	}
 
-	// Check if alert was recently triggered (prevent spam)
-	if alert.LastTriggeredAt != nil {
-		timeSince := time.Since(*alert.LastTriggeredAt)
-		if timeSince < 1*time.Hour {
-			util.InfoLogger.Printf("Alert %d was triggered recently (%v ago), skipping", alert.ID, timeSince)
-			return nil
+	first := alert.LastObservedDate == ""
+	alert.LastObservedDate = latest
+	if first {
+		alert.State = store.AlertArmed
+		if result.Match {
+			alert.State = store.AlertTriggered
 		}
+		return db.SaveAlertState(alert, false)
 	}
 
-	util.InfoLogger.Printf("Alert triggered: %s (series: %s, value: %.2f, threshold: %.2f, condition: %s)",
-		alert.Name, alert.SeriesID, latestValue, alert.Threshold, alert.Condition)
+	fired := step(alert, result.Match, !result.Match, time.Now())
+	if fired {
+		fire(db, notifiers, alert, alert.SeriesID, result.Value, result.Threshold)
+	}
+	return db.SaveAlertState(alert, fired)
+}
 
-	// Send webhook if configured
-	webhookStatus := "skipped"
-	if alert.WebhookURL != "" {
-		webhookStatus = sendWebhook(alert, latestValue)
+// newObservations returns points after the alert's last evaluated date, oldest first.
+// Before the first evaluation only the latest point is returned.
+func newObservations(db *store.Store, alert *store.Alert) ([]store.SeriesPoint, error) {
+	if alert.LastObservedDate == "" {
+		points, err := db.GetRecentPoints(alert.SeriesID, 1)
+		if err != nil || len(points) == 0 {
+			util.InfoLogger.Printf("No data found for series %s", alert.SeriesID)
+		}
+		return points, err
 	}
 
-	// Save to history
-	history := &store.AlertHistory{
-		AlertID:       alert.ID,
-		SeriesID:      alert.SeriesID,
-		Value:         latestValue,
-		Threshold:     alert.Threshold,
-		WebhookStatus: webhookStatus,
+	points, err := db.GetPointsBetween(alert.SeriesID, alert.LastObservedDate, "")
+	if err != nil {
+		return nil, err
 	}
+	for len(points) > 0 && points[0].Date <= alert.LastObservedDate {
+		points = points[1:]
+	}
+	return points, nil
+}
 
-	if err := db.SaveAlertHistory(history); err != nil {
-		util.ErrorLogger.Printf("Failed to save alert history: %v", err)
+// breached reports whether value is beyond the alert's threshold
+func breached(alert *store.Alert, value float64) bool {
+	switch alert.Condition {
+	case "above":
+		return value > alert.Threshold
+	case "below":
+		return value < alert.Threshold
 	}
+	return false
+}
 
-	// Update alert's last triggered time
-	if err := db.UpdateAlertTriggered(alert.ID); err != nil {
-		util.ErrorLogger.Printf("Failed to update alert triggered time: %v", err)
+// rearmed reports whether value is back past the threshold by at least the re-arm band
+func rearmed(alert *store.Alert, value float64) bool {
+	switch alert.Condition {
+	case "above":
+		return value <= alert.Threshold-alert.RearmBand
+	case "below":
+		return value >= alert.Threshold+alert.RearmBand
 	}
+	return false
+}
 
-	return nil
+// advance moves the alert's state machine on by one observation and reports whether
+// the alert fires. It fires only on a crossing that holds for Confirmations consecutive
+// observations, outside the cooldown; once fired it waits to re-arm.
+func advance(alert *store.Alert, value float64, now time.Time) bool {
+	return step(alert, breached(alert, value), rearmed(alert, value), now)
 }
 
-func sendWebhook(alert *store.Alert, value float64) string {
-	payload := map[string]interface{}{
-		"alert_id":     alert.ID,
-		"alert_name":   alert.Name,
-		"series_id":    alert.SeriesID,
-		"condition":    alert.Condition,
-		"threshold":    alert.Threshold,
-		"value":        value,
-		"triggered_at": time.Now().Format(time.RFC3339),
-		"message":      "Alert triggered",
+// step is advance for an observation already classified as beyond the threshold
+// and/or far enough back to re-arm
+func step(alert *store.Alert, beyond, rearm bool, now time.Time) bool {
+	if alert.State == store.AlertTriggered {
+		if rearm {
+			alert.State = store.AlertArmed
+			alert.BreachCount = 0
+		}
+		return false
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		util.ErrorLogger.Printf("Failed to marshal webhook payload: %v", err)
-		return "failed"
+	if !beyond {
+		alert.State = store.AlertArmed
+		alert.BreachCount = 0
+		return false
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(alert.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		util.ErrorLogger.Printf("Failed to send webhook: %v", err)
-		return "failed"
+	alert.State = store.AlertPending
+	alert.BreachCount++
+	if alert.BreachCount < alert.Confirmations {
+		return false
+	}
+
+	cooldown := time.Duration(alert.CooldownMinutes) * time.Minute
+	if alert.LastTriggeredAt != nil && now.Sub(*alert.LastTriggeredAt) < cooldown {
+		util.InfoLogger.Printf("Alert %d is cooling down (triggered %v ago), holding", alert.ID, now.Sub(*alert.LastTriggeredAt))
+		return false
+	}
+
+	alert.State = store.AlertTriggered
+	alert.BreachCount = 0
+	alert.LastTriggeredAt = &now
+	return true
+}
+
+// fire delivers a trigger to the alert's channels and records it in the alert's history
+func fire(db *store.Store, notifiers Notifiers, alert *store.Alert, seriesID string, value, threshold float64) {
+	util.InfoLogger.Printf("Alert triggered: %s (series: %s, value: %.2f, threshold: %.2f, condition: %s)",
+		alert.Name, seriesID, value, threshold, alert.Condition)
+
+	// Save to history first so webhook deliveries can reference the trigger
+	history := &store.AlertHistory{
+		AlertID:       alert.ID,
+		SeriesID:      seriesID,
+		Value:         value,
+		Threshold:     threshold,
+		WebhookStatus: store.DeliveryPending,
+	}
+	if err := db.SaveAlertHistory(history); err != nil {
+		util.ErrorLogger.Printf("Failed to save alert history: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		util.InfoLogger.Printf("Webhook sent successfully to %s (status: %d)", alert.WebhookURL, resp.StatusCode)
-		return "success"
+	deliveries := deliver(db, notifiers, Notification{
+		Alert:       alert,
+		HistoryID:   history.ID,
+		SeriesID:    seriesID,
+		Value:       value,
+		Threshold:   threshold,
+		TriggeredAt: time.Now(),
+	})
+	for _, d := range deliveries {
+		if d.Status != "success" {
+			util.ErrorLogger.Printf("Alert %d %s delivery to %s %s: %s", alert.ID, d.Channel, d.Target, d.Status, d.Error)
+		}
 	}
 
-	util.ErrorLogger.Printf("Webhook failed with status: %d", resp.StatusCode)
-	return "failed"
+	if history.ID != 0 {
+		if err := db.SetAlertHistoryDeliveries(history.ID, deliveryStatus(deliveries), deliveries); err != nil {
+			util.ErrorLogger.Printf("Failed to save alert deliveries: %v", err)
+		}
+	}
 }
@@ -0,0 +1,229 @@
+package alerts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func TestAdvanceCrossing(t *testing.T) {
+	util.InitLogger("info")
+	alert := &store.Alert{Condition: "above", Threshold: 25, RearmBand: 2, Confirmations: 1, State: store.AlertArmed}
+	now := time.Now()
+
+	steps := []struct {
+		value float64
+		fires bool
+		state string
+	}{
+		{24, false, store.AlertArmed},
+		{26, true, store.AlertTriggered},
+		{27, false, store.AlertTriggered}, // still above: no re-fire
+		{24, false, store.AlertTriggered}, // inside the re-arm band
+		{26, false, store.AlertTriggered},
+		{22.5, false, store.AlertArmed},
+		{26, true, store.AlertTriggered},
+	}
+	for i, step := range steps {
+		fired := advance(alert, step.value, now.Add(time.Duration(i)*24*time.Hour))
+		if fired != step.fires || alert.State != step.state {
+			t.Errorf("Step %d (%.1f): expected fires=%v state=%s, got fires=%v state=%s", i, step.value, step.fires, step.state, fired, alert.State)
+		}
+	}
+}
+
+func TestAdvanceConfirmationsAndCooldown(t *testing.T) {
+	util.InitLogger("info")
+	alert := &store.Alert{Condition: "below", Threshold: 100, Confirmations: 3, CooldownMinutes: 60, State: store.AlertArmed}
+	now := time.Now()
+
+	if advance(alert, 99, now) || advance(alert, 98, now) {
+		t.Fatal("Expected no trigger before 3 confirmations")
+	}
+	if advance(alert, 101, now) || alert.BreachCount != 0 {
+		t.Fatalf("Expected a recovery to reset confirmations, got %d", alert.BreachCount)
+	}
+	advance(alert, 99, now)
+	advance(alert, 99, now)
+	if !advance(alert, 99, now) {
+		t.Fatal("Expected trigger on the third consecutive observation")
+	}
+
+	advance(alert, 101, now)
+	if alert.State != store.AlertArmed {
+		t.Fatalf("Expected alert to re-arm, got %s", alert.State)
+	}
+	alert.Confirmations = 1
+	if advance(alert, 99, now.Add(10*time.Minute)) {
+		t.Error("Expected cooldown to hold the trigger")
+	}
+	if alert.State != store.AlertPending {
+		t.Errorf("Expected pending during cooldown, got %s", alert.State)
+	}
+	if !advance(alert, 99, now.Add(2*time.Hour)) {
+		t.Error("Expected trigger once the cooldown has passed")
+	}
+}
+
+func TestCheckSeriesAlertsFiresOncePerCrossing(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	save := func(date string, value float64) {
+		t.Helper()
+		if err := db.SavePoints("VIXCLS", []store.SeriesPoint{{Date: date, Value: value}}, time.Now()); err != nil {
+			t.Fatalf("Failed to save point: %v", err)
+		}
+	}
+	history := func(alertID int64) int {
+		t.Helper()
+		triggers, err := db.ListAlertHistory(alertID, 100)
+		if err != nil {
+			t.Fatalf("Failed to list alert triggers: %v", err)
+		}
+		return len(triggers)
+	}
+
+	// Created while already above the threshold: waits for a fresh crossing
+	save("2024-01-02", 30)
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS"})
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS"})
+	if n := history(alert.ID); n != 0 {
+		t.Fatalf("Expected no trigger without a crossing, got %d", n)
+	}
+
+	save("2024-01-03", 20)
+	save("2024-01-04", 28)
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS"})
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS"})
+	if n := history(alert.ID); n != 1 {
+		t.Fatalf("Expected 1 trigger for the crossing, got %d", n)
+	}
+
+	alerts, err := db.GetActiveAlerts()
+	if err != nil {
+		t.Fatalf("Failed to get alerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].State != store.AlertTriggered || alerts[0].LastObservedDate != "2024-01-04" || alerts[0].LastTriggeredAt == nil {
+		t.Errorf("Expected triggered state through 2024-01-04, got %+v", alerts)
+	}
+}
+
+func TestCheckExpressionAlert(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	save := func(series, date string, value float64) {
+		t.Helper()
+		if err := db.SavePoints(series, []store.SeriesPoint{{Date: date, Value: value}}, time.Now()); err != nil {
+			t.Fatalf("Failed to save point: %v", err)
+		}
+	}
+
+	save("VIXCLS", "2024-01-02", 20)
+	save("BAMLC0A4CBBB", "2024-01-02", 280)
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "Credit stress", SeriesID: "BAMLC0A4CBBB", Condition: "VIXCLS > 25 AND BAMLC0A4CBBB > 300", IsActive: true}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS", "BAMLC0A4CBBB"})
+
+	save("VIXCLS", "2024-01-03", 28)
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS", "BAMLC0A4CBBB"})
+	save("BAMLC0A4CBBB", "2024-01-04", 320)
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS", "BAMLC0A4CBBB"})
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS", "BAMLC0A4CBBB"})
+
+	triggers, err := db.ListAlertHistory(alert.ID, 100)
+	if err != nil {
+		t.Fatalf("Failed to list alert triggers: %v", err)
+	}
+	if len(triggers) != 1 || triggers[0].Value != 28 || triggers[0].Threshold != 25 {
+		t.Errorf("Expected one trigger reporting the first comparison, got %+v", triggers)
+	}
+}
+
+func TestCheckSeriesAlertsOnUpdates(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	db.Events().Subscribe("alerts", func(events []store.SeriesUpdated) {
+		var seriesIDs []string
+		for _, e := range events {
+			seriesIDs = append(seriesIDs, e.SeriesID)
+		}
+		CheckSeriesAlerts(db, nil, seriesIDs)
+	})
+	save := func(series, date string, value float64) {
+		t.Helper()
+		if err := db.SavePoints(series, []store.SeriesPoint{{Date: date, Value: value}}, time.Now()); err != nil {
+			t.Fatalf("Failed to save point: %v", err)
+		}
+		db.Events().Wait()
+	}
+
+	save("VIXCLS", "2024-01-02", 20)
+	save("BAMLC0A4CBBB", "2024-01-02", 280)
+	created := []*store.Alert{
+		{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true},
+		{UserEmail: "a@example.com", Name: "Credit stress", SeriesID: "VIXCLS", Condition: "VIXCLS > 25 AND BAMLC0A4CBBB > 300", IsActive: true},
+	}
+	for _, a := range created {
+		if err := db.CreateAlert(a); err != nil {
+			t.Fatalf("Failed to create alert: %v", err)
+		}
+	}
+	CheckSeriesAlerts(db, nil, []string{"VIXCLS", "BAMLC0A4CBBB"})
+
+	save("VIXCLS", "2024-01-03", 28)
+	save("BAMLC0A4CBBB", "2024-01-03", 320)
+
+	for _, a := range created {
+		triggers, err := db.ListAlertHistory(a.ID, 100)
+		if err != nil {
+			t.Fatalf("Failed to list alert triggers: %v", err)
+		}
+		if len(triggers) != 1 {
+			t.Errorf("Expected alert %q to fire once from series updates, got %+v", a.Name, triggers)
+		}
+	}
+
+	alerts, _ := db.GetActiveAlerts()
+	for _, a := range alerts {
+		if a.State != store.AlertTriggered {
+			t.Errorf("Expected alert %q triggered, got %s", a.Name, a.State)
+		}
+	}
+}
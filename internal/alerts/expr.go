@@ -0,0 +1,629 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"reserve-watch/internal/store"
+)
+
+// Expr is a parsed alert expression over one or more series, e.g.
+//
+//	VIXCLS > 25 AND BAMLC0A4CBBB > 300
+//	abs(pct_change(DTWEXBGS, 10)) >= 2
+//	spread(COFER_USD, COFER_EUR) < 40
+//
+// A bare series ID is its latest observation. Comparisons combine with AND, OR, NOT
+// and parentheses; values support + - * / and the functions in exprFuncs.
+type Expr struct {
+	source  string
+	root    boolExpr
+	history map[string]int // series ID -> observations needed, newest first
+}
+
+// ExprResult is an evaluated expression. Value and Threshold are the two sides of
+// the first comparison, reported in alert history and notifications.
+type ExprResult struct {
+	Match     bool
+	Value     float64
+	Threshold float64
+}
+
+// PointsFunc loads the newest n observations of a series, newest first
+type PointsFunc func(seriesID string, n int) ([]store.SeriesPoint, error)
+
+// maxWindow caps the observation count of windowed functions, so an expression can't
+// make every evaluation read a whole series
+const maxWindow = 1000
+
+// exprFunc describes a function callable from an expression. Windowed functions take
+// a series ID and an observation count; the others take values.
+type exprFunc struct {
+	windowed bool
+	args     int
+	minWin   int
+}
+
+var exprFuncs = map[string]exprFunc{
+	"pct_change": {windowed: true, minWin: 1}, // percent change vs window observations ago
+	"change":     {windowed: true, minWin: 1}, // absolute change vs window observations ago
+	"avg":        {windowed: true, minWin: 1}, // mean of the last window observations
+	"zscore":     {windowed: true, minWin: 2}, // latest vs the mean of the previous window, in standard deviations
+	"spread":     {args: 2},                   // a - b
+	"abs":        {args: 1},
+}
+
+// ParseExpr parses and validates an alert expression
+func ParseExpr(source string) (*Expr, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens, history: make(map[string]int)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+
+	root, ok := node.(boolExpr)
+	if !ok {
+		return nil, fmt.Errorf("expression must be a condition, e.g. %s > 25", source)
+	}
+	if len(p.history) == 0 {
+		return nil, fmt.Errorf("expression must reference at least one series")
+	}
+	return &Expr{source: strings.TrimSpace(source), root: root, history: p.history}, nil
+}
+
+// String returns the expression as written
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Series returns the series IDs the expression reads, in alphabetical order
+func (e *Expr) Series() []string {
+	ids := make([]string, 0, len(e.history))
+	for id := range e.history {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Eval evaluates the expression against the latest data
+func (e *Expr) Eval(load PointsFunc) (ExprResult, error) {
+	env := &exprEnv{points: make(map[string][]store.SeriesPoint)}
+	for id, n := range e.history {
+		points, err := load(id, n)
+		if err != nil {
+			return ExprResult{}, err
+		}
+		if len(points) < n {
+			return ExprResult{}, fmt.Errorf("%s has %d observations, expression needs %d", id, len(points), n)
+		}
+		env.points[id] = points
+	}
+
+	match, err := e.root.eval(env)
+	if err != nil {
+		return ExprResult{}, err
+	}
+	return ExprResult{Match: match, Value: env.value, Threshold: env.threshold}, nil
+}
+
+type exprEnv struct {
+	points           map[string][]store.SeriesPoint
+	compared         bool
+	value, threshold float64
+}
+
+type boolExpr interface {
+	eval(env *exprEnv) (bool, error)
+}
+
+type numExpr interface {
+	value(env *exprEnv) (float64, error)
+}
+
+type logicalExpr struct {
+	and         bool
+	left, right boolExpr
+}
+
+func (x logicalExpr) eval(env *exprEnv) (bool, error) {
+	l, err := x.left.eval(env)
+	if err != nil {
+		return false, err
+	}
+	r, err := x.right.eval(env)
+	if err != nil {
+		return false, err
+	}
+	if x.and {
+		return l && r, nil
+	}
+	return l || r, nil
+}
+
+type notExpr struct {
+	x boolExpr
+}
+
+func (x notExpr) eval(env *exprEnv) (bool, error) {
+	v, err := x.x.eval(env)
+	return !v, err
+}
+
+type compareExpr struct {
+	op          string
+	left, right numExpr
+}
+
+func (x compareExpr) eval(env *exprEnv) (bool, error) {
+	l, err := x.left.value(env)
+	if err != nil {
+		return false, err
+	}
+	r, err := x.right.value(env)
+	if err != nil {
+		return false, err
+	}
+	if !env.compared {
+		env.compared, env.value, env.threshold = true, l, r
+	}
+
+	switch x.op {
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case "==":
+		return l == r, nil
+	}
+	return l != r, nil
+}
+
+type arithExpr struct {
+	op          byte
+	left, right numExpr
+}
+
+func (x arithExpr) value(env *exprEnv) (float64, error) {
+	l, err := x.left.value(env)
+	if err != nil {
+		return 0, err
+	}
+	r, err := x.right.value(env)
+	if err != nil {
+		return 0, err
+	}
+
+	switch x.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	}
+	if r == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return l / r, nil
+}
+
+type negExpr struct {
+	x numExpr
+}
+
+func (x negExpr) value(env *exprEnv) (float64, error) {
+	v, err := x.x.value(env)
+	return -v, err
+}
+
+type numberLit float64
+
+func (x numberLit) value(env *exprEnv) (float64, error) {
+	return float64(x), nil
+}
+
+type seriesRef string
+
+func (x seriesRef) value(env *exprEnv) (float64, error) {
+	return env.points[string(x)][0].Value, nil
+}
+
+type windowCall struct {
+	fn       string
+	seriesID string
+	window   int
+}
+
+func (x windowCall) value(env *exprEnv) (float64, error) {
+	points := env.points[x.seriesID]
+	latest := points[0].Value
+
+	switch x.fn {
+	case "pct_change":
+		base := points[x.window].Value
+		if base == 0 {
+			return 0, fmt.Errorf("pct_change(%s, %d): base value is zero", x.seriesID, x.window)
+		}
+		return (latest - base) / math.Abs(base) * 100, nil
+	case "change":
+		return latest - points[x.window].Value, nil
+	case "avg":
+		return mean(points[:x.window]), nil
+	}
+
+	// zscore
+	previous := points[1 : x.window+1]
+	m := mean(previous)
+	var variance float64
+	for _, p := range previous {
+		variance += (p.Value - m) * (p.Value - m)
+	}
+	stddev := math.Sqrt(variance / float64(len(previous)-1))
+	if stddev == 0 {
+		return 0, fmt.Errorf("zscore(%s, %d): no variation in window", x.seriesID, x.window)
+	}
+	return (latest - m) / stddev, nil
+}
+
+func mean(points []store.SeriesPoint) float64 {
+	var sum float64
+	for _, p := range points {
+		sum += p.Value
+	}
+	return sum / float64(len(points))
+}
+
+type valueCall struct {
+	fn   string
+	args []numExpr
+}
+
+func (x valueCall) value(env *exprEnv) (float64, error) {
+	args := make([]float64, len(x.args))
+	for i, a := range x.args {
+		v, err := a.value(env)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+
+	if x.fn == "spread" {
+		return args[0] - args[1], nil
+	}
+	return math.Abs(args[0]), nil
+}
+
+// Tokenizer
+
+const (
+	tokEOF = iota
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, string(runes[start:i]), start + 1})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, string(runes[start:i]), start + 1})
+		case strings.ContainsRune("<>=!", c):
+			op := string(c)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("unexpected %q at position %d (use == or !=)", op, i+1)
+			}
+			tokens = append(tokens, token{tokOp, op, i + 1})
+			i += len(op)
+		case strings.ContainsRune("()+-*/,", c):
+			tokens = append(tokens, token{tokOp, string(c), i + 1})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", c, i+1)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, text: "end of expression", pos: len(runes) + 1}), nil
+}
+
+// Parser: or -> and (OR and)*, and -> not (AND not)*, not -> NOT not | comparison,
+// comparison -> sum (op sum)?, sum -> term ((+|-) term)*, term -> unary ((*|/) unary)*
+
+type exprParser struct {
+	tokens  []token
+	pos     int
+	history map[string]int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == tokIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expect(op string) error {
+	if tok := p.next(); tok.kind != tokOp || tok.text != op {
+		return fmt.Errorf("expected %q at position %d, got %q", op, tok.pos, tok.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (interface{}, error) {
+	return p.parseLogical("OR", p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (interface{}, error) {
+	return p.parseLogical("AND", p.parseNot)
+}
+
+func (p *exprParser) parseLogical(word string, operand func() (interface{}, error)) (interface{}, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := p.peek().pos
+		if !p.keyword(word) {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(boolExpr)
+		r, rok := right.(boolExpr)
+		if !lok || !rok {
+			return nil, fmt.Errorf("%s at position %d needs a comparison on both sides", word, pos)
+		}
+		left = logicalExpr{and: word == "AND", left: l, right: r}
+	}
+}
+
+func (p *exprParser) parseNot() (interface{}, error) {
+	pos := p.peek().pos
+	if !p.keyword("NOT") {
+		return p.parseComparison()
+	}
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	b, ok := x.(boolExpr)
+	if !ok {
+		return nil, fmt.Errorf("NOT at position %d needs a comparison", pos)
+	}
+	return notExpr{b}, nil
+}
+
+func (p *exprParser) parseComparison() (interface{}, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	switch tok.text {
+	case ">", ">=", "<", "<=", "==", "!=":
+	default:
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	l, lok := left.(numExpr)
+	r, rok := right.(numExpr)
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s at position %d compares values, not conditions", tok.text, tok.pos)
+	}
+	return compareExpr{op: tok.text, left: l, right: r}, nil
+}
+
+func (p *exprParser) parseSum() (interface{}, error) {
+	return p.parseArith("+-", p.parseTerm)
+}
+
+func (p *exprParser) parseTerm() (interface{}, error) {
+	return p.parseArith("*/", p.parseUnary)
+}
+
+func (p *exprParser) parseArith(ops string, operand func() (interface{}, error)) (interface{}, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || len(tok.text) != 1 || !strings.Contains(ops, tok.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(numExpr)
+		r, rok := right.(numExpr)
+		if !lok || !rok {
+			return nil, fmt.Errorf("%s at position %d needs values on both sides", tok.text, tok.pos)
+		}
+		left = arithExpr{op: tok.text[0], left: l, right: r}
+	}
+}
+
+func (p *exprParser) parseUnary() (interface{}, error) {
+	tok := p.peek()
+	if tok.kind == tokOp && tok.text == "-" {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		n, ok := x.(numExpr)
+		if !ok {
+			return nil, fmt.Errorf("- at position %d needs a value", tok.pos)
+		}
+		return negExpr{n}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (interface{}, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return numberLit(v), nil
+
+	case tokIdent:
+		if next := p.peek(); next.kind == tokOp && next.text == "(" {
+			return p.parseCall(tok)
+		}
+		switch strings.ToUpper(tok.text) {
+		case "AND", "OR", "NOT":
+			return nil, fmt.Errorf("unexpected %s at position %d", tok.text, tok.pos)
+		}
+		p.need(tok.text, 1)
+		return seriesRef(tok.text), nil
+
+	case tokOp:
+		if tok.text == "(" {
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseCall(name token) (interface{}, error) {
+	fn := strings.ToLower(name.text)
+	spec, ok := exprFuncs[fn]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s at position %d", name.text, name.pos)
+	}
+	p.next() // (
+
+	if spec.windowed {
+		series := p.next()
+		if series.kind != tokIdent {
+			return nil, fmt.Errorf("%s at position %d needs a series ID first, got %q", fn, name.pos, series.text)
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		win := p.next()
+		window, err := strconv.Atoi(win.text)
+		if win.kind != tokNumber || err != nil || window < spec.minWin || window > maxWindow {
+			return nil, fmt.Errorf("%s at position %d needs a window of %d to %d observations, got %q", fn, name.pos, spec.minWin, maxWindow, win.text)
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		n := window + 1
+		if fn == "avg" {
+			n = window
+		}
+		p.need(series.text, n)
+		return windowCall{fn: fn, seriesID: series.text, window: window}, nil
+	}
+
+	var args []numExpr
+	for {
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		n, ok := x.(numExpr)
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d takes values, not conditions", fn, name.pos)
+		}
+		args = append(args, n)
+		if tok := p.peek(); tok.kind == tokOp && tok.text == "," {
+			p.next()
+			continue
+		}
+		break
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) != spec.args {
+		return nil, fmt.Errorf("%s at position %d takes %d arguments, got %d", fn, name.pos, spec.args, len(args))
+	}
+	return valueCall{fn: fn, args: args}, nil
+}
+
+// need records that the expression reads the newest n observations of a series
+func (p *exprParser) need(seriesID string, n int) {
+	if n > p.history[seriesID] {
+		p.history[seriesID] = n
+	}
+}
@@ -0,0 +1,120 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"reserve-watch/internal/store"
+)
+
+// fakePoints serves newest-first points from fixed series
+func fakePoints(data map[string][]float64) PointsFunc {
+	return func(seriesID string, n int) ([]store.SeriesPoint, error) {
+		values, ok := data[seriesID]
+		if !ok {
+			return nil, fmt.Errorf("no series %s", seriesID)
+		}
+		var points []store.SeriesPoint
+		for i := 0; i < n && i < len(values); i++ {
+			points = append(points, store.SeriesPoint{Value: values[i]})
+		}
+		return points, nil
+	}
+}
+
+func TestParseExprSeries(t *testing.T) {
+	expr, err := ParseExpr("VIXCLS > 25 AND BAMLC0A4CBBB > 300")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	series := expr.Series()
+	if len(series) != 2 || series[0] != "BAMLC0A4CBBB" || series[1] != "VIXCLS" {
+		t.Errorf("Expected both series, got %v", series)
+	}
+	if expr.history["VIXCLS"] != 1 {
+		t.Errorf("Expected 1 VIX observation needed, got %d", expr.history["VIXCLS"])
+	}
+
+	expr, err = ParseExpr("pct_change(DTWEXBGS, 10) > 2 or zscore(DTWEXBGS, 20) > 3")
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if expr.history["DTWEXBGS"] != 21 {
+		t.Errorf("Expected 21 observations for the z-score window, got %d", expr.history["DTWEXBGS"])
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	invalid := []string{
+		"",
+		"VIXCLS",
+		"VIXCLS > ",
+		"VIXCLS = 25",
+		"VIXCLS > 25 AND 3",
+		"(VIXCLS > 25) + 1 > 2",
+		"pct_change(DTWEXBGS) > 2",
+		"pct_change(DTWEXBGS, 0) > 2",
+		"zscore(DTWEXBGS, 1) > 2",
+		"avg(VIXCLS, 99999999999) > 20",
+		"pct_change(DTWEXBGS, 1001) > 2",
+		"spread(COFER_USD) < 40",
+		"median(VIXCLS, 5) > 20",
+		"VIXCLS > 25 $",
+		"(VIXCLS > 25",
+		"1 > 0",
+		"2 * 3 >= 5",
+	}
+	for _, source := range invalid {
+		if _, err := ParseExpr(source); err == nil {
+			t.Errorf("Expected %q to be rejected", source)
+		}
+	}
+
+	if _, err := ParseExpr("1 > 0"); err == nil || err.Error() != "expression must reference at least one series" {
+		t.Errorf("Expected a constant expression to be rejected for reading no series, got %v", err)
+	}
+}
+
+func TestExprEval(t *testing.T) {
+	load := fakePoints(map[string][]float64{
+		"VIXCLS":       {27, 22, 18},
+		"BAMLC0A4CBBB": {310, 290},
+		"DTWEXBGS":     {102.5, 101, 100.8, 100},
+		"COFER_USD":    {57.7},
+		"COFER_EUR":    {19.8},
+	})
+
+	tests := []struct {
+		source string
+		match  bool
+		value  float64
+	}{
+		{"VIXCLS > 25 AND BAMLC0A4CBBB > 300", true, 27},
+		{"VIXCLS > 30 OR BAMLC0A4CBBB > 300", true, 27},
+		{"NOT VIXCLS > 25", false, 27},
+		{"pct_change(DTWEXBGS, 3) > 2", true, 2.5},
+		{"abs(change(DTWEXBGS, 1)) >= 1.5", true, 1.5},
+		{"spread(COFER_USD, COFER_EUR) < 40", true, 37.9},
+		{"avg(VIXCLS, 3) > 22", true, 67.0 / 3},
+		{"(VIXCLS - BAMLC0A4CBBB / 10) * 2 <= -8", true, -8},
+	}
+	for _, tt := range tests {
+		expr, err := ParseExpr(tt.source)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", tt.source, err)
+		}
+		result, err := expr.Eval(load)
+		if err != nil {
+			t.Fatalf("Failed to evaluate %q: %v", tt.source, err)
+		}
+		if result.Match != tt.match || math.Abs(result.Value-tt.value) > 1e-9 {
+			t.Errorf("%s: expected match=%v value=%.4f, got %+v", tt.source, tt.match, tt.value, result)
+		}
+	}
+
+	expr, _ := ParseExpr("pct_change(VIXCLS, 5) > 10")
+	if _, err := expr.Eval(load); err == nil {
+		t.Error("Expected an error without enough history")
+	}
+}
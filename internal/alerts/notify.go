@@ -0,0 +1,393 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"reserve-watch/internal/config"
+	"reserve-watch/internal/store"
+)
+
+// Channel types an alert can be delivered to
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+	ChannelTeams   = "teams"
+	ChannelSMS     = "sms"
+)
+
+const alertsFeedURL = "https://reserve.watch/alerts-feed"
+
+// Notification is one alert trigger to deliver
+type Notification struct {
+	Alert       *store.Alert
+	HistoryID   int64 // alert_history row of the trigger
+	SeriesID    string
+	Value       float64
+	Threshold   float64
+	TriggeredAt time.Time
+	Test        bool // sent from the test endpoint rather than a real trigger
+}
+
+// Title is a one-line headline, e.g. "VIX spike: VIXCLS 28.00 (above 25.00)"
+func (n Notification) Title() string {
+	return fmt.Sprintf("%s: %s", n.name(), n.Detail())
+}
+
+// name is the alert name, marked when this is a test
+func (n Notification) name() string {
+	if n.Test {
+		return "[Test] " + n.Alert.Name
+	}
+	return n.Alert.Name
+}
+
+// Detail describes what crossed
+func (n Notification) Detail() string {
+	if IsExpression(n.Alert.Condition) {
+		return fmt.Sprintf("%s (%.2f vs %.2f)", n.Alert.Condition, n.Value, n.Threshold)
+	}
+	return fmt.Sprintf("%s %.2f (%s %.2f)", n.SeriesID, n.Value, n.Alert.Condition, n.Threshold)
+}
+
+// Notifier delivers a notification to one channel target
+type Notifier interface {
+	Notify(target string, n Notification) error
+}
+
+// Notifiers maps channel types to their senders. Channels without a notifier,
+// e.g. email with neither SendGrid nor SMTP configured, are skipped.
+type Notifiers map[string]Notifier
+
+// NewNotifiers builds the notifiers the configuration supports
+func NewNotifiers(cfg *config.Config) Notifiers {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	notifiers := Notifiers{
+		ChannelWebhook: &WebhookNotifier{client: client},
+		ChannelSlack:   &SlackNotifier{client: client},
+		ChannelDiscord: &DiscordNotifier{client: client},
+		ChannelTeams:   &TeamsNotifier{client: client},
+	}
+
+	from := mail.Address{Name: cfg.SendGridFromName, Address: cfg.SendGridFromEmail}
+	switch {
+	case cfg.SendGridAPIKey != "":
+		notifiers[ChannelEmail] = &SendGridNotifier{apiKey: cfg.SendGridAPIKey, from: from, client: client}
+	case cfg.SMTPHost != "":
+		notifiers[ChannelEmail] = &SMTPNotifier{host: cfg.SMTPHost, port: cfg.SMTPPort, username: cfg.SMTPUsername, password: cfg.SMTPPassword, from: from}
+	}
+
+	if cfg.SMSGatewayURL != "" {
+		notifiers[ChannelSMS] = &SMSGatewayNotifier{url: cfg.SMSGatewayURL, token: cfg.SMSGatewayToken, client: client}
+	}
+
+	return notifiers
+}
+
+// Channels returns where an alert is delivered: its channels plus the legacy webhook URL
+func Channels(alert *store.Alert) []store.AlertChannel {
+	channels := append([]store.AlertChannel(nil), alert.Channels...)
+	if alert.WebhookURL != "" {
+		channels = append(channels, store.AlertChannel{Type: ChannelWebhook, Target: alert.WebhookURL})
+	}
+	return channels
+}
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+// ValidateChannel checks a channel's type and target before it is saved
+func ValidateChannel(ch store.AlertChannel) error {
+	switch ch.Type {
+	case ChannelEmail:
+		if _, err := mail.ParseAddress(ch.Target); err != nil {
+			return fmt.Errorf("email channel needs an email address, got %q", ch.Target)
+		}
+	case ChannelSMS:
+		if !phonePattern.MatchString(ch.Target) {
+			return fmt.Errorf("sms channel needs a phone number like +15551234567, got %q", ch.Target)
+		}
+	case ChannelWebhook, ChannelSlack, ChannelDiscord, ChannelTeams:
+		u, err := url.Parse(ch.Target)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%s channel needs an http(s) URL, got %q", ch.Type, ch.Target)
+		}
+	default:
+		return fmt.Errorf("unknown channel type %q", ch.Type)
+	}
+	return nil
+}
+
+// deliver sends a notification to every channel of the alert. With a store, webhooks
+// go through the logged, retried outbox instead of a single attempt.
+func deliver(db *store.Store, notifiers Notifiers, n Notification) []store.ChannelDelivery {
+	var deliveries []store.ChannelDelivery
+	for _, ch := range Channels(n.Alert) {
+		if ch.Type == ChannelWebhook && db != nil {
+			deliveries = append(deliveries, queueWebhook(db, n, ch.Target))
+			continue
+		}
+
+		d := store.ChannelDelivery{Channel: ch.Type, Target: ch.Target, Status: "success"}
+
+		notifier, ok := notifiers[ch.Type]
+		if !ok {
+			d.Status, d.Error = "skipped", ch.Type+" delivery is not configured"
+		} else if err := notifier.Notify(ch.Target, n); err != nil {
+			d.Status, d.Error = "failed", err.Error()
+		}
+
+		deliveries = append(deliveries, d)
+	}
+	return deliveries
+}
+
+// SendTest delivers a test notification to every channel of an alert using the
+// current value of what it watches. Nothing is recorded in the alert's history and
+// webhooks get a single attempt.
+func SendTest(db *store.Store, notifiers Notifiers, alert *store.Alert) ([]store.ChannelDelivery, error) {
+	n := Notification{Alert: alert, SeriesID: alert.SeriesID, Threshold: alert.Threshold, TriggeredAt: time.Now(), Test: true}
+
+	if IsExpression(alert.Condition) {
+		expr, err := ParseExpr(alert.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", alert.Condition, err)
+		}
+		result, err := expr.Eval(db.GetRecentPoints)
+		if err != nil {
+			return nil, err
+		}
+		n.Value, n.Threshold = result.Value, result.Threshold
+	} else {
+		points, err := db.GetRecentPoints(alert.SeriesID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(points) == 0 {
+			return nil, fmt.Errorf("no data for series %s", alert.SeriesID)
+		}
+		n.Value = points[0].Value
+	}
+
+	return deliver(nil, notifiers, n), nil
+}
+
+// deliveryStatus summarises per-channel outcomes for alert_history.webhook_status:
+// 'failed' if any channel failed or was dead-lettered, else 'pending' while a webhook
+// awaits a retry, else 'success' or 'skipped'
+func deliveryStatus(deliveries []store.ChannelDelivery) string {
+	status := "skipped"
+	for _, d := range deliveries {
+		switch d.Status {
+		case "failed", store.DeliveryDead:
+			return "failed"
+		case store.DeliveryPending:
+			status = store.DeliveryPending
+		case "success":
+			if status == "skipped" {
+				status = "success"
+			}
+		}
+	}
+	return status
+}
+
+// postJSON POSTs a JSON body and treats any non-2xx response as a failure
+func postJSON(client *http.Client, target string, body interface{}, header http.Header) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// WebhookNotifier POSTs the generic JSON payload, signed with the alert's secret,
+// in a single attempt. Triggers normally go through the outbox instead.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func (w *WebhookNotifier) Notify(target string, n Notification) error {
+	payload, err := webhookPayload(n)
+	if err != nil {
+		return err
+	}
+	_, err = sendWebhook(w.client, target, n.Alert.WebhookSecret, 0, payload)
+	return err
+}
+
+// SlackNotifier posts Block Kit messages to a Slack incoming webhook
+type SlackNotifier struct {
+	client *http.Client
+}
+
+func (s *SlackNotifier) Notify(target string, n Notification) error {
+	return postJSON(s.client, target, map[string]interface{}{
+		"text": "🔔 " + n.Title(),
+		"blocks": []map[string]interface{}{
+			{"type": "header", "text": map[string]string{"type": "plain_text", "text": "🔔 " + n.name()}},
+			{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": n.Detail()}},
+			{"type": "context", "elements": []map[string]string{
+				{"type": "mrkdwn", "text": fmt.Sprintf("Triggered %s • <%s|Reserve Watch alerts>", n.TriggeredAt.UTC().Format("Jan 2, 15:04 UTC"), alertsFeedURL)},
+			}},
+		},
+	}, nil)
+}
+
+// DiscordNotifier posts embeds to a Discord webhook
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+func (d *DiscordNotifier) Notify(target string, n Notification) error {
+	return postJSON(d.client, target, map[string]interface{}{
+		"embeds": []map[string]interface{}{{
+			"title":       "🔔 " + n.name(),
+			"description": n.Detail(),
+			"url":         alertsFeedURL,
+			"color":       0xE74C3C,
+			"timestamp":   n.TriggeredAt.UTC().Format(time.RFC3339),
+			"fields": []map[string]interface{}{
+				{"name": "Value", "value": strconv.FormatFloat(n.Value, 'f', 2, 64), "inline": true},
+				{"name": "Threshold", "value": strconv.FormatFloat(n.Threshold, 'f', 2, 64), "inline": true},
+			},
+		}},
+	}, nil)
+}
+
+// TeamsNotifier posts Adaptive Cards to a Microsoft Teams workflow webhook
+type TeamsNotifier struct {
+	client *http.Client
+}
+
+func (t *TeamsNotifier) Notify(target string, n Notification) error {
+	return postJSON(t.client, target, map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body": []map[string]interface{}{
+					{"type": "TextBlock", "size": "Medium", "weight": "Bolder", "text": "🔔 " + n.name()},
+					{"type": "TextBlock", "wrap": true, "text": n.Detail()},
+					{"type": "FactSet", "facts": []map[string]string{
+						{"title": "Value", "value": strconv.FormatFloat(n.Value, 'f', 2, 64)},
+						{"title": "Threshold", "value": strconv.FormatFloat(n.Threshold, 'f', 2, 64)},
+						{"title": "Triggered", "value": n.TriggeredAt.UTC().Format("Jan 2, 2006 15:04 UTC")},
+					}},
+				},
+				"actions": []map[string]string{
+					{"type": "Action.OpenUrl", "title": "View alerts", "url": alertsFeedURL},
+				},
+			},
+		}},
+	}, nil)
+}
+
+// SMSGatewayNotifier POSTs {"to", "message"} to a generic SMS gateway
+type SMSGatewayNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *SMSGatewayNotifier) Notify(target string, n Notification) error {
+	header := http.Header{}
+	if s.token != "" {
+		header.Set("Authorization", "Bearer "+s.token)
+	}
+	return postJSON(s.client, s.url, map[string]string{
+		"to":      target,
+		"message": "Reserve Watch: " + n.Title(),
+	}, header)
+}
+
+// emailBody renders the plain-text alert email
+func emailBody(n Notification) string {
+	intro := "Your Reserve Watch alert \"%s\" was triggered."
+	if n.Test {
+		intro = "This is a test of your Reserve Watch alert \"%s\". Current values:"
+	}
+	return fmt.Sprintf(intro+"\n\n%s\nTriggered: %s\n\nRecent alerts: %s\n",
+		n.Alert.Name, n.Detail(), n.TriggeredAt.UTC().Format("Jan 2, 2006 15:04 UTC"), alertsFeedURL)
+}
+
+// SendGridNotifier sends alert emails through the SendGrid API
+type SendGridNotifier struct {
+	apiKey string
+	from   mail.Address
+	client *http.Client
+}
+
+func (s *SendGridNotifier) Notify(target string, n Notification) error {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+s.apiKey)
+	return postJSON(s.client, "https://api.sendgrid.com/v3/mail/send", map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": []map[string]string{{"email": target}}},
+		},
+		"from":    map[string]string{"email": s.from.Address, "name": s.from.Name},
+		"subject": "🔔 " + n.Title(),
+		"content": []map[string]string{{"type": "text/plain", "value": emailBody(n)}},
+	}, header)
+}
+
+// SMTPNotifier sends alert emails through an SMTP server
+type SMTPNotifier struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     mail.Address
+}
+
+func (s *SMTPNotifier) Notify(target string, n Notification) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	subject := strings.NewReplacer("\r", "", "\n", " ").Replace("Reserve Watch alert: " + n.Title())
+	msg := "From: " + s.from.String() + "\r\n" +
+		"To: " + target + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + emailBody(n)
+
+	addr := s.host + ":" + strconv.Itoa(s.port)
+	return smtp.SendMail(addr, auth, s.from.Address, []string{target}, []byte(msg))
+}
@@ -0,0 +1,149 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func TestDeliverPerChannel(t *testing.T) {
+	var received []map[string]interface{}
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body)
+	}))
+	defer ok.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	client := ok.Client()
+	notifiers := Notifiers{
+		ChannelWebhook: &WebhookNotifier{client: client},
+		ChannelSlack:   &SlackNotifier{client: client},
+		ChannelDiscord: &DiscordNotifier{client: client},
+		ChannelTeams:   &TeamsNotifier{client: client},
+	}
+
+	alert := &store.Alert{
+		ID: 7, Name: "VIX spike", Condition: "above", Threshold: 25, WebhookURL: ok.URL,
+		Channels: []store.AlertChannel{
+			{Type: ChannelSlack, Target: ok.URL},
+			{Type: ChannelDiscord, Target: ok.URL},
+			{Type: ChannelTeams, Target: broken.URL},
+			{Type: ChannelEmail, Target: "a@example.com"},
+		},
+	}
+	deliveries := deliver(nil, notifiers, Notification{Alert: alert, SeriesID: "VIXCLS", Value: 28, Threshold: 25, TriggeredAt: time.Now()})
+
+	want := []string{"success", "success", "failed", "skipped", "success"}
+	if len(deliveries) != len(want) {
+		t.Fatalf("Expected %d deliveries, got %+v", len(want), deliveries)
+	}
+	for i, status := range want {
+		if deliveries[i].Status != status {
+			t.Errorf("Expected %s delivery %s, got %+v", deliveries[i].Channel, status, deliveries[i])
+		}
+	}
+	if deliveryStatus(deliveries) != "failed" {
+		t.Errorf("Expected summary 'failed', got %s", deliveryStatus(deliveries))
+	}
+
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 successful posts, got %d", len(received))
+	}
+	if _, ok := received[0]["blocks"]; !ok {
+		t.Errorf("Expected Slack blocks, got %v", received[0])
+	}
+	if _, ok := received[1]["embeds"]; !ok {
+		t.Errorf("Expected Discord embeds, got %v", received[1])
+	}
+	if received[2]["series_id"] != "VIXCLS" || received[2]["value"] != 28.0 {
+		t.Errorf("Expected generic webhook payload, got %v", received[2])
+	}
+}
+
+func TestSendTest(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	if err := db.SavePoints("VIXCLS", []store.SeriesPoint{{Date: "2024-01-02", Value: 18}}, time.Now()); err != nil {
+		t.Fatalf("Failed to save point: %v", err)
+	}
+
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true, WebhookURL: server.URL}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+
+	deliveries, err := SendTest(db, Notifiers{ChannelWebhook: &WebhookNotifier{client: server.Client()}}, alert)
+	if err != nil {
+		t.Fatalf("Failed to send test: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != "success" {
+		t.Fatalf("Expected one successful delivery, got %+v", deliveries)
+	}
+	if received["test"] != true || received["value"] != 18.0 || received["message"] != "Test notification" {
+		t.Errorf("Expected test payload with the current value, got %v", received)
+	}
+
+	history, err := db.ListAlertHistory(alert.ID, 10)
+	if err != nil {
+		t.Fatalf("Failed to list alert history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("Expected test not to be recorded, got %d triggers", len(history))
+	}
+
+	empty := &store.Alert{Name: "DXY", SeriesID: "DTWEXBGS", Condition: "above", Threshold: 110, WebhookURL: server.URL}
+	if _, err := SendTest(db, nil, empty); err == nil {
+		t.Error("Expected error for a series without data")
+	}
+}
+
+func TestValidateChannel(t *testing.T) {
+	valid := []store.AlertChannel{
+		{Type: ChannelEmail, Target: "ops@example.com"},
+		{Type: ChannelSMS, Target: "+15551234567"},
+		{Type: ChannelSlack, Target: "https://hooks.slack.com/services/T/B/X"},
+	}
+	for _, ch := range valid {
+		if err := ValidateChannel(ch); err != nil {
+			t.Errorf("Expected %+v to be valid: %v", ch, err)
+		}
+	}
+
+	invalid := []store.AlertChannel{
+		{Type: ChannelEmail, Target: "not-an-email"},
+		{Type: ChannelSMS, Target: "call me"},
+		{Type: ChannelTeams, Target: "ftp://example.com"},
+		{Type: "pager", Target: "123"},
+	}
+	for _, ch := range invalid {
+		if err := ValidateChannel(ch); err == nil {
+			t.Errorf("Expected %+v to be rejected", ch)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package alerts
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+// Webhook request headers. The signature is "sha256=" + hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the alert's webhook secret.
+const (
+	SignatureHeader = "X-Reserve-Watch-Signature"
+	TimestampHeader = "X-Reserve-Watch-Timestamp"
+	DeliveryHeader  = "X-Reserve-Watch-Delivery"
+)
+
+// MaxDeliveryAttempts is how many times a webhook is tried before it is dead-lettered
+const MaxDeliveryAttempts = 8
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// deliveryLease is how long a delivery stays claimed by an attempt. It comfortably
+// outlasts webhookClient's timeout, so only attempts lost to a crash are re-claimed.
+const deliveryLease = 2 * time.Minute
+
+// maxRetriesPerPass caps how many deliveries one RetryDeliveries call attempts
+const maxRetriesPerPass = 100
+
+// Sign returns the signature header value for a body sent at timestamp (Unix seconds)
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryBackoff is the wait after a failed attempt: 1m, 2m, 4m, ... capped at 6h
+func retryBackoff(attempts int) time.Duration {
+	if attempts > 10 {
+		return 6 * time.Hour
+	}
+	backoff := time.Minute << uint(attempts-1)
+	if backoff > 6*time.Hour {
+		return 6 * time.Hour
+	}
+	return backoff
+}
+
+// webhookPayload is the generic webhook body. trigger_id stays the same across
+// retries and replays so receivers can de-duplicate.
+func webhookPayload(n Notification) ([]byte, error) {
+	message := "Alert triggered"
+	if n.Test {
+		message = "Test notification"
+	}
+	return json.Marshal(map[string]interface{}{
+		"trigger_id":   n.HistoryID,
+		"alert_id":     n.Alert.ID,
+		"alert_name":   n.Alert.Name,
+		"series_id":    n.SeriesID,
+		"condition":    n.Alert.Condition,
+		"threshold":    n.Threshold,
+		"value":        n.Value,
+		"triggered_at": n.TriggeredAt.Format(time.RFC3339),
+		"message":      message,
+		"test":         n.Test,
+	})
+}
+
+// sendWebhook POSTs a signed payload and returns the response status code
+func sendWebhook(client *http.Client, target, secret string, deliveryID int64, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, payload))
+	if deliveryID != 0 {
+		req.Header.Set(DeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// queueWebhook logs a webhook delivery for a trigger and makes the first attempt.
+// A failed attempt stays in the outbox for RetryDeliveries.
+func queueWebhook(db *store.Store, n Notification, target string) store.ChannelDelivery {
+	payload, err := webhookPayload(n)
+	if err != nil {
+		return store.ChannelDelivery{Channel: ChannelWebhook, Target: target, Status: "failed", Error: err.Error()}
+	}
+
+	d := &store.WebhookDelivery{
+		AlertID:   n.Alert.ID,
+		HistoryID: n.HistoryID,
+		Target:    target,
+		Payload:   string(payload),
+		Secret:    n.Alert.WebhookSecret,
+	}
+	if err := db.CreateWebhookDelivery(d, deliveryLease); err != nil {
+		return store.ChannelDelivery{Channel: ChannelWebhook, Target: target, Status: "failed", Error: err.Error()}
+	}
+
+	if err := attempt(db, d); err != nil {
+		util.ErrorLogger.Printf("Failed to record webhook delivery %d: %v", d.ID, err)
+	}
+	return channelDelivery(d)
+}
+
+// attempt makes one delivery attempt and records its outcome
+func attempt(db *store.Store, d *store.WebhookDelivery) error {
+	d.Attempts++
+	code, err := sendWebhook(webhookClient, d.Target, d.Secret, d.ID, []byte(d.Payload))
+	d.LastStatusCode = code
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		d.Status, d.LastError = store.DeliverySuccess, ""
+		d.DeliveredAt, d.NextAttemptAt = &now, nil
+	case d.Attempts >= MaxDeliveryAttempts:
+		d.Status, d.LastError = store.DeliveryDead, err.Error()
+		d.NextAttemptAt = nil
+		util.ErrorLogger.Printf("Webhook delivery %d to %s dead after %d attempts: %v", d.ID, d.Target, d.Attempts, err)
+	default:
+		next := now.Add(retryBackoff(d.Attempts))
+		d.Status, d.LastError = store.DeliveryPending, err.Error()
+		d.NextAttemptAt = &next
+		util.InfoLogger.Printf("Webhook delivery %d to %s failed (attempt %d), retrying at %s: %v",
+			d.ID, d.Target, d.Attempts, next.UTC().Format(time.RFC3339), err)
+	}
+
+	return db.UpdateWebhookDelivery(d)
+}
+
+// channelDelivery reports a logged delivery as a trigger's per-channel outcome
+func channelDelivery(d *store.WebhookDelivery) store.ChannelDelivery {
+	return store.ChannelDelivery{
+		Channel:    ChannelWebhook,
+		Target:     d.Target,
+		Status:     d.Status,
+		Error:      d.LastError,
+		DeliveryID: d.ID,
+	}
+}
+
+// RetryDeliveries retries outbox deliveries whose backoff has passed and returns how
+// many were attempted. Each delivery is claimed just before its attempt, so
+// concurrent passes and first attempts never send the same delivery twice.
+func RetryDeliveries(db *store.Store) (int, error) {
+	attempted := 0
+	for attempted < maxRetriesPerPass {
+		d, err := db.ClaimDueWebhookDelivery(time.Now(), deliveryLease)
+		if err != nil {
+			return attempted, err
+		}
+		if d == nil {
+			break
+		}
+
+		attempted++
+		if err := attempt(db, d); err != nil {
+			util.ErrorLogger.Printf("Failed to record webhook delivery %d: %v", d.ID, err)
+			continue
+		}
+		if d.Status != store.DeliveryPending {
+			syncHistory(db, d)
+		}
+	}
+
+	return attempted, nil
+}
+
+// ReplayDelivery re-sends a logged delivery's payload, freshly signed, as a new delivery
+func ReplayDelivery(db *store.Store, id int64) (*store.WebhookDelivery, error) {
+	original, err := db.GetWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, fmt.Errorf("delivery %d not found", id)
+	}
+
+	d := &store.WebhookDelivery{
+		AlertID:   original.AlertID,
+		HistoryID: original.HistoryID,
+		ReplayOf:  original.ID,
+		Target:    original.Target,
+		Payload:   original.Payload,
+		Secret:    original.Secret,
+	}
+	if err := db.CreateWebhookDelivery(d, deliveryLease); err != nil {
+		return nil, err
+	}
+	if err := attempt(db, d); err != nil {
+		return nil, err
+	}
+	syncHistory(db, d)
+
+	return db.GetWebhookDelivery(d.ID)
+}
+
+// syncHistory updates the trigger's webhook outcome once a retry or replay settles
+func syncHistory(db *store.Store, d *store.WebhookDelivery) {
+	if d.HistoryID == 0 {
+		return
+	}
+
+	history, err := db.GetAlertHistory(d.HistoryID)
+	if err != nil || history == nil {
+		return
+	}
+
+	for i, entry := range history.Deliveries {
+		if entry.DeliveryID == d.ID || (d.ReplayOf != 0 && entry.DeliveryID == d.ReplayOf) {
+			history.Deliveries[i] = channelDelivery(d)
+			if err := db.SetAlertHistoryDeliveries(history.ID, deliveryStatus(history.Deliveries), history.Deliveries); err != nil {
+				util.ErrorLogger.Printf("Failed to update alert history %d: %v", history.ID, err)
+			}
+			return
+		}
+	}
+}
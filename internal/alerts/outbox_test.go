@@ -0,0 +1,186 @@
+package alerts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	want := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 10: 6 * time.Hour, 50: 6 * time.Hour}
+	for attempts, backoff := range want {
+		if got := retryBackoff(attempts); got != backoff {
+			t.Errorf("Expected backoff %v after %d attempts, got %v", backoff, attempts, got)
+		}
+	}
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	var secret string
+	failures := 1
+	var verified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if r.Header.Get(SignatureHeader) == Sign(secret, ts, body) && r.Header.Get(DeliveryHeader) != "" {
+			verified++
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, WebhookURL: server.URL, IsActive: true}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	secret = alert.WebhookSecret
+	if len(secret) < 20 {
+		t.Fatalf("Expected a generated webhook secret, got %q", secret)
+	}
+
+	fire(db, nil, alert, "VIXCLS", 28, 25)
+
+	deliveries, err := db.ListWebhookDeliveries(store.WebhookDeliveryFilter{AlertID: alert.ID})
+	if err != nil {
+		t.Fatalf("Failed to list deliveries: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != store.DeliveryPending || deliveries[0].LastStatusCode != 503 || deliveries[0].NextAttemptAt == nil {
+		t.Fatalf("Expected a pending delivery after a 503, got %+v", deliveries)
+	}
+	history, _ := db.GetAlertHistory(deliveries[0].HistoryID)
+	if history == nil || history.WebhookStatus != store.DeliveryPending || len(history.Deliveries) != 1 {
+		t.Fatalf("Expected pending trigger history, got %+v", history)
+	}
+
+	// Not due yet
+	if n, _ := RetryDeliveries(db); n != 0 {
+		t.Fatalf("Expected no retry before the backoff, got %d", n)
+	}
+	d := &deliveries[0]
+	past := time.Now().Add(-time.Minute)
+	d.NextAttemptAt = &past
+	db.UpdateWebhookDelivery(d)
+
+	if n, _ := RetryDeliveries(db); n != 1 {
+		t.Fatalf("Expected 1 retry, got %d", n)
+	}
+	d, _ = db.GetWebhookDelivery(d.ID)
+	if d.Status != store.DeliverySuccess || d.Attempts != 2 || d.DeliveredAt == nil {
+		t.Errorf("Expected success on the second attempt, got %+v", d)
+	}
+	history, _ = db.GetAlertHistory(d.HistoryID)
+	if history.WebhookStatus != "success" || history.Deliveries[0].Status != store.DeliverySuccess {
+		t.Errorf("Expected history to record the successful retry, got %+v", history)
+	}
+	if verified != 2 {
+		t.Errorf("Expected both attempts to carry a valid signature, got %d", verified)
+	}
+
+	replay, err := ReplayDelivery(db, d.ID)
+	if err != nil {
+		t.Fatalf("Failed to replay: %v", err)
+	}
+	if replay.ReplayOf != d.ID || replay.Status != store.DeliverySuccess || replay.Payload != d.Payload {
+		t.Errorf("Expected a successful replay of the same payload, got %+v", replay)
+	}
+}
+
+func TestWebhookDeadLetter(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	d := &store.WebhookDelivery{AlertID: alert.ID, Target: server.URL, Payload: "{}", Secret: alert.WebhookSecret}
+	if err := db.CreateWebhookDelivery(d, deliveryLease); err != nil {
+		t.Fatalf("Failed to create delivery: %v", err)
+	}
+
+	for i := 0; i < MaxDeliveryAttempts; i++ {
+		attempt(db, d)
+	}
+	if d.Status != store.DeliveryDead || d.NextAttemptAt != nil {
+		t.Errorf("Expected dead-lettered delivery after %d attempts, got %+v", MaxDeliveryAttempts, d)
+	}
+	dead, _ := db.ListWebhookDeliveries(store.WebhookDeliveryFilter{UserEmail: "a@example.com", Status: store.DeliveryDead})
+	if len(dead) != 1 {
+		t.Errorf("Expected 1 dead delivery, got %d", len(dead))
+	}
+}
+
+func TestWebhookDeliveryClaims(t *testing.T) {
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	alert := &store.Alert{UserEmail: "a@example.com", Name: "VIX spike", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true}
+	if err := db.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	d := &store.WebhookDelivery{AlertID: alert.ID, Target: "http://127.0.0.1:1", Payload: "{}", Secret: alert.WebhookSecret}
+	if err := db.CreateWebhookDelivery(d, deliveryLease); err != nil {
+		t.Fatalf("Failed to create delivery: %v", err)
+	}
+	if d.Status != store.DeliverySending {
+		t.Errorf("Expected a new delivery to be claimed for its first attempt, got %q", d.Status)
+	}
+
+	// The first attempt is still in flight, so retries leave it alone
+	if n, _ := RetryDeliveries(db); n != 0 {
+		t.Errorf("Expected an in-flight delivery not to be retried, got %d attempts", n)
+	}
+
+	// Once its lease runs out it can be claimed, but only once
+	later := time.Now().Add(deliveryLease + time.Minute)
+	claimed, err := db.ClaimDueWebhookDelivery(later, deliveryLease)
+	if err != nil || claimed == nil || claimed.ID != d.ID {
+		t.Fatalf("Expected to claim delivery %d after its lease, got %+v (%v)", d.ID, claimed, err)
+	}
+	if again, _ := db.ClaimDueWebhookDelivery(later, deliveryLease); again != nil {
+		t.Errorf("Expected a claimed delivery not to be claimed twice, got %+v", again)
+	}
+}
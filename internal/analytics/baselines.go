@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"reserve-watch/internal/store"
+)
+
+// Baseline is the reference value an index component is normalized against
+type Baseline struct {
+	SeriesID string  `json:"series_id,omitempty"` // empty for fixed references
+	Value    float64 `json:"value"`
+	Date     string  `json:"date"`
+	Source   string  `json:"source"` // "series" when read from the store, "fallback" otherwise
+}
+
+// Baseline names used by the indices
+const (
+	BaselineSWIFTShareUSD     = "swift_share_usd"
+	BaselineCOFERShareUSD     = "cofer_share_usd"
+	BaselineSWIFTParticipants = "swift_participants"
+)
+
+// baselineFallbacks are used when the baseline series hasn't been ingested yet.
+// Each value is dated so a stale fallback is visible in IndexResult. No source
+// publishes SWIFT's member count as data, so that baseline has no series and is
+// always the dated constant.
+var baselineFallbacks = map[string]Baseline{
+	BaselineSWIFTShareUSD:     {SeriesID: "SWIFT_USD", Value: 49.1, Date: "2024-11-30"},
+	BaselineCOFERShareUSD:     {SeriesID: "COFER_USD", Value: 57.7, Date: "2024-09-30"},
+	BaselineSWIFTParticipants: {Value: 11000, Date: "2024-12-31"},
+}
+
+// ResolveBaseline returns the latest stored value of a baseline's series,
+// or its dated fallback when the series is missing
+func ResolveBaseline(db *store.Store, name string) Baseline {
+	b := baselineFallbacks[name]
+	b.Source = "fallback"
+
+	if db == nil || b.SeriesID == "" {
+		return b
+	}
+
+	if point, err := db.GetLatestPoint(b.SeriesID); err == nil && point != nil && point.Value > 0 {
+		b.Value = point.Value
+		b.Date = point.Date
+		b.Source = "series"
+	}
+	return b
+}
+
+// PenetrationBaselines are the USD/mature-market references for the RMB Penetration Score
+type PenetrationBaselines struct {
+	SWIFTShareUSD     Baseline
+	COFERShareUSD     Baseline
+	SWIFTParticipants Baseline
+}
+
+// ResolvePenetrationBaselines resolves every RMB Penetration Score baseline
+func ResolvePenetrationBaselines(db *store.Store) PenetrationBaselines {
+	return PenetrationBaselines{
+		SWIFTShareUSD:     ResolveBaseline(db, BaselineSWIFTShareUSD),
+		COFERShareUSD:     ResolveBaseline(db, BaselineCOFERShareUSD),
+		SWIFTParticipants: ResolveBaseline(db, BaselineSWIFTParticipants),
+	}
+}
+
+// DefaultPenetrationBaselines returns the dated fallback baselines
+func DefaultPenetrationBaselines() PenetrationBaselines {
+	return ResolvePenetrationBaselines(nil)
+}
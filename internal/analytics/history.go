@@ -0,0 +1,342 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"reserve-watch/internal/store"
+)
+
+// Series the index history is persisted under
+const (
+	SeriesRMBPenetration          = "IDX_RMB_PENETRATION"
+	SeriesDiversificationPressure = "IDX_DIVERSIFICATION_PRESSURE"
+
+	// IndexHistoryStart is the first date UpdateIndexHistory computes indices for
+	IndexHistoryStart = "2016-01-01"
+)
+
+// fillRule says which series feeds an index component and how many days its last
+// observation may be carried forward. MaxAgeDays 0 carries it forward indefinitely.
+type fillRule struct {
+	Component  string
+	SeriesID   string
+	MaxAgeDays int
+}
+
+// Monthly and quarterly releases stay valid for one missed release plus publication lag;
+// CIPS participant counts only change when the membership list is updated.
+var (
+	rmbPenetrationInputs = []fillRule{
+		{Component: "swift_payment_share_rmb", SeriesID: "SWIFT_RMB", MaxAgeDays: 92},
+		{Component: "cofer_reserve_share_rmb", SeriesID: "COFER_CNY", MaxAgeDays: 190},
+		{Component: "cips_participants", SeriesID: "CIPS_PARTICIPANTS"},
+	}
+	diversificationPressureInputs = []fillRule{
+		{Component: "gold_reserve_share", SeriesID: "WGC_GOLD_RESERVE_SHARE", MaxAgeDays: 190},
+		{Component: "cb_gold_purchases", SeriesID: "WGC_CB_PURCHASES", MaxAgeDays: 190},
+	}
+)
+
+// IndexPoint is an index value computed as of a date
+type IndexPoint struct {
+	Date       string             `json:"date"`
+	Value      float64            `json:"value"`
+	Components map[string]float64 `json:"components"`
+}
+
+// IsIndexSeries reports whether a series is one of the persisted indices
+func IsIndexSeries(seriesID string) bool {
+	_, ok := indexInputs[seriesID]
+	return ok
+}
+
+// IndexCatalog describes the persisted indices for the store's series catalog
+func IndexCatalog() []store.Series {
+	index := func(id, name, label string) store.Series {
+		return store.Series{
+			ID:        id,
+			Name:      name,
+			Label:     label,
+			Unit:      "score_0_100",
+			Frequency: "daily",
+			Source:    "reserve_watch",
+			Provider:  "Reserve Watch",
+			License:   "Derived from the input series; cite Reserve Watch and the underlying sources",
+			Link:      "/methodology",
+		}
+	}
+	return []store.Series{
+		index(SeriesRMBPenetration, "RMB Penetration Score", "RMB Penetration"),
+		index(SeriesDiversificationPressure, "Reserve Diversification Pressure", "Diversification Pressure"),
+	}
+}
+
+// IsIndexInput reports whether a series feeds an index or one of its baselines
+func IsIndexInput(seriesID string) bool {
+	for _, inputs := range indexInputs {
+		for _, rule := range inputs {
+			if rule.SeriesID == seriesID {
+				return true
+			}
+		}
+	}
+	for _, b := range baselineFallbacks {
+		if b.SeriesID != "" && b.SeriesID == seriesID {
+			return true
+		}
+	}
+	return false
+}
+
+// filledSeries answers "what was the latest observation as of date" for one series
+type filledSeries struct {
+	points []store.SeriesPoint // oldest first
+	maxAge int
+}
+
+func (f filledSeries) asOf(date string) (store.SeriesPoint, bool) {
+	i := sort.Search(len(f.points), func(i int) bool { return f.points[i].Date > date })
+	if i == 0 {
+		return store.SeriesPoint{}, false
+	}
+	p := f.points[i-1]
+	if f.maxAge > 0 && daysBetween(p.Date, date) > f.maxAge {
+		return store.SeriesPoint{}, false
+	}
+	return p, true
+}
+
+func daysBetween(from, to string) int {
+	a, errA := time.Parse("2006-01-02", from)
+	b, errB := time.Parse("2006-01-02", to)
+	if errA != nil || errB != nil {
+		return math.MaxInt32
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func loadFilled(db *store.Store, seriesID, end string, maxAge int) (filledSeries, error) {
+	points, err := db.GetPointsBetween(seriesID, "", end)
+	if err != nil {
+		return filledSeries{}, fmt.Errorf("failed to load %s: %w", seriesID, err)
+	}
+	return filledSeries{points: points, maxAge: maxAge}, nil
+}
+
+// ComputeIndexHistory computes each index as of every date between start and end on
+// which one of its components was observed. Components are aligned by carrying their
+// last observation forward within their fill rule; dates where any component is
+// missing or stale are skipped.
+func ComputeIndexHistory(db *store.Store, start, end string) (map[string][]IndexPoint, error) {
+	// Baselines move with their own series, falling back to the dated constants
+	baselineSeries := make(map[string]filledSeries)
+	for name, b := range baselineFallbacks {
+		if b.SeriesID == "" {
+			continue
+		}
+		s, err := loadFilled(db, b.SeriesID, end, 0)
+		if err != nil {
+			return nil, err
+		}
+		baselineSeries[name] = s
+	}
+	baselineAsOf := func(name, date string) Baseline {
+		b := baselineFallbacks[name]
+		b.Source = "fallback"
+		if p, ok := baselineSeries[name].asOf(date); ok && p.Value > 0 {
+			b.Value, b.Date, b.Source = p.Value, p.Date, "series"
+		}
+		return b
+	}
+
+	rmb, err := computeIndex(db, rmbPenetrationInputs, start, end, func(date string, c map[string]float64) float64 {
+		return RMBPenetrationScore(c["swift_payment_share_rmb"], c["cofer_reserve_share_rmb"], c["cips_participants"], PenetrationBaselines{
+			SWIFTShareUSD:     baselineAsOf(BaselineSWIFTShareUSD, date),
+			COFERShareUSD:     baselineAsOf(BaselineCOFERShareUSD, date),
+			SWIFTParticipants: baselineAsOf(BaselineSWIFTParticipants, date),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pressure, err := computeIndex(db, diversificationPressureInputs, start, end, func(date string, c map[string]float64) float64 {
+		return ReserveDiversificationPressure(c["gold_reserve_share"], c["cb_gold_purchases"])
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]IndexPoint{
+		SeriesRMBPenetration:          rmb,
+		SeriesDiversificationPressure: pressure,
+	}, nil
+}
+
+func computeIndex(db *store.Store, inputs []fillRule, start, end string, score func(date string, components map[string]float64) float64) ([]IndexPoint, error) {
+	series := make([]filledSeries, len(inputs))
+	dateSet := make(map[string]bool)
+	for i, rule := range inputs {
+		s, err := loadFilled(db, rule.SeriesID, end, rule.MaxAgeDays)
+		if err != nil {
+			return nil, err
+		}
+		series[i] = s
+		for _, p := range s.points {
+			if p.Date >= start {
+				dateSet[p.Date] = true
+			}
+		}
+	}
+
+	dates := make([]string, 0, len(dateSet))
+	for date := range dateSet {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	var history []IndexPoint
+	for _, date := range dates {
+		components := make(map[string]float64, len(inputs))
+		for i, rule := range inputs {
+			p, ok := series[i].asOf(date)
+			if !ok {
+				break
+			}
+			components[rule.Component] = p.Value
+		}
+		if len(components) < len(inputs) {
+			continue
+		}
+
+		history = append(history, IndexPoint{
+			Date:       date,
+			Value:      score(date, components),
+			Components: components,
+		})
+	}
+	return history, nil
+}
+
+// UpdateIndexHistory recomputes both indices from since (IndexHistoryStart if empty
+// or earlier) and saves them as their own series. Persisted index points in that
+// range that are no longer produced, e.g. because an input was revised away, are
+// deleted. It returns the number of points saved.
+func UpdateIndexHistory(db *store.Store, since string) (int, error) {
+	if since < IndexHistoryStart {
+		since = IndexHistoryStart
+	}
+
+	history, err := ComputeIndexHistory(db, since, time.Now().UTC().Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	for seriesID, points := range history {
+		if err := deleteStaleIndexPoints(db, seriesID, since, points); err != nil {
+			return saved, err
+		}
+		if len(points) == 0 {
+			continue
+		}
+
+		seriesPoints := make([]store.SeriesPoint, len(points))
+		for i, p := range points {
+			meta := map[string]string{
+				"series_id": seriesID,
+				"source":    "Reserve Watch",
+				"unit":      "index_0_100",
+				"method":    "as_of_forward_fill",
+			}
+			for name, v := range p.Components {
+				meta[name] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			seriesPoints[i] = store.SeriesPoint{Date: p.Date, Value: p.Value, Meta: meta}
+		}
+
+		if err := db.SavePoints(seriesID, seriesPoints, time.Now()); err != nil {
+			return saved, fmt.Errorf("failed to save %s: %w", seriesID, err)
+		}
+		saved += len(seriesPoints)
+	}
+	return saved, nil
+}
+
+// deleteStaleIndexPoints deletes persisted points of an index from since onwards
+// that the recomputed history no longer has
+func deleteStaleIndexPoints(db *store.Store, seriesID, since string, points []IndexPoint) error {
+	persisted, err := db.GetPointsBetween(seriesID, since, "")
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", seriesID, err)
+	}
+
+	produced := make(map[string]bool, len(points))
+	for _, p := range points {
+		produced[p.Date] = true
+	}
+	var stale []string
+	for _, p := range persisted {
+		if !produced[p.Date] {
+			stale = append(stale, p.Date)
+		}
+	}
+
+	if err := db.DeletePoints(seriesID, stale); err != nil {
+		return fmt.Errorf("failed to delete stale %s points: %w", seriesID, err)
+	}
+	return nil
+}
+
+// EarliestIndexInputDate returns the earliest observation date among updates of
+// index inputs, which is where the index history has to be recomputed from. It
+// reports false if none of the updates feed an index.
+func EarliestIndexInputDate(events []store.SeriesUpdated) (string, bool) {
+	earliest, found := "", false
+	for _, e := range events {
+		if !IsIndexInput(e.SeriesID) {
+			continue
+		}
+		for _, p := range e.Points {
+			if !found || p.Date < earliest {
+				earliest, found = p.Date, true
+			}
+		}
+	}
+	return earliest, found
+}
+
+// indexInputs maps each persisted index series to its inputs
+var indexInputs = map[string][]fillRule{
+	SeriesRMBPenetration:          rmbPenetrationInputs,
+	SeriesDiversificationPressure: diversificationPressureInputs,
+}
+
+// GetIndexHistory reads a persisted index series between two dates, oldest first
+func GetIndexHistory(db *store.Store, seriesID, start, end string) ([]IndexPoint, error) {
+	inputs, ok := indexInputs[seriesID]
+	if !ok {
+		return nil, fmt.Errorf("unknown index series %q", seriesID)
+	}
+
+	points, err := db.GetPointsBetween(seriesID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]IndexPoint, len(points))
+	for i, p := range points {
+		components := make(map[string]float64, len(inputs))
+		for _, rule := range inputs {
+			if v, err := strconv.ParseFloat(p.Meta[rule.Component], 64); err == nil {
+				components[rule.Component] = v
+			}
+		}
+		history[i] = IndexPoint{Date: p.Date, Value: p.Value, Components: components}
+	}
+	return history, nil
+}
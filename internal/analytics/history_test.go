@@ -0,0 +1,141 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+)
+
+func TestComputeIndexHistoryForwardFill(t *testing.T) {
+	db := newTestStore(t)
+
+	// Monthly SWIFT, quarterly COFER, one CIPS count carried forward indefinitely
+	savePoint(t, db, "SWIFT_RMB", "2024-01-31", 4.5)
+	savePoint(t, db, "SWIFT_RMB", "2024-02-29", 4.6)
+	savePoint(t, db, "SWIFT_RMB", "2024-09-30", 4.7)
+	savePoint(t, db, "COFER_CNY", "2023-12-31", 2.3)
+	savePoint(t, db, "CIPS_PARTICIPANTS", "2023-06-30", 1400)
+
+	history, err := ComputeIndexHistory(db, "2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatalf("Failed to compute history: %v", err)
+	}
+
+	rmb := history[SeriesRMBPenetration]
+	if len(rmb) != 2 {
+		t.Fatalf("Expected 2 RMB points (COFER stale by September), got %+v", rmb)
+	}
+	if rmb[0].Date != "2024-01-31" || rmb[0].Components["cofer_reserve_share_rmb"] != 2.3 || rmb[0].Components["cips_participants"] != 1400 {
+		t.Errorf("Expected forward-filled components on 2024-01-31, got %+v", rmb[0])
+	}
+
+	want := RMBPenetrationScore(4.6, 2.3, 1400, DefaultPenetrationBaselines())
+	if rmb[1].Date != "2024-02-29" || rmb[1].Value != want {
+		t.Errorf("Expected %.2f on 2024-02-29, got %+v", want, rmb[1])
+	}
+
+	if len(history[SeriesDiversificationPressure]) != 0 {
+		t.Errorf("Expected no pressure history without WGC data, got %+v", history[SeriesDiversificationPressure])
+	}
+}
+
+func TestComputeIndexHistoryBaselineAsOf(t *testing.T) {
+	db := newTestStore(t)
+
+	savePoint(t, db, "SWIFT_RMB", "2024-01-31", 4.5)
+	savePoint(t, db, "SWIFT_RMB", "2024-02-29", 4.5)
+	savePoint(t, db, "COFER_CNY", "2023-12-31", 2.3)
+	savePoint(t, db, "CIPS_PARTICIPANTS", "2023-06-30", 1400)
+	savePoint(t, db, "SWIFT_USD", "2024-02-29", 45)
+
+	history, err := ComputeIndexHistory(db, "2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatalf("Failed to compute history: %v", err)
+	}
+
+	// January predates the SWIFT_USD observation and uses the fallback
+	rmb := history[SeriesRMBPenetration]
+	if len(rmb) != 2 || rmb[0].Value >= rmb[1].Value {
+		t.Errorf("Expected the lower February USD baseline to raise the score, got %+v", rmb)
+	}
+}
+
+func TestUpdateIndexHistoryTrend(t *testing.T) {
+	db := newTestStore(t)
+
+	savePoint(t, db, "WGC_GOLD_RESERVE_SHARE", "2024-06-30", 16)
+	savePoint(t, db, "WGC_GOLD_RESERVE_SHARE", "2024-09-30", 17.6)
+	savePoint(t, db, "WGC_CB_PURCHASES", "2024-06-30", 200)
+	savePoint(t, db, "WGC_CB_PURCHASES", "2024-09-30", 300)
+
+	saved, err := UpdateIndexHistory(db, "")
+	if err != nil {
+		t.Fatalf("Failed to update history: %v", err)
+	}
+	if saved != 2 {
+		t.Errorf("Expected 2 saved points, got %d", saved)
+	}
+
+	history, err := GetIndexHistory(db, SeriesDiversificationPressure, "", "")
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+	if len(history) != 2 || history[1].Components["cb_gold_purchases"] != 300 {
+		t.Errorf("Expected persisted history with components, got %+v", history)
+	}
+
+	indices, err := CalculateAllIndices(db)
+	if err != nil {
+		t.Fatalf("Failed to calculate indices: %v", err)
+	}
+	pressure := indices[0]
+	if pressure.PreviousValue == nil || *pressure.PreviousValue != history[0].Value || pressure.PreviousDate != "2024-06-30" {
+		t.Fatalf("Expected previous value from 2024-06-30, got %+v", pressure)
+	}
+	if pressure.Trend != "rising" {
+		t.Errorf("Expected rising trend, got %s", pressure.Trend)
+	}
+
+	if _, err := GetIndexHistory(db, "DTWEXBGS", "", ""); err == nil {
+		t.Error("Expected error for a non-index series")
+	}
+}
+
+func TestUpdateIndexHistoryDeletesStalePoints(t *testing.T) {
+	db := newTestStore(t)
+
+	savePoint(t, db, "WGC_GOLD_RESERVE_SHARE", "2024-06-30", 16)
+	savePoint(t, db, "WGC_CB_PURCHASES", "2024-06-30", 200)
+
+	// Points no longer backed by inputs, one before and one after the recomputed range
+	savePoint(t, db, SeriesDiversificationPressure, "2024-03-31", 40)
+	savePoint(t, db, SeriesDiversificationPressure, "2024-08-15", 99)
+	beforeDelete := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := UpdateIndexHistory(db, "2024-06-01"); err != nil {
+		t.Fatalf("Failed to update history: %v", err)
+	}
+
+	points, err := db.GetPointsBetween(SeriesDiversificationPressure, "", "")
+	if err != nil {
+		t.Fatalf("Failed to read index points: %v", err)
+	}
+	if len(points) != 2 || points[0].Date != "2024-03-31" || points[1].Date != "2024-06-30" {
+		t.Errorf("Expected 2024-08-15 deleted and points before the range kept, got %+v", points)
+	}
+
+	past, err := db.AsOf(beforeDelete).GetPointsBetween(SeriesDiversificationPressure, "", "")
+	if err != nil || len(past) != 2 || past[1].Date != "2024-08-15" {
+		t.Errorf("Expected the deleted point as of before the update, got %+v, %v", past, err)
+	}
+
+	since, ok := EarliestIndexInputDate([]store.SeriesUpdated{
+		{SeriesID: "VIXCLS", Points: []store.SeriesPoint{{Date: "2020-01-01"}}},
+		{SeriesID: "COFER_CNY", Points: []store.SeriesPoint{{Date: "2024-09-30"}, {Date: "2024-06-30"}}},
+	})
+	if !ok || since != "2024-06-30" {
+		t.Errorf("Expected the earliest index input date 2024-06-30, got %q, %v", since, ok)
+	}
+}
@@ -9,30 +9,15 @@ import (
 
 // RMBPenetrationScore calculates the RMB Penetration Score (0-100)
 // Methodology: Normalize each component against USD/mature-currency baselines, then equal-weight average
-// - Payments: RMB SWIFT share / USD SWIFT share (~49.1%)
-// - Reserves: RMB COFER share / USD COFER share (~57.7%)
-// - Network: CIPS participants / SWIFT total participants (~11,000)
+// - Payments: RMB SWIFT share / USD SWIFT share (SWIFT_USD)
+// - Reserves: RMB COFER share / USD COFER share (COFER_USD)
+// - Network: CIPS participants / SWIFT total participants
 // Result: 0-100 score, where ~8-10 indicates current RMB penetration level
-func RMBPenetrationScore(swiftShareRMB, coferShareRMB, cipsParticipants float64) float64 {
-	// Baselines (as of 2024-2025)
-	const swiftShareUSD = 49.1             // USD payment share in SWIFT
-	const coferShareUSD = 57.7             // USD reserve share in IMF COFER
-	const swiftTotalParticipants = 11000.0 // SWIFT's global member base
-
-	// Component 1: Payments (0-100 points)
-	// Normalize RMB payment share against USD payment share
-	paymentsComponent := (swiftShareRMB / swiftShareUSD) * 100.0
-
-	// Component 2: Reserves (0-100 points)
-	// Normalize RMB reserve share against USD reserve share
-	reservesComponent := (coferShareRMB / coferShareUSD) * 100.0
-
-	// Component 3: Network (0-100 points)
-	// Normalize CIPS participants against SWIFT total
-	networkComponent := (cipsParticipants / swiftTotalParticipants) * 100.0
+func RMBPenetrationScore(swiftShareRMB, coferShareRMB, cipsParticipants float64, baselines PenetrationBaselines) float64 {
+	payments, reserves, network := penetrationComponents(swiftShareRMB, coferShareRMB, cipsParticipants, baselines)
 
 	// Equal-weight average of three components
-	score := (paymentsComponent + reservesComponent + networkComponent) / 3.0
+	score := (payments + reserves + network) / 3.0
 
 	// Cap at 100 (shouldn't happen with current data)
 	if score > 100 {
@@ -42,6 +27,14 @@ func RMBPenetrationScore(swiftShareRMB, coferShareRMB, cipsParticipants float64)
 	return math.Round(score*100) / 100 // Round to 2 decimals
 }
 
+// penetrationComponents normalizes each RMB component against its baseline (0-100 points each)
+func penetrationComponents(swiftShareRMB, coferShareRMB, cipsParticipants float64, b PenetrationBaselines) (payments, reserves, network float64) {
+	payments = (swiftShareRMB / b.SWIFTShareUSD.Value) * 100.0
+	reserves = (coferShareRMB / b.COFERShareUSD.Value) * 100.0
+	network = (cipsParticipants / b.SWIFTParticipants.Value) * 100.0
+	return payments, reserves, network
+}
+
 // ReserveDiversificationPressure calculates pressure on USD reserves
 // Formula: (Gold Reserve Share Trend) + (Central Bank Gold Buying Rate)
 // Higher score = more pressure to diversify away from USD
@@ -81,20 +74,27 @@ func ReserveDiversificationPressure(goldSharePercent, cbBuyingTonnes float64) fl
 
 // ComponentDetail holds detailed component breakdown
 type ComponentDetail struct {
-	RawValue   float64 `json:"raw_value"`
-	Baseline   float64 `json:"baseline,omitempty"`
-	Normalized float64 `json:"normalized"`
+	RawValue       float64 `json:"raw_value"`
+	Baseline       float64 `json:"baseline,omitempty"`
+	BaselineDate   string  `json:"baseline_date,omitempty"`
+	BaselineSource string  `json:"baseline_source,omitempty"`
+	Normalized     float64 `json:"normalized"`
 }
 
 // IndexResult holds calculated index values with metadata
 type IndexResult struct {
 	Name               string                     `json:"name"`
+	SeriesID           string                     `json:"series_id"`
 	Value              float64                    `json:"value"`
 	Description        string                     `json:"description"`
 	Method             string                     `json:"method"`
 	Components         map[string]float64         `json:"components"`
 	ComponentsDetailed map[string]ComponentDetail `json:"components_detailed,omitempty"`
+	Baselines          map[string]Baseline        `json:"baselines,omitempty"`
 	Timestamp          string                     `json:"timestamp"`
+	PreviousValue      *float64                   `json:"previous_value,omitempty"`
+	PreviousDate       string                     `json:"previous_date,omitempty"`
+	Trend              string                     `json:"trend,omitempty"`
 }
 
 // CalculateAllIndices computes all proprietary indices from store data
@@ -110,24 +110,25 @@ func CalculateAllIndices(db *store.Store) ([]IndexResult, error) {
 
 	// Calculate RMB Penetration Score
 	if swiftPoint != nil && coferPoint != nil && cipsPoint != nil {
-		// Baselines
-		const swiftShareUSD = 49.1
-		const coferShareUSD = 57.7
-		const swiftTotalParticipants = 11000.0
+		baselines := ResolvePenetrationBaselines(db)
 
-		// Calculate normalized components
-		paymentsNorm := (swiftPoint.Value / swiftShareUSD) * 100.0
-		reservesNorm := (coferPoint.Value / coferShareUSD) * 100.0
-		networkNorm := (cipsPoint.Value / swiftTotalParticipants) * 100.0
+		paymentsNorm, reservesNorm, networkNorm := penetrationComponents(
+			swiftPoint.Value,
+			coferPoint.Value,
+			cipsPoint.Value,
+			baselines,
+		)
 
 		score := RMBPenetrationScore(
 			swiftPoint.Value,
 			coferPoint.Value,
 			cipsPoint.Value,
+			baselines,
 		)
 
 		results = append(results, IndexResult{
 			Name:        "RMB Penetration Score",
+			SeriesID:    SeriesRMBPenetration,
 			Value:       score,
 			Description: "Measures RMB's global reach across payments, reserves, and infrastructure",
 			Method:      "Equal-weight average of three normalized components (each 0-100 vs USD baselines)",
@@ -137,21 +138,14 @@ func CalculateAllIndices(db *store.Store) ([]IndexResult, error) {
 				"cips_participants":       cipsPoint.Value,
 			},
 			ComponentsDetailed: map[string]ComponentDetail{
-				"payments": {
-					RawValue:   swiftPoint.Value,
-					Baseline:   swiftShareUSD,
-					Normalized: math.Round(paymentsNorm*100) / 100,
-				},
-				"reserves": {
-					RawValue:   coferPoint.Value,
-					Baseline:   coferShareUSD,
-					Normalized: math.Round(reservesNorm*100) / 100,
-				},
-				"network": {
-					RawValue:   cipsPoint.Value,
-					Baseline:   swiftTotalParticipants,
-					Normalized: math.Round(networkNorm*100) / 100,
-				},
+				"payments": componentDetail(swiftPoint.Value, paymentsNorm, baselines.SWIFTShareUSD),
+				"reserves": componentDetail(coferPoint.Value, reservesNorm, baselines.COFERShareUSD),
+				"network":  componentDetail(cipsPoint.Value, networkNorm, baselines.SWIFTParticipants),
+			},
+			Baselines: map[string]Baseline{
+				BaselineSWIFTShareUSD:     baselines.SWIFTShareUSD,
+				BaselineCOFERShareUSD:     baselines.COFERShareUSD,
+				BaselineSWIFTParticipants: baselines.SWIFTParticipants,
 			},
 			Timestamp: swiftPoint.Date,
 		})
@@ -166,6 +160,7 @@ func CalculateAllIndices(db *store.Store) ([]IndexResult, error) {
 
 		results = append(results, IndexResult{
 			Name:        "Reserve Diversification Pressure",
+			SeriesID:    SeriesDiversificationPressure,
 			Value:       pressure,
 			Description: "Measures pressure to diversify away from USD into gold and alternatives",
 			Components: map[string]float64{
@@ -180,11 +175,45 @@ func CalculateAllIndices(db *store.Store) ([]IndexResult, error) {
 		return nil, fmt.Errorf("insufficient data to calculate indices")
 	}
 
+	for i := range results {
+		attachTrend(db, &results[i])
+	}
+
 	return results, nil
 }
 
+// attachTrend compares an index with its last persisted value before the current date
+func attachTrend(db *store.Store, result *IndexResult) {
+	recent, err := db.GetRecentPoints(result.SeriesID, 2)
+	if err != nil {
+		return
+	}
+	for _, p := range recent {
+		if p.Date < result.Timestamp {
+			previous := p.Value
+			result.PreviousValue = &previous
+			result.PreviousDate = p.Date
+			result.Trend = GetIndexTrend(result.Value, previous)
+			return
+		}
+	}
+}
+
+func componentDetail(raw, normalized float64, baseline Baseline) ComponentDetail {
+	return ComponentDetail{
+		RawValue:       raw,
+		Baseline:       baseline.Value,
+		BaselineDate:   baseline.Date,
+		BaselineSource: baseline.Source,
+		Normalized:     math.Round(normalized*100) / 100,
+	}
+}
+
 // GetIndexTrend calculates index trend (rising/falling/stable)
 func GetIndexTrend(currentValue, previousValue float64) string {
+	if previousValue == 0 {
+		return "stable"
+	}
 	change := currentValue - previousValue
 	changePercent := (change / previousValue) * 100
 
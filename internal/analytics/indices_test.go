@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/migrations"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	return db
+}
+
+func savePoint(t *testing.T, db *store.Store, seriesID, date string, value float64) {
+	t.Helper()
+	if err := db.SavePoints(seriesID, []store.SeriesPoint{{Date: date, Value: value}}, time.Now()); err != nil {
+		t.Fatalf("Failed to save %s: %v", seriesID, err)
+	}
+}
+
+func TestResolveBaselineFallback(t *testing.T) {
+	db := newTestStore(t)
+
+	b := ResolveBaseline(db, BaselineCOFERShareUSD)
+	if b.Source != "fallback" || b.Value != 57.7 || b.Date == "" {
+		t.Errorf("Expected dated fallback, got %+v", b)
+	}
+
+	savePoint(t, db, "COFER_USD", "2025-03-31", 57.79)
+
+	b = ResolveBaseline(db, BaselineCOFERShareUSD)
+	if b.Source != "series" || b.Value != 57.79 || b.Date != "2025-03-31" {
+		t.Errorf("Expected baseline from COFER_USD, got %+v", b)
+	}
+}
+
+func TestCalculateAllIndicesReportsBaselines(t *testing.T) {
+	db := newTestStore(t)
+
+	savePoint(t, db, "SWIFT_RMB", "2024-09-30", 4.69)
+	savePoint(t, db, "SWIFT_USD", "2024-09-30", 46.9)
+	savePoint(t, db, "COFER_CNY", "2024-06-30", 2.14)
+	savePoint(t, db, "CIPS_PARTICIPANTS", "2024-12-31", 1500)
+
+	indices, err := CalculateAllIndices(db)
+	if err != nil {
+		t.Fatalf("Failed to calculate indices: %v", err)
+	}
+
+	var rmb *IndexResult
+	for i := range indices {
+		if indices[i].Name == "RMB Penetration Score" {
+			rmb = &indices[i]
+		}
+	}
+	if rmb == nil {
+		t.Fatal("Expected RMB Penetration Score")
+	}
+
+	swift := rmb.Baselines[BaselineSWIFTShareUSD]
+	if swift.Source != "series" || swift.Value != 46.9 || swift.Date != "2024-09-30" {
+		t.Errorf("Expected SWIFT_USD baseline from series, got %+v", swift)
+	}
+	if cofer := rmb.Baselines[BaselineCOFERShareUSD]; cofer.Source != "fallback" {
+		t.Errorf("Expected COFER_USD fallback baseline, got %+v", cofer)
+	}
+
+	payments := rmb.ComponentsDetailed["payments"]
+	if payments.Baseline != 46.9 || payments.BaselineDate != "2024-09-30" || payments.Normalized != 10 {
+		t.Errorf("Expected payments normalized against 46.9, got %+v", payments)
+	}
+
+	want := RMBPenetrationScore(4.69, 2.14, 1500, ResolvePenetrationBaselines(db))
+	if rmb.Value != want {
+		t.Errorf("Expected score %.2f, got %.2f", want, rmb.Value)
+	}
+}
@@ -0,0 +1,305 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"reserve-watch/internal/store"
+)
+
+// Resampling frequencies. Periods are labelled by their last day: weeks end on
+// Friday, like FRED's weekly aggregates, and quarters on the quarter-end dates
+// COFER uses.
+const (
+	FrequencyWeekly    = "weekly"
+	FrequencyMonthly   = "monthly"
+	FrequencyQuarterly = "quarterly"
+)
+
+// Aggregations combining the observations in a resampled period
+const (
+	AggregateLast = "last"
+	AggregateMean = "mean"
+	AggregateSum  = "sum"
+)
+
+// Transforms applied after resampling
+const (
+	TransformPctChange = "pct_change" // percent change from the previous observation
+	TransformYoY       = "yoy"        // percent change from a year earlier
+	TransformLog       = "log"        // natural log
+	TransformIndex     = "index"      // rebased so the first observation in range is 100
+)
+
+// yoyToleranceDays is how far before the same date a year earlier the base
+// observation of a yoy change may be, to bridge weekends, holidays and month ends
+const yoyToleranceDays = 31
+
+// ObservationQuery selects, resamples and transforms a series' observations
+type ObservationQuery struct {
+	Start       string // YYYY-MM-DD, inclusive; empty for the earliest
+	End         string // YYYY-MM-DD, inclusive; empty for the latest
+	After       string // cursor: only dates after this one
+	Limit       int    // 0 for no limit
+	Frequency   string // empty keeps the series' own frequency
+	Aggregation string // defaults to last
+	Transform   string // empty for raw values
+}
+
+// Validate reports the first invalid field of q, worded for API clients
+func (q ObservationQuery) Validate() error {
+	for name, date := range map[string]string{"start": q.Start, "end": q.End, "cursor": q.After} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid %s %q: use YYYY-MM-DD", name, date)
+		}
+	}
+
+	switch q.Frequency {
+	case "", FrequencyWeekly, FrequencyMonthly, FrequencyQuarterly:
+	default:
+		return fmt.Errorf("unknown frequency %q (available: weekly, monthly, quarterly)", q.Frequency)
+	}
+	switch q.Aggregation {
+	case "":
+	case AggregateLast, AggregateMean, AggregateSum:
+		if q.Frequency == "" {
+			return fmt.Errorf("aggregation %q needs a frequency to resample to", q.Aggregation)
+		}
+	default:
+		return fmt.Errorf("unknown aggregation %q (available: last, mean, sum)", q.Aggregation)
+	}
+	switch q.Transform {
+	case "", TransformPctChange, TransformYoY, TransformLog, TransformIndex:
+	default:
+		return fmt.Errorf("unknown transform %q (available: pct_change, yoy, log, index)", q.Transform)
+	}
+	return nil
+}
+
+// LoadObservations reads a series' observations for q, oldest first. Raw values are
+// paged straight from the store; otherwise the whole range is loaded, along with
+// enough history before Start for the first period and change, so values don't
+// depend on where a page starts.
+func LoadObservations(db *store.Store, seriesID string, q ObservationQuery) ([]store.SeriesPoint, error) {
+	if q.Frequency == "" && q.Transform == "" {
+		return db.GetPoints(seriesID, store.PointFilter{Start: q.Start, End: q.End, After: q.After, Limit: q.Limit})
+	}
+
+	loadStart := q.Start
+	if loadStart != "" {
+		if q.Transform == TransformPctChange || q.Transform == TransformYoY {
+			loadStart = shiftDate(loadStart, -1, -2)
+		}
+		if q.Frequency != "" {
+			loadStart = periodStart(loadStart, q.Frequency)
+		}
+	}
+
+	points, err := db.GetPointsBetween(seriesID, loadStart, q.End)
+	if err != nil {
+		return nil, err
+	}
+	if q.Frequency != "" {
+		points = Resample(points, q.Frequency, q.Aggregation)
+	}
+	if q.Transform != TransformIndex {
+		points = Transform(points, q.Transform)
+	}
+
+	points = trimPoints(points, q.Start, q.End)
+	if q.Transform == TransformIndex {
+		points = Transform(points, q.Transform)
+	}
+	after := sort.Search(len(points), func(i int) bool { return points[i].Date > q.After })
+	points = points[after:]
+	if q.Limit > 0 && len(points) > q.Limit {
+		points = points[:q.Limit]
+	}
+	return points, nil
+}
+
+// trimPoints keeps the points with start <= date <= end; empty bounds are open
+func trimPoints(points []store.SeriesPoint, start, end string) []store.SeriesPoint {
+	from := sort.Search(len(points), func(i int) bool { return points[i].Date >= start })
+	to := len(points)
+	if end != "" {
+		to = sort.Search(len(points), func(i int) bool { return points[i].Date > end })
+	}
+	if from >= to {
+		return nil
+	}
+	return points[from:to]
+}
+
+// Resample aggregates points, oldest first, into periods of freq labelled by their
+// last day. The newest period may still be incomplete.
+func Resample(points []store.SeriesPoint, freq, aggregation string) []store.SeriesPoint {
+	var resampled []store.SeriesPoint
+	var count int
+	for _, p := range points {
+		end := periodEnd(p.Date, freq)
+		if end == "" {
+			continue
+		}
+
+		if len(resampled) == 0 || resampled[len(resampled)-1].Date != end {
+			if count > 0 && aggregation == AggregateMean {
+				resampled[len(resampled)-1].Value /= float64(count)
+			}
+			resampled = append(resampled, store.SeriesPoint{Date: end, Value: p.Value})
+			count = 1
+			continue
+		}
+
+		last := &resampled[len(resampled)-1]
+		switch aggregation {
+		case AggregateMean, AggregateSum:
+			last.Value += p.Value
+		default:
+			last.Value = p.Value
+		}
+		count++
+	}
+	if count > 0 && aggregation == AggregateMean {
+		resampled[len(resampled)-1].Value /= float64(count)
+	}
+	return resampled
+}
+
+// periodEnd is the last day of the period of freq containing date, or "" if date
+// doesn't parse
+func periodEnd(date, freq string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+
+	switch freq {
+	case FrequencyWeekly:
+		t = t.AddDate(0, 0, (int(time.Friday)-int(t.Weekday())+7)%7)
+	case FrequencyMonthly:
+		t = time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	case FrequencyQuarterly:
+		quarterEnd := ((t.Month()-1)/3 + 1) * 3
+		t = time.Date(t.Year(), quarterEnd+1, 0, 0, 0, 0, 0, time.UTC)
+	}
+	return t.Format("2006-01-02")
+}
+
+// periodStart is the first day of the period of freq containing date
+func periodStart(date, freq string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+
+	switch freq {
+	case FrequencyWeekly:
+		t = t.AddDate(0, 0, -((int(t.Weekday()) - int(time.Saturday) + 7) % 7))
+	case FrequencyMonthly:
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case FrequencyQuarterly:
+		t = time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t.Format("2006-01-02")
+}
+
+func shiftDate(date string, years, months int) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(years, months, 0).Format("2006-01-02")
+}
+
+// Transform applies a named transform to points, oldest first. Points it is
+// undefined for are dropped: the first pct_change, yoy without an observation a
+// year earlier, changes from zero and logs of non-positive values. An empty name
+// returns points unchanged.
+func Transform(points []store.SeriesPoint, name string) []store.SeriesPoint {
+	if name == "" {
+		return points
+	}
+
+	var out []store.SeriesPoint
+	for i, p := range points {
+		var v float64
+		switch name {
+		case TransformPctChange:
+			if i == 0 || points[i-1].Value == 0 {
+				continue
+			}
+			v = (p.Value/points[i-1].Value - 1) * 100
+		case TransformYoY:
+			base, ok := yearEarlier(points[:i], p.Date)
+			if !ok || base.Value == 0 {
+				continue
+			}
+			v = (p.Value/base.Value - 1) * 100
+		case TransformLog:
+			if p.Value <= 0 {
+				continue
+			}
+			v = math.Log(p.Value)
+		case TransformIndex:
+			if points[0].Value == 0 {
+				return nil
+			}
+			v = p.Value / points[0].Value * 100
+		default:
+			return points
+		}
+		out = append(out, store.SeriesPoint{Date: p.Date, Value: v})
+	}
+	return out
+}
+
+// yearEarlier finds the latest of earlier, oldest first, on or before the same date
+// a year before date, within yoyToleranceDays
+func yearEarlier(earlier []store.SeriesPoint, date string) (store.SeriesPoint, bool) {
+	target := shiftDate(date, -1, 0)
+	i := sort.Search(len(earlier), func(i int) bool { return earlier[i].Date > target })
+	if i == 0 || daysBetween(earlier[i-1].Date, target) > yoyToleranceDays {
+		return store.SeriesPoint{}, false
+	}
+	return earlier[i-1], true
+}
+
+// AlignedObservation is the value of each series on one date; nil where a series
+// has no observation that date
+type AlignedObservation struct {
+	Date   string              `json:"date"`
+	Values map[string]*float64 `json:"values"`
+}
+
+// AlignObservations puts several series, each oldest first, on the union of their
+// dates, oldest first. Gaps are left nil rather than filled.
+func AlignObservations(series map[string][]store.SeriesPoint) []AlignedObservation {
+	rows := make(map[string]map[string]*float64)
+	for id, points := range series {
+		for _, p := range points {
+			if rows[p.Date] == nil {
+				rows[p.Date] = make(map[string]*float64, len(series))
+			}
+			v := p.Value
+			rows[p.Date][id] = &v
+		}
+	}
+
+	aligned := make([]AlignedObservation, 0, len(rows))
+	for date, values := range rows {
+		for id := range series {
+			if _, ok := values[id]; !ok {
+				values[id] = nil
+			}
+		}
+		aligned = append(aligned, AlignedObservation{Date: date, Values: values})
+	}
+	sort.Slice(aligned, func(i, j int) bool { return aligned[i].Date < aligned[j].Date })
+	return aligned
+}
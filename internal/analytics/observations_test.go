@@ -0,0 +1,113 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"reserve-watch/internal/store"
+)
+
+func TestResample(t *testing.T) {
+	daily := []store.SeriesPoint{
+		{Date: "2024-01-04", Value: 1}, // Thursday
+		{Date: "2024-01-05", Value: 2}, // Friday
+		{Date: "2024-01-08", Value: 3}, // Monday
+		{Date: "2024-01-31", Value: 4},
+		{Date: "2024-02-01", Value: 6},
+		{Date: "2024-04-01", Value: 8},
+	}
+
+	weekly := Resample(daily, FrequencyWeekly, AggregateLast)
+	if len(weekly) != 4 || weekly[0].Date != "2024-01-05" || weekly[0].Value != 2 || weekly[1].Date != "2024-01-12" {
+		t.Errorf("Expected weeks ending Friday, got %+v", weekly)
+	}
+
+	monthly := Resample(daily, FrequencyMonthly, AggregateMean)
+	if len(monthly) != 3 || monthly[0].Date != "2024-01-31" || monthly[0].Value != 2.5 || monthly[2].Value != 8 {
+		t.Errorf("Expected monthly means, got %+v", monthly)
+	}
+
+	quarterly := Resample(daily, FrequencyQuarterly, AggregateSum)
+	if len(quarterly) != 2 || quarterly[0].Date != "2024-03-31" || quarterly[0].Value != 16 || quarterly[1].Date != "2024-06-30" {
+		t.Errorf("Expected quarterly sums at quarter ends, got %+v", quarterly)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	points := []store.SeriesPoint{
+		{Date: "2023-03-31", Value: 50},
+		{Date: "2023-12-31", Value: 80},
+		{Date: "2024-03-31", Value: 100},
+		{Date: "2024-06-30", Value: 110},
+	}
+
+	pct := Transform(points, TransformPctChange)
+	if len(pct) != 3 || pct[0].Date != "2023-12-31" || math.Abs(pct[0].Value-60) > 1e-9 {
+		t.Errorf("Expected change from the previous observation, got %+v", pct)
+	}
+
+	yoy := Transform(points, TransformYoY)
+	if len(yoy) != 1 || yoy[0].Date != "2024-03-31" || yoy[0].Value != 100 {
+		t.Errorf("Expected only Q1 2024 to have a year-earlier base, got %+v", yoy)
+	}
+
+	index := Transform(points[2:], TransformIndex)
+	if len(index) != 2 || index[0].Value != 100 || math.Abs(index[1].Value-110) > 1e-9 {
+		t.Errorf("Expected values rebased to the first, got %+v", index)
+	}
+
+	logged := Transform([]store.SeriesPoint{{Date: "2024-01-01", Value: -1}, {Date: "2024-01-02", Value: math.E}}, TransformLog)
+	if len(logged) != 1 || logged[0].Value != 1 {
+		t.Errorf("Expected non-positive values dropped from log, got %+v", logged)
+	}
+}
+
+func TestAlignObservations(t *testing.T) {
+	aligned := AlignObservations(map[string][]store.SeriesPoint{
+		"A": {{Date: "2024-01-01", Value: 1}, {Date: "2024-01-03", Value: 3}},
+		"B": {{Date: "2024-01-02", Value: 20}, {Date: "2024-01-03", Value: 30}},
+	})
+
+	if len(aligned) != 3 || aligned[0].Date != "2024-01-01" || aligned[2].Date != "2024-01-03" {
+		t.Fatalf("Expected the union of dates oldest first, got %+v", aligned)
+	}
+	if aligned[0].Values["B"] != nil || *aligned[0].Values["A"] != 1 {
+		t.Errorf("Expected B missing on 2024-01-01, got %+v", aligned[0].Values)
+	}
+	if *aligned[2].Values["A"] != 3 || *aligned[2].Values["B"] != 30 {
+		t.Errorf("Expected both values on 2024-01-03, got %+v", aligned[2].Values)
+	}
+}
+
+func TestLoadObservationsPages(t *testing.T) {
+	db := newTestStore(t)
+
+	for i, date := range []string{"2023-12-29", "2024-01-02", "2024-01-31", "2024-02-15", "2024-02-29", "2024-03-28"} {
+		savePoint(t, db, "DTWEXBGS", date, float64(100+i))
+	}
+
+	q := ObservationQuery{Start: "2024-01-01", Frequency: FrequencyMonthly, Transform: TransformPctChange, Limit: 2}
+	first, err := LoadObservations(db, "DTWEXBGS", q)
+	if err != nil {
+		t.Fatalf("Failed to load observations: %v", err)
+	}
+	if len(first) != 2 || first[0].Date != "2024-01-31" || math.Abs(first[0].Value-2) > 1e-9 {
+		t.Fatalf("Expected January's change from December before start, got %+v", first)
+	}
+
+	q.After = first[1].Date
+	next, err := LoadObservations(db, "DTWEXBGS", q)
+	if err != nil {
+		t.Fatalf("Failed to load next page: %v", err)
+	}
+	if len(next) != 1 || next[0].Date != "2024-03-31" || math.Abs(next[0].Value-(105.0/104-1)*100) > 1e-9 {
+		t.Errorf("Expected March on the next page, got %+v", next)
+	}
+
+	if err := (ObservationQuery{Aggregation: AggregateMean}).Validate(); err == nil {
+		t.Error("Expected error for an aggregation without a frequency")
+	}
+	if err := (ObservationQuery{Start: "2024-13-01"}).Validate(); err == nil {
+		t.Error("Expected error for an invalid start date")
+	}
+}
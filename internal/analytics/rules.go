@@ -0,0 +1,383 @@
+package analytics
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+// defaultRulesJSON is used when no SIGNAL_RULES_FILE is configured
+//
+//go:embed signal_rules.json
+var defaultRulesJSON []byte
+
+// Band classifies values between Min and Max (both inclusive, either may be omitted).
+// A band without bounds matches everything and is normally the last one.
+type Band struct {
+	Min         *float64     `json:"min,omitempty"`
+	Max         *float64     `json:"max,omitempty"`
+	Status      SignalStatus `json:"status"`
+	Why         string       `json:"why"`
+	Action      string       `json:"action,omitempty"`
+	ActionLabel string       `json:"action_label,omitempty"`
+}
+
+func (b Band) matches(v float64) bool {
+	return (b.Min == nil || v >= *b.Min) && (b.Max == nil || v <= *b.Max)
+}
+
+// SeriesRule holds the level bands for one series, evaluated in order (the first match
+// wins), plus dynamic rules that can escalate the band's status
+type SeriesRule struct {
+	Key         string        `json:"key"` // key in GetAllSignals' result
+	SeriesID    string        `json:"series_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Bands       []Band        `json:"bands"`
+	Dynamic     []DynamicRule `json:"dynamic,omitempty"`
+}
+
+// Dynamic rule types, measured over the last Window observations
+const (
+	RuleLevel     = "level"      // the latest value itself (bands)
+	RulePctChange = "pct_change" // percent change vs Window observations ago
+	RuleMomentum  = "momentum"   // absolute change vs Window observations ago
+	RuleZScore    = "zscore"     // standard deviations from the mean of the previous Window observations
+)
+
+// DynamicRule flags moves the level bands miss, e.g. a 15% VIX spike in two sessions.
+// Abs compares the magnitude so one rule catches moves in either direction.
+type DynamicRule struct {
+	ID          string       `json:"id"`
+	Type        string       `json:"type"`
+	Window      int          `json:"window"`
+	Min         *float64     `json:"min,omitempty"`
+	Max         *float64     `json:"max,omitempty"`
+	Abs         bool         `json:"abs,omitempty"`
+	Status      SignalStatus `json:"status"`
+	Why         string       `json:"why"`
+	Action      string       `json:"action,omitempty"`
+	ActionLabel string       `json:"action_label,omitempty"`
+}
+
+// measure computes the rule's metric from points, newest first. ok is false when
+// there isn't enough history.
+func (d DynamicRule) measure(points []store.SeriesPoint) (float64, bool) {
+	if len(points) <= d.Window {
+		return 0, false
+	}
+	latest := points[0].Value
+
+	switch d.Type {
+	case RulePctChange:
+		base := points[d.Window].Value
+		if base == 0 {
+			return 0, false
+		}
+		return (latest - base) / math.Abs(base) * 100, true
+	case RuleMomentum:
+		return latest - points[d.Window].Value, true
+	case RuleZScore:
+		var mean, variance float64
+		previous := points[1 : d.Window+1]
+		for _, p := range previous {
+			mean += p.Value
+		}
+		mean /= float64(len(previous))
+		for _, p := range previous {
+			variance += (p.Value - mean) * (p.Value - mean)
+		}
+		stddev := math.Sqrt(variance / float64(len(previous)-1))
+		if stddev == 0 {
+			return 0, false
+		}
+		return (latest - mean) / stddev, true
+	}
+	return 0, false
+}
+
+func (d DynamicRule) fires(metric float64) bool {
+	if d.Abs {
+		metric = math.Abs(metric)
+	}
+	return (d.Min == nil || metric >= *d.Min) && (d.Max == nil || metric <= *d.Max)
+}
+
+// historyNeeded is the number of observations, newest first, the rule's dynamic checks need
+func (r SeriesRule) historyNeeded() int {
+	n := 1
+	for _, d := range r.Dynamic {
+		if d.Window+1 > n {
+			n = d.Window + 1
+		}
+	}
+	return n
+}
+
+// levelRuleID names the rule reported when a level band sets the status
+func (r SeriesRule) levelRuleID() string {
+	return r.Key + "_level"
+}
+
+// Match returns the first band containing v
+func (r SeriesRule) Match(v float64) (Band, bool) {
+	for _, b := range r.Bands {
+		if b.matches(v) {
+			return b, true
+		}
+	}
+	return Band{}, false
+}
+
+// RuleSet is the declarative signal configuration shared by the API, homepage,
+// Trigger Watch and Crash-Drill
+type RuleSet struct {
+	Actions map[string]string `json:"actions"` // action -> URL
+	Series  []SeriesRule      `json:"series"`
+}
+
+// ParseRules decodes and validates a rules file
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("invalid rules JSON: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range rs.Series {
+		if r.Key == "" || r.SeriesID == "" {
+			return nil, fmt.Errorf("rule needs both key and series_id: %+v", r)
+		}
+		if seen[r.Key] {
+			return nil, fmt.Errorf("duplicate rule key %q", r.Key)
+		}
+		seen[r.Key] = true
+
+		if len(r.Bands) == 0 {
+			return nil, fmt.Errorf("%s: no bands", r.Key)
+		}
+		for _, b := range r.Bands {
+			if err := rs.validateOutcome(r.Key, b.Status, b.Action); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, d := range r.Dynamic {
+			if d.ID == "" || seen[d.ID] {
+				return nil, fmt.Errorf("%s: dynamic rule needs a unique id, got %q", r.Key, d.ID)
+			}
+			seen[d.ID] = true
+
+			switch {
+			case d.Type != RulePctChange && d.Type != RuleMomentum && d.Type != RuleZScore:
+				return nil, fmt.Errorf("%s: unknown rule type %q", d.ID, d.Type)
+			case d.Window < 1 || (d.Type == RuleZScore && d.Window < 2):
+				return nil, fmt.Errorf("%s: window %d too small for %s", d.ID, d.Window, d.Type)
+			case d.Min == nil && d.Max == nil:
+				return nil, fmt.Errorf("%s: needs min or max", d.ID)
+			}
+			if err := rs.validateOutcome(d.ID, d.Status, d.Action); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &rs, nil
+}
+
+func (rs *RuleSet) validateOutcome(name string, status SignalStatus, action string) error {
+	if severity(status) < 0 {
+		return fmt.Errorf("%s: unknown status %q", name, status)
+	}
+	if action != "" && action != "none" {
+		if _, ok := rs.Actions[action]; !ok {
+			return fmt.Errorf("%s: action %q has no URL", name, action)
+		}
+	}
+	return nil
+}
+
+// severity orders statuses so a dynamic rule only escalates a signal
+func severity(status SignalStatus) int {
+	switch status {
+	case StatusGood:
+		return 0
+	case StatusNeutral:
+		return 1
+	case StatusWatch:
+		return 2
+	case StatusCrisis:
+		return 3
+	}
+	return -1
+}
+
+// Rule returns the rule for a series ID
+func (rs *RuleSet) Rule(seriesID string) (SeriesRule, bool) {
+	for _, r := range rs.Series {
+		if r.SeriesID == seriesID {
+			return r, true
+		}
+	}
+	return SeriesRule{}, false
+}
+
+// Evaluate classifies a value with the series' level bands. Series without a rule,
+// or values no band matches, are neutral.
+func (rs *RuleSet) Evaluate(seriesID string, value float64, asOf string) Signal {
+	return rs.EvaluateHistory(seriesID, []store.SeriesPoint{{Date: asOf, Value: value}})
+}
+
+// EvaluateHistory classifies the latest of points (newest first) with the level bands,
+// then lets any dynamic rule that fires escalate the status. Signal.Rule names the
+// rule that set the final status.
+func (rs *RuleSet) EvaluateHistory(seriesID string, points []store.SeriesPoint) Signal {
+	if len(points) == 0 {
+		return Signal{SeriesID: seriesID, Status: StatusNeutral, Action: "none"}
+	}
+
+	signal := Signal{
+		SeriesID: seriesID,
+		Value:    points[0].Value,
+		AsOf:     points[0].Date,
+		Status:   StatusNeutral,
+		Action:   "none",
+	}
+
+	rule, ok := rs.Rule(seriesID)
+	if !ok {
+		return signal
+	}
+
+	if band, ok := rule.Match(signal.Value); ok {
+		signal.apply(band.Status, band.Why, band.Action, band.ActionLabel)
+		signal.Rule = rule.levelRuleID()
+		signal.RuleType = RuleLevel
+		signal.RuleValue = signal.Value
+	}
+
+	for _, d := range rule.Dynamic {
+		metric, ok := d.measure(points)
+		if !ok || !d.fires(metric) || severity(d.Status) <= severity(signal.Status) {
+			continue
+		}
+		signal.apply(d.Status, d.Why, d.Action, d.ActionLabel)
+		signal.Rule = d.ID
+		signal.RuleType = d.Type
+		signal.RuleValue = math.Round(metric*100) / 100
+	}
+
+	return signal
+}
+
+func (s *Signal) apply(status SignalStatus, why, action, actionLabel string) {
+	s.Status = status
+	s.Why = why
+	s.Action, s.ActionLabel = "none", ""
+	if action != "" {
+		s.Action, s.ActionLabel = action, actionLabel
+	}
+}
+
+// EvaluateLatest loads enough recent history for the series' rules and evaluates it.
+// ok is false when the series has no data.
+func (rs *RuleSet) EvaluateLatest(db *store.Store, seriesID string) (Signal, bool) {
+	n := 1
+	if rule, ok := rs.Rule(seriesID); ok {
+		n = rule.historyNeeded()
+	}
+
+	points, err := db.GetRecentPoints(seriesID, n)
+	if err != nil || len(points) == 0 {
+		return Signal{}, false
+	}
+	return rs.EvaluateHistory(seriesID, points), true
+}
+
+// ActionURL returns the URL for an action, or "" for none
+func (rs *RuleSet) ActionURL(action string) string {
+	return rs.Actions[action]
+}
+
+// ruleLoader holds the active rule set, reloading the rules file when it changes
+type ruleLoader struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	set     *RuleSet
+}
+
+var rules = &ruleLoader{}
+
+// SetRulesFile switches signal evaluation to a rules file on disk. The file is
+// re-read whenever its modification time changes, so thresholds can be tuned
+// without a redeploy. An empty path restores the built-in rules.
+func SetRulesFile(path string) error {
+	rules.mu.Lock()
+	defer rules.mu.Unlock()
+
+	if path == "" {
+		rules.path, rules.modTime, rules.set = "", time.Time{}, nil
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read rules file: %w", err)
+	}
+	set, err := loadRulesFile(path)
+	if err != nil {
+		return err
+	}
+
+	rules.path, rules.modTime, rules.set = path, info.ModTime(), set
+	return nil
+}
+
+// Rules returns the active rule set
+func Rules() *RuleSet {
+	rules.mu.Lock()
+	defer rules.mu.Unlock()
+
+	if rules.path == "" {
+		if rules.set == nil {
+			set, err := ParseRules(defaultRulesJSON)
+			if err != nil {
+				panic(fmt.Sprintf("built-in signal rules are invalid: %v", err))
+			}
+			rules.set = set
+		}
+		return rules.set
+	}
+
+	// A broken edit keeps the last good rules in effect
+	if info, err := os.Stat(rules.path); err == nil && !info.ModTime().Equal(rules.modTime) {
+		if set, err := loadRulesFile(rules.path); err != nil {
+			util.ErrorLogger.Printf("Keeping previous signal rules: %v", err)
+		} else {
+			util.InfoLogger.Printf("Reloaded signal rules from %s", rules.path)
+			rules.set = set
+		}
+		rules.modTime = info.ModTime()
+	}
+	return rules.set
+}
+
+func loadRulesFile(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	set, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return set, nil
+}
@@ -0,0 +1,148 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+func TestDefaultRules(t *testing.T) {
+	tests := []struct {
+		seriesID string
+		value    float64
+		status   SignalStatus
+		action   string
+	}{
+		{"DTWEXBGS", 126, StatusCrisis, "open_hedge_checklist"},
+		{"DTWEXBGS", 115, StatusNeutral, "none"},
+		{"DTWEXBGS", 110, StatusGood, "none"},
+		{"COFER_CNY", 3.0, StatusWatch, "rmb_settlement_readiness"},
+		{"SWIFT_RMB", 3.2, StatusNeutral, "rmb_settlement_readiness"},
+		{"VIXCLS", 19.9, StatusGood, "none"},
+		{"VIXCLS", 20, StatusWatch, "prepare_checklist"},
+		{"BAMLC0A4CBBB", 450, StatusCrisis, "open_crash_drill"},
+		{"WGC_CB_PURCHASES", 290, StatusWatch, "gold_proof_pack"},
+		{"WGC_CB_PURCHASES", 200, StatusNeutral, "none"},
+		{"UNKNOWN", 1, StatusNeutral, "none"},
+	}
+
+	for _, tt := range tests {
+		s := EvaluateSignal(tt.seriesID, tt.value, "2024-01-01")
+		if s.Status != tt.status || s.Action != tt.action {
+			t.Errorf("%s=%.1f: expected %s/%s, got %s/%s", tt.seriesID, tt.value, tt.status, tt.action, s.Status, s.Action)
+		}
+	}
+
+	if url := GetActionURL("open_crash_drill"); url != "/crash-drill" {
+		t.Errorf("Expected /crash-drill, got %q", url)
+	}
+}
+
+func TestRulesFileReload(t *testing.T) {
+	util.InitLogger("info")
+	t.Cleanup(func() { SetRulesFile("") })
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write rules: %v", err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	write(`{"series": [{"key": "vix", "series_id": "VIXCLS", "bands": [{"min": 15, "status": "watch", "why": "tuned"}, {"status": "good"}]}]}`, base)
+	if err := SetRulesFile(path); err != nil {
+		t.Fatalf("Failed to load rules file: %v", err)
+	}
+
+	if s := EvaluateSignal("VIXCLS", 16, ""); s.Status != StatusWatch || s.Why != "tuned" {
+		t.Errorf("Expected tuned watch threshold, got %+v", s)
+	}
+
+	// Edits are picked up without a restart
+	write(`{"series": [{"key": "vix", "series_id": "VIXCLS", "bands": [{"min": 18, "status": "watch"}, {"status": "good"}]}]}`, base.Add(time.Minute))
+	if s := EvaluateSignal("VIXCLS", 16, ""); s.Status != StatusGood {
+		t.Errorf("Expected reloaded rules, got %+v", s)
+	}
+
+	// A broken edit keeps the last good rules
+	write(`{"series": [{"key": "vix", "series_id": "VIXCLS", "bands": [{"status": "panic"}]}]}`, base.Add(2*time.Minute))
+	if s := EvaluateSignal("VIXCLS", 19, ""); s.Status != StatusWatch {
+		t.Errorf("Expected previous rules after invalid edit, got %+v", s)
+	}
+}
+
+func TestParseRulesRejectsUnknownAction(t *testing.T) {
+	_, err := ParseRules([]byte(`{"series": [{"key": "vix", "series_id": "VIXCLS", "bands": [{"status": "watch", "action": "panic_button"}]}]}`))
+	if err == nil {
+		t.Error("Expected error for an action without a URL")
+	}
+}
+
+// history builds points newest first from values oldest first
+func history(values ...float64) []store.SeriesPoint {
+	points := make([]store.SeriesPoint, len(values))
+	for i, v := range values {
+		points[len(values)-1-i] = store.SeriesPoint{Date: fmt.Sprintf("2024-01-%02d", i+1), Value: v}
+	}
+	return points
+}
+
+func TestDynamicRules(t *testing.T) {
+	rs, err := ParseRules([]byte(`{"series": [{"key": "vix", "series_id": "VIXCLS",
+		"bands": [{"min": 30, "status": "crisis"}, {"status": "good", "why": "calm"}],
+		"dynamic": [
+			{"id": "spike", "type": "pct_change", "window": 2, "min": 15, "status": "watch", "why": "spike"},
+			{"id": "drift", "type": "momentum", "window": 3, "min": 5, "abs": true, "status": "watch"},
+			{"id": "sigma", "type": "zscore", "window": 5, "min": 3, "abs": true, "status": "crisis", "why": "3 sigma"}
+		]}]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse rules: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		points []store.SeriesPoint
+		status SignalStatus
+		rule   string
+		value  float64
+	}{
+		{"level only", history(14, 14.5, 15), StatusGood, "vix_level", 15},
+		{"pct change", history(15, 16, 17.4), StatusWatch, "spike", 16},
+		{"falling momentum", history(20, 18, 16, 14.5), StatusWatch, "drift", -5.5},
+		{"z-score escalates past watch", history(15, 15.2, 14.8, 15.1, 14.9, 21), StatusCrisis, "sigma", 37.95},
+		{"not enough history", history(15, 17.4), StatusGood, "vix_level", 17.4},
+		{"level crisis is not downgraded", history(40, 31, 30.5), StatusCrisis, "vix_level", 30.5},
+	}
+
+	for _, tt := range tests {
+		s := rs.EvaluateHistory("VIXCLS", tt.points)
+		if s.Status != tt.status || s.Rule != tt.rule || s.RuleValue != tt.value {
+			t.Errorf("%s: expected %s by %s (%.2f), got %s by %s (%.2f)", tt.name, tt.status, tt.rule, tt.value, s.Status, s.Rule, s.RuleValue)
+		}
+	}
+}
+
+func TestGetAllSignalsUsesHistory(t *testing.T) {
+	db := newTestStore(t)
+
+	for i, v := range []float64{14, 15, 18} {
+		savePoint(t, db, "VIXCLS", fmt.Sprintf("2024-01-%02d", i+1), v)
+	}
+
+	signals, err := GetAllSignals(db)
+	if err != nil {
+		t.Fatalf("Failed to get signals: %v", err)
+	}
+
+	vix := signals["vix"]
+	if vix.Status != StatusWatch || vix.Rule != "vix_spike_2d" || vix.RuleType != RulePctChange {
+		t.Errorf("Expected VIX spike rule to fire, got %+v", vix)
+	}
+}
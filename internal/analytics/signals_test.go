@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"testing"
+
+	"reserve-watch/internal/store"
+)
+
+func TestRecordSignalTransitions(t *testing.T) {
+	db := newTestStore(t)
+
+	savePoint(t, db, "VIXCLS", "2024-01-02", 15)
+	savePoint(t, db, "BAMLC0A4CBBB", "2024-01-02", 150)
+
+	recorded, err := RecordSignalTransitions(db)
+	if err != nil {
+		t.Fatalf("Failed to record transitions: %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("Expected initial state for 2 signals, got %+v", recorded)
+	}
+
+	// Same data, no new transitions
+	if recorded, _ = RecordSignalTransitions(db); len(recorded) != 0 {
+		t.Errorf("Expected no transitions without a status change, got %+v", recorded)
+	}
+
+	savePoint(t, db, "VIXCLS", "2024-01-03", 31)
+	recorded, err = RecordSignalTransitions(db)
+	if err != nil {
+		t.Fatalf("Failed to record transitions: %v", err)
+	}
+	if len(recorded) != 1 {
+		t.Fatalf("Expected one VIX transition, got %+v", recorded)
+	}
+
+	vix := recorded[0]
+	if vix.SignalKey != "vix" || vix.FromStatus != "good" || vix.ToStatus != "crisis" || vix.Rule != "vix_level" || vix.AsOf != "2024-01-03" {
+		t.Errorf("Unexpected transition %+v", vix)
+	}
+
+	history, _ := db.GetSignalTransitions(store.SignalTransitionFilter{SignalKey: "vix"})
+	if len(history) != 2 {
+		t.Errorf("Expected 2 recorded VIX states, got %d", len(history))
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,12 @@ type Config struct {
 	LogLevel   string
 	DryRun     bool
 
+	IngestConcurrency int
+	WGCDemandURL      string
+	WGCReservesURL    string
+	WGCManualDir      string
+	SignalRulesFile   string
+
 	LinkedInAccessToken string
 	LinkedInOrgURN      string
 	MailchimpAPIKey     string
@@ -30,6 +37,15 @@ type Config struct {
 	SendGridFromEmail  string
 	SendGridFromName   string
 
+	// Alert delivery. Alert emails go through SendGrid when SENDGRID_API_KEY is set,
+	// otherwise through SMTP_HOST, both from SENDGRID_FROM_EMAIL/NAME.
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string
+	SMTPPassword    string
+	SMSGatewayURL   string
+	SMSGatewayToken string
+
 	PublishLinkedIn  bool
 	PublishMailchimp bool
 	AutoPublish      bool
@@ -45,6 +61,12 @@ func Load() (*Config, error) {
 		LogLevel:   getEnv("LOG_LEVEL", "info"),
 		DryRun:     getEnvBool("DRY_RUN", true),
 
+		IngestConcurrency: getEnvInt("INGEST_CONCURRENCY", 3),
+		WGCDemandURL:      getEnv("WGC_DEMAND_URL", ""),
+		WGCReservesURL:    getEnv("WGC_RESERVES_URL", ""),
+		WGCManualDir:      getEnv("WGC_MANUAL_DIR", "data/wgc"),
+		SignalRulesFile:   getEnv("SIGNAL_RULES_FILE", ""),
+
 		LinkedInAccessToken: getEnv("LINKEDIN_ACCESS_TOKEN", ""),
 		LinkedInOrgURN:      getEnv("LINKEDIN_ORG_URN", ""),
 		MailchimpAPIKey:     getEnv("MAILCHIMP_API_KEY", ""),
@@ -61,6 +83,13 @@ func Load() (*Config, error) {
 		SendGridFromEmail:  getEnv("SENDGRID_FROM_EMAIL", "alerts@reserve.watch"),
 		SendGridFromName:   getEnv("SENDGRID_FROM_NAME", "Reserve Watch"),
 
+		SMTPHost:        getEnv("SMTP_HOST", ""),
+		SMTPPort:        getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+		SMSGatewayURL:   getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken: getEnv("SMS_GATEWAY_TOKEN", ""),
+
 		PublishLinkedIn:  getEnvBool("PUBLISH_LINKEDIN", false),
 		PublishMailchimp: getEnvBool("PUBLISH_MAILCHIMP", false),
 		AutoPublish:      getEnvBool("AUTOPUBLISH", false),
@@ -80,6 +109,18 @@ func getEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+func getEnvInt(key string, defaultVal int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return defaultVal
+	}
+	return n
+}
+
 func getEnvBool(key string, defaultVal bool) bool {
 	val := os.Getenv(key)
 	if val == "" {
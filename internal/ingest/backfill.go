@@ -0,0 +1,272 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+// RangeFunc fetches all points for a series between two dates (YYYY-MM-DD, inclusive)
+type RangeFunc func(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error)
+
+// Backfiller is implemented by sources that can fetch historical date ranges
+type Backfiller interface {
+	Source
+	FetchRange(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error)
+}
+
+type backfillSource struct {
+	Source
+	fetchRange RangeFunc
+	allSeries  bool // fetchRange returns every series of the source, whatever seriesID
+}
+
+func (s *backfillSource) FetchRange(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error) {
+	return s.fetchRange(ctx, seriesID, start, end)
+}
+
+// WithBackfill adds historical range fetching to a source
+func WithBackfill(src Source, fetchRange RangeFunc) Backfiller {
+	return &backfillSource{Source: src, fetchRange: fetchRange}
+}
+
+// WithSourceBackfill adds historical range fetching to a source whose API returns
+// all of its series at once. Backfills fetch each page once for every series.
+func WithSourceBackfill(src Source, fetchRange func(ctx context.Context, start, end string) ([]store.SeriesPoint, error)) Backfiller {
+	return &backfillSource{Source: src, allSeries: true, fetchRange: func(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error) {
+		return fetchRange(ctx, start, end)
+	}}
+}
+
+// fetchesAllSeries reports whether one FetchRange call returns every series of src
+func fetchesAllSeries(src Backfiller) bool {
+	bs, ok := src.(*backfillSource)
+	return ok && bs.allSeries
+}
+
+// BackfillOptions controls a historical backfill run
+type BackfillOptions struct {
+	SeriesIDs []string      // limit to these series; empty means all backfillable series
+	Start     string        // override each series' HistoryStart (YYYY-MM-DD)
+	End       string        // defaults to today
+	Restart   bool          // ignore recorded progress and start over
+	Pause     time.Duration // delay between page requests, to stay within rate limits
+}
+
+// Backfill pages through full history for every backfillable series in the registry.
+// Coverage is recorded after each page so an interrupted run resumes where it stopped;
+// re-running after completion only fetches dates since the last covered day.
+func Backfill(ctx context.Context, db *store.Store, registry *Registry, opts BackfillOptions) error {
+	if opts.End == "" {
+		opts.End = time.Now().UTC().Format("2006-01-02")
+	}
+
+	wanted := make(map[string]bool)
+	for _, id := range opts.SeriesIDs {
+		if _, _, ok := registry.Lookup(id); !ok {
+			return fmt.Errorf("unknown series %s", id)
+		}
+		wanted[id] = true
+	}
+
+	for _, src := range registry.Sources() {
+		bf, isBackfiller := src.(Backfiller)
+
+		var targets []*backfillTarget
+		for _, spec := range src.Series() {
+			if len(wanted) > 0 && !wanted[spec.ID] {
+				continue
+			}
+
+			if !isBackfiller || spec.HistoryStart == "" {
+				if wanted[spec.ID] {
+					util.InfoLogger.Printf("%s: source %s has no historical API, skipping", spec.ID, src.Name())
+				}
+				continue
+			}
+
+			target, err := newBackfillTarget(db, spec, opts)
+			if err != nil {
+				return fmt.Errorf("backfill %s: %w", spec.ID, err)
+			}
+			if target != nil {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+
+		if fetchesAllSeries(bf) {
+			if err := backfillPages(ctx, db, bf, targets, opts); err != nil {
+				return fmt.Errorf("backfill %s: %w", src.Name(), err)
+			}
+			continue
+		}
+		for _, target := range targets {
+			if err := backfillPages(ctx, db, bf, []*backfillTarget{target}, opts); err != nil {
+				return fmt.Errorf("backfill %s: %w", target.spec.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backfillTarget is one series' progress through a backfill run
+type backfillTarget struct {
+	spec     SeriesSpec
+	from     string // first date still to fetch
+	progress *store.BackfillProgress
+}
+
+// newBackfillTarget picks up a series' recorded progress, or returns nil if it is
+// already covered through opts.End
+func newBackfillTarget(db *store.Store, spec SeriesSpec, opts BackfillOptions) (*backfillTarget, error) {
+	start := spec.HistoryStart
+	if opts.Start != "" {
+		start = opts.Start
+	}
+
+	progress, err := db.GetBackfillProgress(spec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := start
+	if progress == nil || opts.Restart || progress.StartDate > start {
+		progress = &store.BackfillProgress{SeriesID: spec.ID, StartDate: start}
+	} else {
+		next, err := addDays(progress.CoveredThrough, 1)
+		if err != nil {
+			return nil, err
+		}
+		from = next
+		progress.CompletedAt = nil // incomplete again until this run finishes
+		util.InfoLogger.Printf("%s: resuming after %s (%d points saved so far)", spec.ID, progress.CoveredThrough, progress.PointsSaved)
+	}
+
+	if from > opts.End {
+		util.InfoLogger.Printf("%s: already covered through %s", spec.ID, progress.CoveredThrough)
+		return nil, nil
+	}
+	return &backfillTarget{spec: spec, from: from, progress: progress}, nil
+}
+
+// backfillPages pages from the earliest target's start to opts.End. Each page is
+// fetched once and saved for every target it reaches; with several targets src must
+// return all of them from one FetchRange call.
+func backfillPages(ctx context.Context, db *store.Store, src Backfiller, targets []*backfillTarget, opts BackfillOptions) error {
+	from := targets[0].from
+	window := backfillWindow(targets[0].spec.Frequency)
+	for _, t := range targets[1:] {
+		if t.from < from {
+			from = t.from
+		}
+		if w := backfillWindow(t.spec.Frequency); w < window {
+			window = w
+		}
+	}
+
+	for from <= opts.End {
+		to, err := addYears(from, window)
+		if err != nil {
+			return err
+		}
+		to, _ = addDays(to, -1)
+		if to > opts.End {
+			to = opts.End
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Pages are fetched once, so there's nothing to revalidate later
+		pageCtx, cancel := context.WithTimeout(withoutCache(ctx), src.Timeout())
+		points, err := src.FetchRange(pageCtx, targets[0].spec.ID, from, to)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("fetch %s..%s: %w", from, to, err)
+		}
+
+		grouped, err := GroupBySeries(src, points)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range targets {
+			if t.from > to {
+				continue
+			}
+
+			// Skip what an earlier run already covered for this series
+			var batch []store.SeriesPoint
+			for _, p := range grouped[t.spec.ID] {
+				if p.Date >= t.from {
+					batch = append(batch, p)
+				}
+			}
+			if len(batch) > 0 {
+				if err := db.SavePoints(t.spec.ID, batch, time.Time{}); err != nil {
+					return err
+				}
+			}
+
+			t.progress.CoveredThrough = to
+			t.progress.PointsSaved += len(batch)
+			if err := db.SaveBackfillProgress(t.progress); err != nil {
+				return err
+			}
+			util.InfoLogger.Printf("%s: %s..%s saved %d points", t.spec.ID, from, to, len(batch))
+		}
+
+		from, _ = addDays(to, 1)
+		if opts.Pause > 0 && from <= opts.End {
+			select {
+			case <-time.After(opts.Pause):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, t := range targets {
+		t.progress.CompletedAt = &now
+		if err := db.SaveBackfillProgress(t.progress); err != nil {
+			return err
+		}
+		util.InfoLogger.Printf("%s: backfill complete %s..%s (%d points)", t.spec.ID, t.progress.StartDate, t.progress.CoveredThrough, t.progress.PointsSaved)
+	}
+	return nil
+}
+
+// backfillWindow returns the page size in years for a series frequency
+func backfillWindow(frequency string) int {
+	switch frequency {
+	case "daily", "intraday":
+		return 1
+	default:
+		return 10
+	}
+}
+
+func addDays(date string, days int) (string, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	return t.AddDate(0, 0, days).Format("2006-01-02"), nil
+}
+
+func addYears(date string, years int) (string, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", date, err)
+	}
+	return t.AddDate(years, 0, 0).Format("2006-01-02"), nil
+}
@@ -0,0 +1,142 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"reserve-watch/internal/store"
+)
+
+func TestBackfillResumes(t *testing.T) {
+	db := newTestStore(t)
+
+	var calls []string
+	failAfter := 1
+
+	spec := SeriesSpec{ID: "HIST", Frequency: "daily", HistoryStart: "2020-01-01"}
+	latestOnly := func(ctx context.Context) ([]store.SeriesPoint, error) { return nil, nil }
+
+	registry := NewRegistry()
+	registry.MustRegister(WithBackfill(NewSource("hist", "", 0, []SeriesSpec{spec}, latestOnly),
+		func(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error) {
+			if len(calls) == failAfter {
+				return nil, errors.New("connection reset")
+			}
+			calls = append(calls, start+".."+end)
+			return []store.SeriesPoint{
+				{Date: start, Value: 1},
+				{Date: end, Value: 2},
+			}, nil
+		}))
+
+	opts := BackfillOptions{End: "2022-06-30"}
+
+	if err := Backfill(context.Background(), db, registry, opts); err == nil {
+		t.Fatal("Expected interrupted backfill to return an error")
+	}
+
+	progress, err := db.GetBackfillProgress("HIST")
+	if err != nil || progress == nil {
+		t.Fatalf("Expected progress to be recorded: %v", err)
+	}
+
+	if progress.CoveredThrough != "2020-12-31" || progress.CompletedAt != nil {
+		t.Errorf("Expected partial coverage through 2020-12-31, got %+v", progress)
+	}
+
+	failAfter = -1
+	if err := Backfill(context.Background(), db, registry, opts); err != nil {
+		t.Fatalf("Failed to resume backfill: %v", err)
+	}
+
+	expected := []string{"2020-01-01..2020-12-31", "2021-01-01..2021-12-31", "2022-01-01..2022-06-30"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected call %d to be %s, got %s", i, expected[i], calls[i])
+		}
+	}
+
+	progress, _ = db.GetBackfillProgress("HIST")
+	if progress.CompletedAt == nil || progress.PointsSaved != 6 {
+		t.Errorf("Expected completed backfill with 6 points, got %+v", progress)
+	}
+
+	// Nothing left to fetch
+	calls = nil
+	if err := Backfill(context.Background(), db, registry, opts); err != nil {
+		t.Fatalf("Failed to rerun backfill: %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected no fetches once covered, got %v", calls)
+	}
+
+	// Extending the range resumes the series; until it finishes it isn't complete
+	failAfter = 1
+	opts.End = "2024-06-30"
+	if err := Backfill(context.Background(), db, registry, opts); err == nil {
+		t.Fatal("Expected interrupted backfill to return an error")
+	}
+	progress, _ = db.GetBackfillProgress("HIST")
+	if progress.CoveredThrough != "2023-06-30" || progress.CompletedAt != nil {
+		t.Errorf("Expected incomplete coverage through 2023-06-30, got %+v", progress)
+	}
+}
+
+func TestBackfillMultiSeriesSourceFetchesEachPageOnce(t *testing.T) {
+	db := newTestStore(t)
+
+	specs := []SeriesSpec{
+		{ID: "HIST_A", Frequency: "quarterly", HistoryStart: "2000-01-01"},
+		{ID: "HIST_B", Frequency: "quarterly", HistoryStart: "2005-01-01"},
+	}
+	latestOnly := func(ctx context.Context) ([]store.SeriesPoint, error) { return nil, nil }
+
+	var calls []string
+	registry := NewRegistry()
+	registry.MustRegister(WithSourceBackfill(NewSource("hist", "", 0, specs, latestOnly),
+		func(ctx context.Context, start, end string) ([]store.SeriesPoint, error) {
+			calls = append(calls, start+".."+end)
+			var points []store.SeriesPoint
+			for _, spec := range specs {
+				for _, date := range []string{start, end} {
+					points = append(points, store.SeriesPoint{Date: date, Value: 1, Meta: map[string]string{"series_id": spec.ID}})
+				}
+			}
+			return points, nil
+		}))
+
+	// HIST_B was interrupted partway through the second page
+	if err := db.SaveBackfillProgress(&store.BackfillProgress{SeriesID: "HIST_B", StartDate: "2005-01-01", CoveredThrough: "2011-12-31", PointsSaved: 3}); err != nil {
+		t.Fatalf("Failed to save progress: %v", err)
+	}
+
+	if err := Backfill(context.Background(), db, registry, BackfillOptions{End: "2019-12-31"}); err != nil {
+		t.Fatalf("Failed to backfill: %v", err)
+	}
+
+	expected := []string{"2000-01-01..2009-12-31", "2010-01-01..2019-12-31"}
+	if len(calls) != len(expected) || calls[0] != expected[0] || calls[1] != expected[1] {
+		t.Errorf("Expected one fetch per page %v, got %v", expected, calls)
+	}
+
+	a, _ := db.GetBackfillProgress("HIST_A")
+	b, _ := db.GetBackfillProgress("HIST_B")
+	if a == nil || a.CompletedAt == nil || a.PointsSaved != 4 || a.CoveredThrough != "2019-12-31" {
+		t.Errorf("Expected HIST_A complete with 4 points, got %+v", a)
+	}
+	if b == nil || b.CompletedAt == nil || b.PointsSaved != 4 || b.CoveredThrough != "2019-12-31" {
+		t.Errorf("Expected HIST_B complete with 1 new point, got %+v", b)
+	}
+
+	points, err := db.GetPoints("HIST_B", store.PointFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+	if len(points) != 1 || points[0].Date != "2019-12-31" {
+		t.Errorf("Expected only HIST_B's point after its resume date, got %+v", points)
+	}
+}
@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,15 +18,13 @@ type CIPSClient struct {
 
 func NewCIPSClient() *CIPSClient {
 	return &CIPSClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: newHTTPClient(30 * time.Second),
 	}
 }
 
 // FetchCIPSStats scrapes CIPS website for network statistics
 // CIPS (Cross-Border Interbank Payment System) is China's international payment system
-func (c *CIPSClient) FetchCIPSStats() (map[string]float64, error) {
+func (c *CIPSClient) FetchCIPSStats(ctx context.Context) (map[string]float64, error) {
 	// CIPS publishes stats on their website: https://www.cips.com.cn/en/
 	// Key metrics:
 	// 1. Number of participants (direct + indirect)
@@ -34,7 +33,12 @@ func (c *CIPSClient) FetchCIPSStats() (map[string]float64, error) {
 
 	url := "https://www.cips.com.cn/en/index/index.html"
 
-	resp, err := c.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch CIPS data: %w", err)
 	}
@@ -84,8 +88,8 @@ func (c *CIPSClient) FetchCIPSStats() (map[string]float64, error) {
 }
 
 // GetCIPSSeriesPoints converts CIPS stats to series points for storage
-func (c *CIPSClient) GetCIPSSeriesPoints() ([]store.SeriesPoint, error) {
-	stats, err := c.FetchCIPSStats()
+func (c *CIPSClient) GetCIPSSeriesPoints(ctx context.Context) ([]store.SeriesPoint, error) {
+	stats, err := c.FetchCIPSStats(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,221 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// dataTable is one sheet of a downloaded spreadsheet or a CSV file, as rows of cell text
+type dataTable struct {
+	Name string
+	Rows [][]string
+}
+
+// readDataFile parses an XLSX workbook (one table per sheet) or a CSV file.
+// The format is detected from content, so misnamed downloads still parse.
+func readDataFile(name string, data []byte) ([]dataTable, error) {
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return readXLSX(data)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	if trimmed[0] == '<' {
+		return nil, fmt.Errorf("%s is HTML, not a data file", name)
+	}
+
+	rows, err := readCSV(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s as CSV: %w", name, err)
+	}
+	return []dataTable{{Name: path.Base(name), Rows: rows}}, nil
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+	return r.ReadAll()
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads every worksheet of an XLSX workbook using only archive/zip and encoding/xml
+func readXLSX(data []byte) ([]dataTable, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+
+	var rels xlsxRelationships
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[rel.ID] = target
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var tables []dataTable
+	for _, s := range workbook.Sheets {
+		var sheet xlsxSheet
+		if err := decodeZipXML(files, targets[s.RID], &sheet); err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", s.Name, err)
+		}
+
+		table := dataTable{Name: s.Name}
+		for _, row := range sheet.Rows {
+			var cells []string
+			for i, c := range row.Cells {
+				// Refs without column letters fall back to the cell's position
+				col := i
+				if ref, ok := xlsxColumn(c.Ref); ok {
+					col = ref
+				}
+				if col > maxXLSXColumn {
+					return nil, fmt.Errorf("sheet %q: cell %q is beyond column XFD", s.Name, c.Ref)
+				}
+				for len(cells) <= col {
+					cells = append(cells, "")
+				}
+
+				switch c.Type {
+				case "s":
+					var idx int
+					if _, err := fmt.Sscanf(c.Value, "%d", &idx); err == nil && idx >= 0 && idx < len(shared.Items) {
+						cells[col] = shared.Items[idx].String()
+					}
+				case "inlineStr":
+					cells[col] = c.Inline.String()
+				default:
+					cells[col] = c.Value
+				}
+				cells[col] = strings.TrimSpace(cells[col])
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("XLSX has no worksheets")
+	}
+	return tables, nil
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("XLSX is missing %s", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// maxXLSXColumn is the zero-based index of XFD, the last column a worksheet can have
+const maxXLSXColumn = 16383
+
+// xlsxColumn converts a cell reference such as "AB12" to a zero-based column index.
+// It reports false if ref has no column letters. Columns past XFD are returned as
+// maxXLSXColumn+1 rather than computed, so long refs can't overflow.
+func xlsxColumn(ref string) (int, bool) {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		if col-1 > maxXLSXColumn {
+			return maxXLSXColumn + 1, true
+		}
+	}
+	return col - 1, col > 0
+}
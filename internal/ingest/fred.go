@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -30,17 +31,50 @@ type fredResponse struct {
 	} `json:"observations"`
 }
 
+type fredSeriesResponse struct {
+	Seriess []struct {
+		LastUpdated string `json:"last_updated"`
+	} `json:"seriess"`
+}
+
 func NewFREDClient(apiKey string) *FREDClient {
 	return &FREDClient{
-		apiKey:  apiKey,
-		baseURL: "https://api.stlouisfed.org/fred",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:     apiKey,
+		baseURL:    "https://api.stlouisfed.org/fred",
+		httpClient: newHTTPClient(30 * time.Second),
+	}
+}
+
+// FetchSeries fetches the 100 most recent observations, newest first, stamped
+// with when FRED last updated the series
+func (c *FREDClient) FetchSeries(ctx context.Context, seriesID string) FetchResult {
+	q := url.Values{}
+	q.Set("sort_order", "desc")
+	q.Set("limit", "100")
+	result := c.fetchObservations(ctx, seriesID, q)
+	if result.Err != nil || len(result.Points) == 0 {
+		return result
+	}
+
+	// Freshness is informational, so a failed lookup doesn't fail the fetch
+	if updated, err := c.fetchLastUpdated(ctx, seriesID); err == nil {
+		for i := range result.Points {
+			result.Points[i].SourceUpdatedAt = &updated
+		}
 	}
+	return result
+}
+
+// FetchSeriesRange fetches all observations between start and end (YYYY-MM-DD, inclusive), oldest first
+func (c *FREDClient) FetchSeriesRange(ctx context.Context, seriesID, start, end string) FetchResult {
+	q := url.Values{}
+	q.Set("sort_order", "asc")
+	q.Set("observation_start", start)
+	q.Set("observation_end", end)
+	return c.fetchObservations(ctx, seriesID, q)
 }
 
-func (c *FREDClient) FetchSeries(seriesID string) FetchResult {
+func (c *FREDClient) fetchObservations(ctx context.Context, seriesID string, params url.Values) FetchResult {
 	result := FetchResult{Name: seriesID}
 
 	u, err := url.Parse(fmt.Sprintf("%s/series/observations", c.baseURL))
@@ -53,11 +87,18 @@ func (c *FREDClient) FetchSeries(seriesID string) FetchResult {
 	q.Set("series_id", seriesID)
 	q.Set("api_key", c.apiKey)
 	q.Set("file_type", "json")
-	q.Set("sort_order", "desc")
-	q.Set("limit", "100")
+	for k, v := range params {
+		q[k] = v
+	}
 	u.RawQuery = q.Encode()
 
-	resp, err := c.httpClient.Get(u.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		result.Err = fmt.Errorf("failed to fetch FRED data: %w", err)
 		return result
@@ -92,3 +133,41 @@ func (c *FREDClient) FetchSeries(seriesID string) FetchResult {
 
 	return result
 }
+
+// fetchLastUpdated returns when FRED last revised or extended a series
+func (c *FREDClient) fetchLastUpdated(ctx context.Context, seriesID string) (time.Time, error) {
+	q := url.Values{}
+	q.Set("series_id", seriesID)
+	q.Set("api_key", c.apiKey)
+	q.Set("file_type", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/series?"+q.Encode(), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch FRED series info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("FRED API returned status %d", resp.StatusCode)
+	}
+
+	var info fredSeriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode FRED series info: %w", err)
+	}
+	if len(info.Seriess) == 0 {
+		return time.Time{}, fmt.Errorf("FRED series %s not found", seriesID)
+	}
+
+	// e.g. "2024-01-16 15:17:02-06"
+	updated, err := time.Parse("2006-01-02 15:04:05-07", info.Seriess[0].LastUpdated)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid FRED last_updated %q: %w", info.Seriess[0].LastUpdated, err)
+	}
+	return updated.UTC(), nil
+}
@@ -1,9 +1,14 @@
 package ingest
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"reserve-watch/internal/store"
@@ -11,168 +16,247 @@ import (
 
 type IMFClient struct {
 	httpClient *http.Client
+	baseURL    string
 }
 
 type imfResponse struct {
 	CompactData struct {
 		DataSet struct {
-			Series []struct {
-				Attributes struct {
-					Indicator string `json:"INDICATOR"`
-					Currency  string `json:"CURRENCY"`
-				} `json:"@attributes"`
-				Obs []struct {
-					Attributes struct {
-						TimePeriod string `json:"TIME_PERIOD"`
-						ObsValue   string `json:"OBS_VALUE"`
-					} `json:"@attributes"`
-				} `json:"Obs"`
-			} `json:"Series"`
+			Series imfList[imfSeries] `json:"Series"`
 		} `json:"DataSet"`
 	} `json:"CompactData"`
 }
 
+type imfSeries struct {
+	Indicator string          `json:"@INDICATOR"`
+	UnitMult  string          `json:"@UNIT_MULT"`
+	Obs       imfList[imfObs] `json:"Obs"`
+}
+
+type imfObs struct {
+	TimePeriod string `json:"@TIME_PERIOD"`
+	ObsValue   string `json:"@OBS_VALUE"`
+}
+
+// imfList decodes IMF SDMX-JSON fields that are an object when there is one
+// element and an array otherwise
+type imfList[T any] []T
+
+func (l *imfList[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var one T
+		if err := json.Unmarshal(data, &one); err != nil {
+			return err
+		}
+		*l = []T{one}
+		return nil
+	}
+	var many []T
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*l = many
+	return nil
+}
+
+// coferCurrency maps a COFER allocated-claims indicator to the share series we store
+type coferCurrency struct {
+	Indicator string
+	SeriesID  string
+	Currency  string
+}
+
+// COFER world (W00) indicators, in USD. Currency claims are the allocated reserves
+// held in each currency; shares are computed against total allocated reserves so
+// every currency, including "other", is on the same footing.
+var coferCurrencies = []coferCurrency{
+	{"RAXGFXARUSD_USD", "COFER_USD", "USD"},
+	{"RAXGFXAREUR_USD", "COFER_EUR", "EUR"},
+	{"RAXGFXARJPY_USD", "COFER_JPY", "JPY"},
+	{"RAXGFXARGBP_USD", "COFER_GBP", "GBP"},
+	{"RAXGFXARCNY_USD", "COFER_CNY", "CNY"},
+	{"RAXGFXARCAD_USD", "COFER_CAD", "CAD"},
+	{"RAXGFXARAUD_USD", "COFER_AUD", "AUD"},
+	{"RAXGFXARCHF_USD", "COFER_CHF", "CHF"},
+	{"RAXGFXAROC_USD", "COFER_OTHER", "OTHER"},
+}
+
+const (
+	coferTotalIndicator       = "RAXGFX_USD"   // total foreign exchange reserves
+	coferAllocatedIndicator   = "RAXGFXAR_USD" // allocated reserves
+	coferUnallocatedIndicator = "RAXGFXUR_USD" // unallocated reserves
+
+	// coferHistoryStart is the first quarter of world COFER data
+	coferHistoryStart = "1995-01-01"
+)
+
 func NewIMFClient() *IMFClient {
 	return &IMFClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient: newHTTPClient(30 * time.Second),
+		baseURL:    "https://dataservices.imf.org/REST/SDMX_JSON.svc",
 	}
 }
 
-// FetchCOFER fetches IMF Currency Composition of Official Foreign Exchange Reserves
-// Returns CNY (RMB) reserve share percentage
-func (c *IMFClient) FetchCOFER() (store.SeriesPoint, error) {
-	// IMF COFER API endpoint for CNY allocated reserves
-	// Changed to HTTPS as HTTP is refused
-	url := "https://dataservices.imf.org/REST/SDMX_JSON.svc/CompactData/COFER/Q.CN.?startPeriod=2016&endPeriod=2025"
+// FetchCOFER fetches the full quarterly history of world COFER: currency shares of
+// allocated reserves plus total, allocated and unallocated reserves
+func (c *IMFClient) FetchCOFER(ctx context.Context) ([]store.SeriesPoint, error) {
+	return c.FetchCOFERRange(ctx, coferHistoryStart, time.Now().UTC().Format("2006-01-02"))
+}
+
+// FetchCOFERRange fetches every COFER point between two dates (YYYY-MM-DD).
+// IMF only filters by period, so the request is widened to whole years and trimmed after.
+func (c *IMFClient) FetchCOFERRange(ctx context.Context, start, end string) ([]store.SeriesPoint, error) {
+	imfResp, err := c.fetchCOFERData(ctx, start[:4], end[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	points, err := coferPoints(imfResp)
+	if err != nil {
+		return nil, err
+	}
+
+	var inRange []store.SeriesPoint
+	for _, p := range points {
+		if p.Date >= start && p.Date <= end {
+			inRange = append(inRange, p)
+		}
+	}
+	return inRange, nil
+}
+
+func (c *IMFClient) fetchCOFERData(ctx context.Context, startPeriod, endPeriod string) (*imfResponse, error) {
+	url := fmt.Sprintf("%s/CompactData/COFER/Q.W00.?startPeriod=%s&endPeriod=%s", c.baseURL, startPeriod, endPeriod)
 
-	resp, err := c.httpClient.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to fetch IMF COFER data: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch IMF COFER data: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return store.SeriesPoint{}, fmt.Errorf("IMF API returned status %d - will retry on next fetch", resp.StatusCode)
+		return nil, fmt.Errorf("IMF API returned status %d", resp.StatusCode)
 	}
 
 	var imfResp imfResponse
 	if err := json.NewDecoder(resp.Body).Decode(&imfResp); err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to decode IMF response: %w", err)
+		return nil, fmt.Errorf("failed to decode IMF response: %w", err)
 	}
 
-	// Extract latest CNY reserve percentage
-	if len(imfResp.CompactData.DataSet.Series) == 0 {
-		return store.SeriesPoint{}, fmt.Errorf("no COFER data returned")
-	}
+	return &imfResp, nil
+}
 
-	var latestObs struct {
-		period string
-		value  string
-	}
+// coferPoints turns COFER amounts into share and reserve-total series.
+// Allocated reserves fall back to the sum of currency claims and unallocated
+// reserves to total minus allocated when IMF doesn't report them directly.
+func coferPoints(resp *imfResponse) ([]store.SeriesPoint, error) {
+	byPeriod := make(map[string]map[string]float64)
 
-	for _, series := range imfResp.CompactData.DataSet.Series {
-		if len(series.Obs) > 0 {
-			// Get most recent observation
-			obs := series.Obs[len(series.Obs)-1]
-			if obs.Attributes.TimePeriod > latestObs.period {
-				latestObs.period = obs.Attributes.TimePeriod
-				latestObs.value = obs.Attributes.ObsValue
+	for _, series := range resp.CompactData.DataSet.Series {
+		mult := 0
+		if series.UnitMult != "" {
+			mult, _ = strconv.Atoi(series.UnitMult)
+		}
+
+		for _, obs := range series.Obs {
+			date, ok := parseQuarterLabel(obs.TimePeriod)
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseFloat(obs.ObsValue, 64)
+			if err != nil {
+				continue
 			}
+			if byPeriod[date] == nil {
+				byPeriod[date] = make(map[string]float64)
+			}
+			// Store amounts in billions of USD
+			byPeriod[date][series.Indicator] = value * math.Pow(10, float64(mult)) / 1e9
 		}
 	}
 
-	if latestObs.period == "" {
-		return store.SeriesPoint{}, fmt.Errorf("no observations found in COFER data")
+	if len(byPeriod) == 0 {
+		return nil, fmt.Errorf("no observations found in COFER data")
 	}
 
-	var value float64
-	fmt.Sscanf(latestObs.value, "%f", &value)
-
-	return store.SeriesPoint{
-		Date:  latestObs.period,
-		Value: value,
-		Meta: map[string]string{
-			"series_id": "COFER_CNY",
-			"source":    "IMF",
-			"currency":  "CNY",
-			"unit":      "percent_of_reserves",
-			"frequency": "quarterly",
-		},
-	}, nil
-}
-
-// FetchAllCOFERCurrencies fetches reserve shares for all major currencies
-func (c *IMFClient) FetchAllCOFERCurrencies() ([]store.SeriesPoint, error) {
-	// Major currencies: USD, EUR, CNY, JPY, GBP
-	currencies := []string{"US", "XM", "CN", "JP", "GB"}
-	currencyNames := map[string]string{
-		"US": "USD",
-		"XM": "EUR",
-		"CN": "CNY",
-		"JP": "JPY",
-		"GB": "GBP",
+	dates := make([]string, 0, len(byPeriod))
+	for date := range byPeriod {
+		dates = append(dates, date)
 	}
+	sort.Strings(dates)
 
 	var points []store.SeriesPoint
+	for _, date := range dates {
+		values := byPeriod[date]
 
-	for _, curr := range currencies {
-		url := fmt.Sprintf("https://dataservices.imf.org/REST/SDMX_JSON.svc/CompactData/COFER/Q.%s.?startPeriod=2023&endPeriod=2025", curr)
-
-		resp, err := c.httpClient.Get(url)
-		if err != nil {
-			continue // Skip on error, don't fail entire fetch
+		allocated, haveAllocated := values[coferAllocatedIndicator]
+		if !haveAllocated {
+			for _, cur := range coferCurrencies {
+				if v, ok := values[cur.Indicator]; ok {
+					allocated += v
+					haveAllocated = true
+				}
+			}
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			continue
+		total, haveTotal := values[coferTotalIndicator]
+		unallocated, haveUnallocated := values[coferUnallocatedIndicator]
+		if !haveUnallocated && haveTotal && haveAllocated {
+			unallocated, haveUnallocated = total-allocated, true
 		}
 
-		var imfResp imfResponse
-		if err := json.NewDecoder(resp.Body).Decode(&imfResp); err != nil {
-			continue
+		if haveTotal {
+			points = append(points, coferAmountPoint("COFER_TOTAL", date, total))
 		}
-
-		if len(imfResp.CompactData.DataSet.Series) == 0 {
-			continue
+		if haveAllocated {
+			points = append(points, coferAmountPoint("COFER_ALLOCATED", date, allocated))
 		}
-
-		var latestObs struct {
-			period string
-			value  string
+		if haveUnallocated {
+			points = append(points, coferAmountPoint("COFER_UNALLOCATED", date, unallocated))
 		}
 
-		for _, series := range imfResp.CompactData.DataSet.Series {
-			if len(series.Obs) > 0 {
-				obs := series.Obs[len(series.Obs)-1]
-				if obs.Attributes.TimePeriod > latestObs.period {
-					latestObs.period = obs.Attributes.TimePeriod
-					latestObs.value = obs.Attributes.ObsValue
-				}
-			}
-		}
-
-		if latestObs.period == "" {
+		if !haveAllocated || allocated <= 0 {
 			continue
 		}
-
-		var value float64
-		fmt.Sscanf(latestObs.value, "%f", &value)
-
-		points = append(points, store.SeriesPoint{
-			Date:  latestObs.period,
-			Value: value,
-			Meta: map[string]string{
-				"series_id": fmt.Sprintf("COFER_%s", currencyNames[curr]),
-				"source":    "IMF",
-				"currency":  currencyNames[curr],
-				"unit":      "percent_of_reserves",
-				"frequency": "quarterly",
-			},
-		})
+		for _, cur := range coferCurrencies {
+			claim, ok := values[cur.Indicator]
+			if !ok {
+				continue
+			}
+			share := math.Round(claim/allocated*100*100) / 100
+			points = append(points, store.SeriesPoint{
+				Date:  date,
+				Value: share,
+				Meta: map[string]string{
+					"series_id":     cur.SeriesID,
+					"source":        "IMF",
+					"currency":      cur.Currency,
+					"unit":          "percent_of_allocated_reserves",
+					"frequency":     "quarterly",
+					"claims_usd_bn": strconv.FormatFloat(claim, 'f', 2, 64),
+				},
+			})
+		}
 	}
 
 	return points, nil
 }
+
+func coferAmountPoint(seriesID, date string, value float64) store.SeriesPoint {
+	return store.SeriesPoint{
+		Date:  date,
+		Value: math.Round(value*100) / 100,
+		Meta: map[string]string{
+			"series_id": seriesID,
+			"source":    "IMF",
+			"unit":      "usd_billions",
+			"frequency": "quarterly",
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package ingest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFetchCOFER(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/imf_cofer_w00.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	client := NewIMFClient()
+	client.httpClient = server.Client()
+	client.baseURL = server.URL
+
+	points, err := client.FetchCOFERRange(context.Background(), "2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatalf("Failed to fetch COFER: %v", err)
+	}
+
+	if !strings.Contains(requested, "/CompactData/COFER/Q.W00.?startPeriod=2024&endPeriod=2024") {
+		t.Errorf("Unexpected request %s", requested)
+	}
+
+	got := make(map[string]float64)
+	for _, p := range points {
+		got[p.Meta["series_id"]+"@"+p.Date] = p.Value
+	}
+
+	expected := map[string]float64{
+		// Q2 reports allocated and unallocated reserves directly
+		"COFER_USD@2024-06-30":         59.06,
+		"COFER_CNY@2024-06-30":         2.15,
+		"COFER_CHF@2024-06-30":         0.18,
+		"COFER_ALLOCATED@2024-06-30":   11000,
+		"COFER_UNALLOCATED@2024-06-30": 1335,
+		"COFER_TOTAL@2024-06-30":       12335,
+		// Q1 derives allocated from the currency claims and unallocated from the total
+		"COFER_USD@2024-03-31":         58.80,
+		"COFER_ALLOCATED@2024-03-31":   10883.6,
+		"COFER_UNALLOCATED@2024-03-31": 1116.4,
+	}
+	for key, want := range expected {
+		if v, ok := got[key]; !ok || v != want {
+			t.Errorf("Expected %s = %.2f, got %.2f (present: %v)", key, want, v, ok)
+		}
+	}
+
+	if _, ok := got["COFER_CHF@2024-03-31"]; ok {
+		t.Error("Expected no CHF point for a quarter without a CHF observation")
+	}
+
+	// Shares of allocated reserves add up to 100
+	var sum float64
+	for _, cur := range coferCurrencies {
+		sum += got[cur.SeriesID+"@2024-06-30"]
+	}
+	if sum < 99.9 || sum > 100.1 {
+		t.Errorf("Expected currency shares to sum to 100, got %.2f", sum)
+	}
+
+	// Every point belongs to a declared series
+	registry := NewRegistry()
+	registry.MustRegister(NewSource("imf_cofer", "", 0, coferSeries, nil))
+	_, src, _ := registry.Lookup("COFER_USD")
+	if _, err := GroupBySeries(src, points); err != nil {
+		t.Errorf("Expected points to match declared COFER series: %v", err)
+	}
+}
@@ -0,0 +1,823 @@
+package ingest
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Minimal PDF text extraction.
+//
+// This is just enough of a PDF reader to get the text out of report-style
+// documents such as the SWIFT RMB Tracker: it finds indirect objects (including
+// those packed in object streams), inflates FlateDecode streams, walks the page
+// tree and interprets text-showing operators, mapping glyph codes through each
+// font's ToUnicode CMap. Layout is approximated with spaces and newlines.
+
+type pdfName string
+type pdfKeyword string
+type pdfRef int
+type pdfDict map[pdfName]interface{}
+type pdfArray []interface{}
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+type pdfDocument struct {
+	objects map[int]interface{}
+	cmaps   map[int]*pdfCMap
+}
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// ExtractPDFText returns the text of every page in document order
+func ExtractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("%PDF-")) {
+		return "", errors.New("not a PDF document")
+	}
+
+	doc := &pdfDocument{
+		objects: make(map[int]interface{}),
+		cmaps:   make(map[int]*pdfCMap),
+	}
+	doc.parseObjects(data)
+	doc.expandObjectStreams()
+
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", errors.New("no pages found in PDF")
+	}
+
+	var out strings.Builder
+	for _, page := range pages {
+		doc.showText(&out, doc.pageContent(page.dict), page.resources, 0)
+		out.WriteString("\n")
+	}
+
+	return normalizePDFText(out.String()), nil
+}
+
+func (d *pdfDocument) parseObjects(data []byte) {
+	cursor := 0
+	for _, m := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		if m[0] < cursor {
+			continue // inside a stream we already consumed
+		}
+
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+
+		l := &pdfLexer{data: data, pos: m[1]}
+		value := l.parseValue()
+		cursor = l.pos
+
+		dict, ok := value.(pdfDict)
+		if ok && l.skipKeyword("stream") {
+			start := l.pos
+			if start < len(data) && data[start] == '\r' {
+				start++
+			}
+			if start < len(data) && data[start] == '\n' {
+				start++
+			}
+
+			end := -1
+			// Ignore a /Length that points outside the file; endstream is searched for instead
+			if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(data)-start) {
+				candidate := start + int(length)
+				if candidate <= len(data) && bytes.HasPrefix(bytes.TrimLeft(data[candidate:], " \t\r\n"), []byte("endstream")) {
+					end = candidate
+				}
+			}
+			if end < 0 {
+				idx := bytes.Index(data[start:], []byte("endstream"))
+				if idx < 0 {
+					continue
+				}
+				end = start + idx
+				for end > start && (data[end-1] == '\n' || data[end-1] == '\r') {
+					end--
+				}
+			}
+
+			value = &pdfStream{dict: dict, raw: data[start:end]}
+			cursor = end
+		}
+
+		d.objects[num] = value
+	}
+}
+
+// expandObjectStreams adds objects stored inside /Type /ObjStm streams
+func (d *pdfDocument) expandObjectStreams() {
+	var streams []*pdfStream
+	for _, obj := range d.objects {
+		if s, ok := obj.(*pdfStream); ok && s.dict["Type"] == pdfName("ObjStm") {
+			streams = append(streams, s)
+		}
+	}
+
+	for _, s := range streams {
+		data, err := d.decodeStream(s)
+		if err != nil {
+			continue
+		}
+
+		n, _ := d.resolve(s.dict["N"]).(float64)
+		first, _ := d.resolve(s.dict["First"]).(float64)
+		if first < 0 || int(first) > len(data) {
+			continue
+		}
+
+		header := &pdfLexer{data: data[:int(first)]}
+		for i := 0; i < int(n); i++ {
+			num, ok1 := header.next().(float64)
+			offset, ok2 := header.next().(float64)
+			if !ok1 || !ok2 {
+				break
+			}
+			if _, exists := d.objects[int(num)]; exists {
+				continue
+			}
+			pos := int(first) + int(offset)
+			if offset < 0 || pos >= len(data) {
+				continue
+			}
+			l := &pdfLexer{data: data, pos: pos}
+			d.objects[int(num)] = l.parseValue()
+		}
+	}
+}
+
+func (d *pdfDocument) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[int(ref)]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v interface{}) pdfDict {
+	switch o := d.resolve(v).(type) {
+	case pdfDict:
+		return o
+	case *pdfStream:
+		return o.dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) decodeStream(s *pdfStream) ([]byte, error) {
+	var filters []interface{}
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{f}
+	case pdfArray:
+		filters = f
+	}
+
+	data := s.raw
+	for _, f := range filters {
+		switch d.resolve(f) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("inflate stream: %w", err)
+			}
+			out, err := io.ReadAll(r)
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("inflate stream: %w", err)
+			}
+			data = out
+		default:
+			return nil, fmt.Errorf("unsupported stream filter %v", f)
+		}
+	}
+	return data, nil
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree from the catalog, falling back to object order
+func (d *pdfDocument) pages() []pdfPage {
+	var catalogs []int
+	for num, obj := range d.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			catalogs = append(catalogs, num)
+		}
+	}
+	sort.Ints(catalogs)
+
+	var pages []pdfPage
+	if len(catalogs) > 0 {
+		catalog := d.objects[catalogs[len(catalogs)-1]].(pdfDict)
+		d.walkPages(catalog["Pages"], nil, &pages, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	var nums []int
+	for num, obj := range d.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		dict := d.objects[num].(pdfDict)
+		pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+	}
+	return pages
+}
+
+func (d *pdfDocument) walkPages(node interface{}, inherited pdfDict, pages *[]pdfPage, depth int) {
+	dict := d.dict(node)
+	if dict == nil || depth > 32 {
+		return
+	}
+
+	resources := inherited
+	if r := d.dict(dict["Resources"]); r != nil {
+		resources = r
+	}
+
+	if dict["Type"] == pdfName("Page") {
+		*pages = append(*pages, pdfPage{dict: dict, resources: resources})
+		return
+	}
+
+	kids, _ := d.resolve(dict["Kids"]).(pdfArray)
+	for _, kid := range kids {
+		d.walkPages(kid, resources, pages, depth+1)
+	}
+}
+
+func (d *pdfDocument) pageContent(page pdfDict) []byte {
+	var refs []interface{}
+	switch c := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		refs = []interface{}{c}
+	case pdfArray:
+		refs = c
+	}
+
+	// Content arrays are a single stream split at arbitrary token boundaries
+	var joined []byte
+	for _, ref := range refs {
+		s, ok := d.resolve(ref).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		joined = append(joined, data...)
+		joined = append(joined, '\n')
+	}
+	return joined
+}
+
+// showText interprets a content stream and writes the text it shows
+func (d *pdfDocument) showText(out *strings.Builder, content []byte, resources pdfDict, depth int) {
+	if depth > 8 {
+		return
+	}
+
+	fonts := d.dict(resources["Font"])
+	var font *pdfCMap
+	var operands []interface{}
+	lastY, haveY := 0.0, false
+
+	write := func(s pdfString) {
+		if font != nil {
+			out.WriteString(font.decode(s))
+		} else {
+			out.WriteString(latin1(s))
+		}
+	}
+
+	l := &pdfLexer{data: content}
+	for {
+		tok := l.parseValue()
+		if tok == nil {
+			break
+		}
+
+		op, ok := tok.(pdfKeyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch op {
+		case "BT":
+			haveY = false
+		case "ET":
+			out.WriteString("\n")
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[len(operands)-2].(pdfName); ok {
+					font = d.fontCMap(fonts[name])
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if ty, _ := operands[len(operands)-1].(float64); ty != 0 {
+					out.WriteString("\n")
+				} else {
+					out.WriteString(" ")
+				}
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := operands[len(operands)-1].(float64)
+				if haveY && y == lastY {
+					out.WriteString(" ")
+				} else {
+					out.WriteString("\n")
+				}
+				lastY, haveY = y, true
+			}
+		case "T*":
+			out.WriteString("\n")
+		case "Tj":
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					write(s)
+				}
+			}
+		case "'", "\"":
+			out.WriteString("\n")
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					write(s)
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				arr, _ := operands[len(operands)-1].(pdfArray)
+				for _, item := range arr {
+					switch v := item.(type) {
+					case pdfString:
+						write(v)
+					case float64:
+						// Large negative adjustments are used in place of spaces
+						if v < -200 {
+							out.WriteString(" ")
+						}
+					}
+				}
+			}
+		case "Do":
+			if len(operands) >= 1 {
+				if name, ok := operands[len(operands)-1].(pdfName); ok {
+					xobjects := d.dict(resources["XObject"])
+					if form, ok := d.resolve(xobjects[name]).(*pdfStream); ok && form.dict["Subtype"] == pdfName("Form") {
+						if data, err := d.decodeStream(form); err == nil {
+							formResources := resources
+							if r := d.dict(form.dict["Resources"]); r != nil {
+								formResources = r
+							}
+							d.showText(out, data, formResources, depth+1)
+						}
+					}
+				}
+			}
+		case "ID":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func (d *pdfDocument) fontCMap(ref interface{}) *pdfCMap {
+	font := d.dict(ref)
+	if font == nil {
+		return nil
+	}
+
+	toUnicode, ok := font["ToUnicode"].(pdfRef)
+	if !ok {
+		if font["Subtype"] == pdfName("Type0") {
+			return &pdfCMap{codeLen: 2} // CIDs with no mapping are dropped
+		}
+		return nil
+	}
+
+	if cmap, ok := d.cmaps[int(toUnicode)]; ok {
+		return cmap
+	}
+
+	var cmap *pdfCMap
+	if s, ok := d.resolve(toUnicode).(*pdfStream); ok {
+		if data, err := d.decodeStream(s); err == nil {
+			cmap = parseCMap(data)
+		}
+	}
+	d.cmaps[int(toUnicode)] = cmap
+	return cmap
+}
+
+// pdfCMap maps glyph codes to Unicode text
+type pdfCMap struct {
+	codeLen int
+	chars   map[uint32]string
+	ranges  []pdfCMapRange
+}
+
+type pdfCMapRange struct {
+	lo, hi uint32
+	base   []uint16 // destination for lo, incremented across the range
+	dsts   []string // explicit destinations, when given as an array
+}
+
+func parseCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{codeLen: 1, chars: make(map[uint32]string)}
+	l := &pdfLexer{data: data}
+
+	for {
+		tok := l.parseValue()
+		if tok == nil {
+			break
+		}
+
+		switch tok {
+		case pdfKeyword("begincodespacerange"):
+			if lo, ok := l.parseValue().(pdfString); ok && len(lo) > 0 {
+				cmap.codeLen = len(lo)
+			}
+		case pdfKeyword("beginbfchar"):
+			for {
+				src, ok := l.parseValue().(pdfString)
+				if !ok {
+					break
+				}
+				dst, _ := l.parseValue().(pdfString)
+				cmap.chars[codeValue(src)] = utf16String(dst)
+			}
+		case pdfKeyword("beginbfrange"):
+			for {
+				lo, ok := l.parseValue().(pdfString)
+				if !ok {
+					break
+				}
+				hi, _ := l.parseValue().(pdfString)
+				r := pdfCMapRange{lo: codeValue(lo), hi: codeValue(hi)}
+				switch dst := l.parseValue().(type) {
+				case pdfString:
+					r.base = utf16Units(dst)
+				case pdfArray:
+					for _, item := range dst {
+						s, _ := item.(pdfString)
+						r.dsts = append(r.dsts, utf16String(s))
+					}
+				}
+				cmap.ranges = append(cmap.ranges, r)
+			}
+		}
+	}
+
+	return cmap
+}
+
+func (c *pdfCMap) decode(s pdfString) string {
+	var out strings.Builder
+	n := c.codeLen
+	if n <= 0 {
+		n = 1
+	}
+
+	for i := 0; i+n <= len(s); i += n {
+		code := codeValue(s[i : i+n])
+		if text, ok := c.chars[code]; ok {
+			out.WriteString(text)
+			continue
+		}
+
+		mapped := false
+		for _, r := range c.ranges {
+			if code < r.lo || code > r.hi {
+				continue
+			}
+			offset := code - r.lo
+			if r.dsts != nil {
+				if int(offset) < len(r.dsts) {
+					out.WriteString(r.dsts[offset])
+				}
+			} else if len(r.base) > 0 {
+				units := append([]uint16(nil), r.base...)
+				units[len(units)-1] += uint16(offset)
+				out.WriteString(string(utf16.Decode(units)))
+			}
+			mapped = true
+			break
+		}
+
+		if !mapped && n == 1 {
+			out.WriteString(latin1(s[i : i+1]))
+		}
+	}
+
+	return out.String()
+}
+
+func codeValue(b []byte) uint32 {
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+	return v
+}
+
+func utf16Units(b []byte) []uint16 {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return units
+}
+
+func utf16String(b []byte) string {
+	return string(utf16.Decode(utf16Units(b)))
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+var (
+	pdfSpaceRun   = regexp.MustCompile(`[ \t]+`)
+	pdfNewlineRun = regexp.MustCompile(`\s*\n\s*`)
+)
+
+func normalizePDFText(s string) string {
+	s = pdfSpaceRun.ReplaceAllString(s, " ")
+	s = pdfNewlineRun.ReplaceAllString(s, "\n")
+	return strings.TrimSpace(s)
+}
+
+// pdfString is a decoded literal or hex string
+type pdfString []byte
+
+// pdfLexer tokenizes PDF object syntax and content streams
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFSpace(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// skipKeyword consumes kw if it is the next token
+func (l *pdfLexer) skipKeyword(kw string) bool {
+	l.skipSpace()
+	if l.pos < len(l.data) && bytes.HasPrefix(l.data[l.pos:], []byte(kw)) {
+		l.pos += len(kw)
+		return true
+	}
+	return false
+}
+
+// next returns the next token: float64, pdfName, pdfString, pdfKeyword or nil at EOF
+func (l *pdfLexer) next() interface{} {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(unescapeName(l.data[start:l.pos]))
+	case c == '(':
+		return l.literalString()
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<")
+		}
+		return l.hexString()
+	case c == '>':
+		l.pos++
+		if l.pos < len(l.data) && l.data[l.pos] == '>' {
+			l.pos++
+			return pdfKeyword(">>")
+		}
+		return pdfKeyword(">")
+	case c == '[' || c == ']' || c == '{' || c == '}' || c == ')':
+		l.pos++
+		return pdfKeyword(string(c))
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if f, err := strconv.ParseFloat(word, 64); err == nil && strings.IndexAny(word[:1], "+-.0123456789") == 0 {
+		return f
+	}
+	return pdfKeyword(word)
+}
+
+// parseValue reads a complete value, assembling arrays, dictionaries and references
+func (l *pdfLexer) parseValue() interface{} {
+	tok := l.next()
+	switch tok {
+	case pdfKeyword("["):
+		return pdfArray(l.parseItems("]"))
+	case pdfKeyword("<<"):
+		items := l.parseItems(">>")
+		dict := make(pdfDict)
+		for i := 0; i+1 < len(items); i += 2 {
+			if key, ok := items[i].(pdfName); ok {
+				dict[key] = items[i+1]
+			}
+		}
+		return dict
+	}
+	return tok
+}
+
+func (l *pdfLexer) parseItems(end string) []interface{} {
+	var items []interface{}
+	for {
+		v := l.parseValue()
+		if v == nil || v == pdfKeyword(end) {
+			break
+		}
+		if v == pdfKeyword("R") && len(items) >= 2 {
+			num, ok1 := items[len(items)-2].(float64)
+			_, ok2 := items[len(items)-1].(float64)
+			if ok1 && ok2 {
+				items = append(items[:len(items)-2], pdfRef(int(num)))
+				continue
+			}
+		}
+		items = append(items, v)
+	}
+	return items
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if isHexDigit(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++ // >
+	}
+
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(v)
+	}
+	return out
+}
+
+// skipInlineImage skips binary inline image data following an ID operator
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos + 1; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func unescapeName(b []byte) string {
+	if !bytes.ContainsRune(b, '#') {
+		return string(b)
+	}
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) && isHexDigit(b[i+1]) && isHexDigit(b[i+2]) {
+			v, _ := strconv.ParseUint(string(b[i+1:i+3]), 16, 8)
+			out = append(out, byte(v))
+			i += 2
+			continue
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
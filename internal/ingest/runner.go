@@ -0,0 +1,177 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+
+	"github.com/robfig/cron/v3"
+)
+
+// DefaultConcurrency is the number of sources fetched in parallel
+const DefaultConcurrency = 3
+
+// DefaultRetryDelays are the waits before re-running a failed scheduled source,
+// so an outage that outlasts the transport's own retries heals within minutes
+var DefaultRetryDelays = []time.Duration{2 * time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// Runner fetches registered sources and saves their points.
+// Sources run concurrently up to the configured limit, each under its own timeout,
+// and a source is never run twice at the same time. Changed series are announced
+// on the store's event bus by SavePoints.
+type Runner struct {
+	registry *Registry
+	store    *store.Store
+	sem      chan struct{}
+	running  sync.Map
+
+	// RetryDelays are applied in turn after a scheduled run fails
+	RetryDelays []time.Duration
+}
+
+func NewRunner(registry *Registry, db *store.Store, concurrency int) *Runner {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	return &Runner{
+		registry: registry,
+		store:    db,
+		sem:      make(chan struct{}, concurrency),
+
+		RetryDelays: DefaultRetryDelays,
+	}
+}
+
+// Schedule adds one cron entry per source using the source's own schedule
+func (r *Runner) Schedule(c *cron.Cron) error {
+	for _, src := range r.registry.Sources() {
+		src := src
+		if _, err := c.AddFunc(src.Schedule(), func() {
+			r.runScheduled(src, 0)
+		}); err != nil {
+			return fmt.Errorf("invalid schedule %q for source %s: %w", src.Schedule(), src.Name(), err)
+		}
+		util.InfoLogger.Printf("Scheduled %s (%s, timeout %v)", src.Name(), src.Schedule(), src.Timeout())
+	}
+	return nil
+}
+
+// runScheduled runs a source and, on failure, schedules another attempt after
+// the next retry delay until the delays are exhausted
+func (r *Runner) runScheduled(src Source, attempt int) {
+	err := r.RunSource(context.Background(), src)
+	if err == nil {
+		return
+	}
+
+	if attempt >= len(r.RetryDelays) {
+		util.ErrorLogger.Printf("%s fetch failed: %v", src.Name(), err)
+		return
+	}
+
+	delay := r.RetryDelays[attempt]
+	util.ErrorLogger.Printf("%s fetch failed, retrying in %v: %v", src.Name(), delay, err)
+	time.AfterFunc(delay, func() {
+		r.runScheduled(src, attempt+1)
+	})
+}
+
+// RunAll runs every registered source concurrently and waits for them to finish
+func (r *Runner) RunAll(ctx context.Context) error {
+	sources := r.registry.Sources()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed []string
+
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+			if err := r.RunSource(ctx, src); err != nil {
+				util.ErrorLogger.Printf("%s fetch failed: %v", src.Name(), err)
+				mu.Lock()
+				failed = append(failed, src.Name())
+				mu.Unlock()
+			}
+		}(src)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d source(s) failed: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RunSource fetches one source and saves each of its series.
+// Partial results are saved even when the source reports an error.
+func (r *Runner) RunSource(ctx context.Context, src Source) error {
+	if _, busy := r.running.LoadOrStore(src.Name(), struct{}{}); busy {
+		util.InfoLogger.Printf("%s is still running, skipping", src.Name())
+		return nil
+	}
+	defer r.running.Delete(src.Name())
+
+	select {
+	case r.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-r.sem }()
+
+	ctx, cancel := context.WithTimeout(ctx, src.Timeout())
+	defer cancel()
+
+	start := time.Now()
+	util.InfoLogger.Printf("Fetching %s...", src.Name())
+	points, fetchErr := fetchRecovering(ctx, src)
+	if errors.Is(fetchErr, context.DeadlineExceeded) {
+		fetchErr = fmt.Errorf("timed out after %v: %w", src.Timeout(), fetchErr)
+	}
+
+	batches, err := GroupBySeries(src, points)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{fetchErr}
+	saved := 0
+	for _, spec := range src.Series() {
+		batch := batches[spec.ID]
+		if len(batch) == 0 {
+			continue
+		}
+
+		latest := Latest(batch)
+		util.InfoLogger.Printf("%s: %.4f (date: %s)", spec.ID, latest.Value, latest.Date)
+		if err := r.store.SavePoints(spec.ID, batch, time.Time{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save %s: %w", spec.ID, err))
+			continue
+		}
+		saved++
+	}
+
+	util.InfoLogger.Printf("%s finished in %v (%d series saved)", src.Name(), time.Since(start).Round(time.Millisecond), saved)
+
+	return errors.Join(errs...)
+}
+
+// fetchRecovering fetches src, turning a panic in its parser into an error so a
+// malformed download fails that source instead of the whole process
+func fetchRecovering(ctx context.Context, src Source) (points []store.SeriesPoint, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			util.ErrorLogger.Printf("%s panicked: %v\n%s", src.Name(), v, debug.Stack())
+			points, err = nil, fmt.Errorf("panic while fetching: %v", v)
+		}
+	}()
+	return src.Fetch(ctx)
+}
@@ -0,0 +1,105 @@
+package ingest
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	util.InitLogger("info")
+
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	return db
+}
+
+func TestRunnerRunAll(t *testing.T) {
+	db := newTestStore(t)
+
+	registry := NewRegistry()
+	registry.MustRegister(NewSource("fast", "", time.Second, []SeriesSpec{{ID: "FAST"}},
+		func(ctx context.Context) ([]store.SeriesPoint, error) {
+			return []store.SeriesPoint{{Date: "2024-01-15", Value: 1.5}}, nil
+		}))
+	registry.MustRegister(NewSource("slow", "", 50*time.Millisecond, []SeriesSpec{{ID: "SLOW"}},
+		func(ctx context.Context) ([]store.SeriesPoint, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}))
+
+	runner := NewRunner(registry, db, 1)
+
+	var mu sync.Mutex
+	var updated []string
+	db.Events().Subscribe("test", func(events []store.SeriesUpdated) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, e := range events {
+			updated = append(updated, e.SeriesID)
+		}
+	})
+
+	err := runner.RunAll(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "slow") {
+		t.Fatalf("Expected slow source to fail, got %v", err)
+	}
+
+	latest, err := db.GetLatestPoint("FAST")
+	if err != nil || latest == nil {
+		t.Fatalf("Expected FAST point to be saved: %v", err)
+	}
+
+	if latest.Value != 1.5 {
+		t.Errorf("Expected value 1.5, got %f", latest.Value)
+	}
+
+	db.Events().Wait()
+	if len(updated) != 1 || updated[0] != "FAST" {
+		t.Errorf("Expected update for FAST only, got %v", updated)
+	}
+
+	// A second run with identical data reports no updates
+	updated = nil
+	_, src, _ := registry.Lookup("FAST")
+	if err := runner.RunSource(context.Background(), src); err != nil {
+		t.Fatalf("Failed to rerun source: %v", err)
+	}
+
+	db.Events().Wait()
+	if len(updated) != 0 {
+		t.Errorf("Expected no updates for unchanged data, got %v", updated)
+	}
+}
+
+func TestRunnerRecoversFromPanic(t *testing.T) {
+	db := newTestStore(t)
+
+	registry := NewRegistry()
+	registry.MustRegister(NewSource("broken", "", time.Second, []SeriesSpec{{ID: "BROKEN"}},
+		func(ctx context.Context) ([]store.SeriesPoint, error) {
+			var cells []string
+			return []store.SeriesPoint{{Date: cells[1]}}, nil
+		}))
+
+	_, src, _ := registry.Lookup("BROKEN")
+	err := NewRunner(registry, db, 1).RunSource(context.Background(), src)
+	if err == nil || !strings.Contains(err.Error(), "panic") {
+		t.Errorf("Expected panic to be returned as an error, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"reserve-watch/internal/store"
+)
+
+// DefaultSchedule is the cron spec used by sources that don't declare their own
+// 6:00 AM EST (11:00 AM UTC), 3.5 hours before US market open
+const DefaultSchedule = "0 11 * * *"
+
+// DefaultTimeout bounds a single Fetch call for sources that don't declare their own
+const DefaultTimeout = 60 * time.Second
+
+// SeriesSpec describes a single series produced by a source
+type SeriesSpec struct {
+	ID           string
+	Name         string
+	Label        string // short name for tiles and posts; defaults to Name
+	Unit         string
+	Frequency    string
+	HistoryStart string // earliest date to backfill (YYYY-MM-DD); empty if no history is available
+	Attribution
+}
+
+// Attribution credits the publisher of a series
+type Attribution struct {
+	Provider   string // short publisher name, e.g. "FRED"
+	License    string
+	LicenseURL string
+	Link       string // the series on the provider's site; "{id}" is replaced by the series ID
+}
+
+// attribute returns a copy of specs credited to a
+func attribute(a Attribution, specs ...SeriesSpec) []SeriesSpec {
+	out := make([]SeriesSpec, len(specs))
+	for i, spec := range specs {
+		spec.Attribution = a
+		spec.Link = strings.ReplaceAll(a.Link, "{id}", spec.ID)
+		out[i] = spec
+	}
+	return out
+}
+
+// Source is an upstream data provider that yields points for one or more series
+// Fetch returns points tagged with Meta["series_id"]; single-series sources may omit the tag.
+// A source may return partial points together with an error.
+// Schedule is a standard 5-field cron spec; Timeout bounds each Fetch call.
+type Source interface {
+	Name() string
+	Schedule() string
+	Timeout() time.Duration
+	Series() []SeriesSpec
+	Fetch(ctx context.Context) ([]store.SeriesPoint, error)
+}
+
+// FetchFunc fetches the latest points for a source
+type FetchFunc func(ctx context.Context) ([]store.SeriesPoint, error)
+
+type funcSource struct {
+	name     string
+	schedule string
+	timeout  time.Duration
+	series   []SeriesSpec
+	fetch    FetchFunc
+}
+
+// NewSource builds a Source from its declared series and a fetch function.
+// An empty schedule or zero timeout falls back to the defaults.
+func NewSource(name, schedule string, timeout time.Duration, series []SeriesSpec, fetch FetchFunc) Source {
+	if schedule == "" {
+		schedule = DefaultSchedule
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &funcSource{
+		name:     name,
+		schedule: schedule,
+		timeout:  timeout,
+		series:   series,
+		fetch:    fetch,
+	}
+}
+
+func (s *funcSource) Name() string           { return s.name }
+func (s *funcSource) Schedule() string       { return s.schedule }
+func (s *funcSource) Timeout() time.Duration { return s.timeout }
+func (s *funcSource) Series() []SeriesSpec   { return s.series }
+
+func (s *funcSource) Fetch(ctx context.Context) ([]store.SeriesPoint, error) {
+	return s.fetch(ctx)
+}
+
+// Registry holds all ingest sources in registration order
+type Registry struct {
+	mu      sync.RWMutex
+	sources []Source
+	series  map[string]SeriesSpec
+	owners  map[string]Source
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		series: make(map[string]SeriesSpec),
+		owners: make(map[string]Source),
+	}
+}
+
+// Register adds a source; series IDs must be unique across the registry
+func (r *Registry) Register(src Source) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(src.Series()) == 0 {
+		return fmt.Errorf("source %s declares no series", src.Name())
+	}
+
+	for _, existing := range r.sources {
+		if existing.Name() == src.Name() {
+			return fmt.Errorf("source %s already registered", src.Name())
+		}
+	}
+
+	for _, spec := range src.Series() {
+		if spec.ID == "" {
+			return fmt.Errorf("source %s declares a series without an ID", src.Name())
+		}
+		if owner, ok := r.owners[spec.ID]; ok {
+			return fmt.Errorf("series %s already registered by source %s", spec.ID, owner.Name())
+		}
+	}
+
+	for _, spec := range src.Series() {
+		r.series[spec.ID] = spec
+		r.owners[spec.ID] = src
+	}
+	r.sources = append(r.sources, src)
+
+	return nil
+}
+
+// MustRegister is like Register but panics on error, for use with static source lists
+func (r *Registry) MustRegister(src Source) {
+	if err := r.Register(src); err != nil {
+		panic(err)
+	}
+}
+
+// Sources returns all registered sources in registration order
+func (r *Registry) Sources() []Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sources := make([]Source, len(r.sources))
+	copy(sources, r.sources)
+	return sources
+}
+
+// AllSeries returns every series declared by registered sources
+func (r *Registry) AllSeries() []SeriesSpec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var specs []SeriesSpec
+	for _, src := range r.sources {
+		specs = append(specs, src.Series()...)
+	}
+	return specs
+}
+
+// Catalog describes every registered series for the store's series catalog
+func (r *Registry) Catalog() []store.Series {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var catalog []store.Series
+	for _, src := range r.sources {
+		for _, spec := range src.Series() {
+			catalog = append(catalog, store.Series{
+				ID:         spec.ID,
+				Name:       spec.Name,
+				Label:      spec.Label,
+				Unit:       spec.Unit,
+				Frequency:  spec.Frequency,
+				Source:     src.Name(),
+				Provider:   spec.Provider,
+				License:    spec.License,
+				LicenseURL: spec.LicenseURL,
+				Link:       spec.Link,
+			})
+		}
+	}
+	return catalog
+}
+
+// Lookup returns the spec and owning source for a series ID
+func (r *Registry) Lookup(seriesID string) (SeriesSpec, Source, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	spec, ok := r.series[seriesID]
+	if !ok {
+		return SeriesSpec{}, nil, false
+	}
+	return spec, r.owners[seriesID], true
+}
+
+// GroupBySeries splits fetched points by series ID and fills in unit/frequency/source
+// meta from the declared spec. Points for undeclared series are rejected.
+func GroupBySeries(src Source, points []store.SeriesPoint) (map[string][]store.SeriesPoint, error) {
+	specs := make(map[string]SeriesSpec)
+	for _, spec := range src.Series() {
+		specs[spec.ID] = spec
+	}
+
+	grouped := make(map[string][]store.SeriesPoint)
+	for _, p := range points {
+		seriesID := p.Meta["series_id"]
+		if seriesID == "" && len(specs) == 1 {
+			seriesID = src.Series()[0].ID
+		}
+
+		spec, ok := specs[seriesID]
+		if !ok {
+			return nil, fmt.Errorf("source %s returned undeclared series %q", src.Name(), seriesID)
+		}
+
+		meta := make(map[string]string, len(p.Meta)+4)
+		for k, v := range p.Meta {
+			meta[k] = v
+		}
+		meta["series_id"] = spec.ID
+		if meta["source"] == "" {
+			meta["source"] = src.Name()
+		}
+		if meta["unit"] == "" && spec.Unit != "" {
+			meta["unit"] = spec.Unit
+		}
+		if meta["frequency"] == "" && spec.Frequency != "" {
+			meta["frequency"] = spec.Frequency
+		}
+		p.Meta = meta
+
+		grouped[seriesID] = append(grouped[seriesID], p)
+	}
+
+	return grouped, nil
+}
+
+// Latest returns the point with the most recent date
+func Latest(points []store.SeriesPoint) store.SeriesPoint {
+	var latest store.SeriesPoint
+	for _, p := range points {
+		if p.Date > latest.Date {
+			latest = p
+		}
+	}
+	return latest
+}
@@ -0,0 +1,128 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"reserve-watch/internal/store"
+)
+
+func newTestSource(name string, ids ...string) Source {
+	var specs []SeriesSpec
+	for _, id := range ids {
+		specs = append(specs, SeriesSpec{ID: id, Unit: "index", Frequency: "daily"})
+	}
+	return NewSource(name, "", 0, specs, func(ctx context.Context) ([]store.SeriesPoint, error) {
+		return nil, nil
+	})
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+
+	if err := r.Register(newTestSource("a", "SERIES_A", "SERIES_B")); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+
+	if err := r.Register(newTestSource("a", "SERIES_C")); err == nil {
+		t.Error("Expected error for duplicate source name")
+	}
+
+	if err := r.Register(newTestSource("b", "SERIES_B")); err == nil {
+		t.Error("Expected error for duplicate series ID")
+	}
+
+	if len(r.Sources()) != 1 {
+		t.Errorf("Expected 1 source, got %d", len(r.Sources()))
+	}
+
+	if len(r.AllSeries()) != 2 {
+		t.Errorf("Expected 2 series, got %d", len(r.AllSeries()))
+	}
+
+	spec, src, ok := r.Lookup("SERIES_B")
+	if !ok || src.Name() != "a" || spec.ID != "SERIES_B" {
+		t.Errorf("Expected SERIES_B to be owned by source a, got %v %v", spec, ok)
+	}
+
+	if src.Schedule() != DefaultSchedule {
+		t.Errorf("Expected default schedule, got %s", src.Schedule())
+	}
+
+	if src.Timeout() != DefaultTimeout {
+		t.Errorf("Expected default timeout, got %v", src.Timeout())
+	}
+}
+
+func TestGroupBySeries(t *testing.T) {
+	src := newTestSource("multi", "SERIES_A", "SERIES_B")
+
+	points := []store.SeriesPoint{
+		{Date: "2024-01-15", Value: 1, Meta: map[string]string{"series_id": "SERIES_A"}},
+		{Date: "2024-01-15", Value: 2, Meta: map[string]string{"series_id": "SERIES_B", "unit": "percent"}},
+		{Date: "2024-01-16", Value: 3, Meta: map[string]string{"series_id": "SERIES_A"}},
+	}
+
+	grouped, err := GroupBySeries(src, points)
+	if err != nil {
+		t.Fatalf("Failed to group points: %v", err)
+	}
+
+	if len(grouped["SERIES_A"]) != 2 {
+		t.Errorf("Expected 2 points for SERIES_A, got %d", len(grouped["SERIES_A"]))
+	}
+
+	if got := grouped["SERIES_A"][0].Meta["source"]; got != "multi" {
+		t.Errorf("Expected source meta to default to source name, got %s", got)
+	}
+
+	if got := grouped["SERIES_B"][0].Meta["unit"]; got != "percent" {
+		t.Errorf("Expected explicit unit to be kept, got %s", got)
+	}
+
+	if latest := Latest(grouped["SERIES_A"]); latest.Date != "2024-01-16" {
+		t.Errorf("Expected latest date 2024-01-16, got %s", latest.Date)
+	}
+
+	undeclared := []store.SeriesPoint{{Date: "2024-01-15", Meta: map[string]string{"series_id": "OTHER"}}}
+	if _, err := GroupBySeries(src, undeclared); err == nil {
+		t.Error("Expected error for undeclared series")
+	}
+}
+
+func TestGroupBySeriesSingleSeriesDefault(t *testing.T) {
+	src := newTestSource("single", "ONLY")
+
+	grouped, err := GroupBySeries(src, []store.SeriesPoint{{Date: "2024-01-15", Value: 1}})
+	if err != nil {
+		t.Fatalf("Failed to group points: %v", err)
+	}
+
+	if len(grouped["ONLY"]) != 1 {
+		t.Errorf("Expected untagged point to be assigned to ONLY, got %v", grouped)
+	}
+}
+
+func TestRegistryCatalog(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(NewSource("fred", "", 0, attribute(fredAttribution,
+		SeriesSpec{ID: "VIXCLS", Name: "CBOE Volatility Index (VIX)", Label: "VIX", Unit: "index", Frequency: "daily"},
+	), nil))
+	r.MustRegister(newTestSource("b", "SERIES_B"))
+
+	catalog := r.Catalog()
+	if len(catalog) != 2 {
+		t.Fatalf("Expected 2 catalog entries, got %d", len(catalog))
+	}
+
+	vix := catalog[0]
+	if vix.ID != "VIXCLS" || vix.Source != "fred" || vix.Label != "VIX" || vix.Provider != "FRED" || vix.LicenseURL == "" {
+		t.Errorf("Expected VIX entry with FRED attribution, got %+v", vix)
+	}
+	if vix.Link != "https://fred.stlouisfed.org/series/VIXCLS" {
+		t.Errorf("Expected link with the series ID filled in, got %s", vix.Link)
+	}
+	if catalog[1].Source != "b" || catalog[1].Provider != "" {
+		t.Errorf("Expected unattributed entry for source b, got %+v", catalog[1])
+	}
+}
@@ -0,0 +1,156 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"reserve-watch/internal/config"
+	"reserve-watch/internal/store"
+)
+
+// Attribution for each publisher, shown in the series catalog and on homepage tiles
+var (
+	fredAttribution = Attribution{
+		Provider:   "FRED",
+		License:    "FRED terms of use; cite FRED, Federal Reserve Bank of St. Louis",
+		LicenseURL: "https://fred.stlouisfed.org/legal/",
+		Link:       "https://fred.stlouisfed.org/series/{id}",
+	}
+	yahooAttribution = Attribution{
+		Provider:   "Yahoo Finance",
+		License:    "Indicative data for informational use; not for redistribution",
+		LicenseURL: "https://legal.yahoo.com/us/en/yahoo/terms/otos/index.html",
+		Link:       "https://finance.yahoo.com/quote/DX-Y.NYB",
+	}
+	imfAttribution = Attribution{
+		Provider:   "IMF COFER",
+		License:    "IMF terms of use; cite International Monetary Fund, COFER",
+		LicenseURL: "https://www.imf.org/external/terms.htm",
+		Link:       "https://data.imf.org/?sk=E6A5F467-C14B-4AA8-9F6D-5A09EC4E62A4",
+	}
+	swiftAttribution = Attribution{
+		Provider: "SWIFT RMB Tracker",
+		License:  "Published in SWIFT's public RMB Tracker reports",
+		Link:     "https://www.swift.com/swift-resource/248201/download",
+	}
+	cipsAttribution = Attribution{
+		Provider: "CIPS",
+		License:  "Published on the CIPS website",
+		Link:     "https://www.cips.com.cn/en/index/index.html",
+	}
+	wgcAttribution = Attribution{
+		Provider:   "World Gold Council",
+		License:    "© World Gold Council; WGC terms and conditions",
+		LicenseURL: "https://www.gold.org/terms-and-conditions",
+	}
+)
+
+// fredSeries lists the FRED series we track; add new FRED series here
+var fredSeries = attribute(fredAttribution,
+	SeriesSpec{ID: "DTWEXBGS", Name: "Nominal Broad U.S. Dollar Index", Label: "USD Index", Unit: "index_jan2006_100", Frequency: "daily", HistoryStart: "2006-01-02"},
+	SeriesSpec{ID: "VIXCLS", Name: "CBOE Volatility Index (VIX)", Label: "VIX", Unit: "index", Frequency: "daily", HistoryStart: "1990-01-02"},
+	SeriesSpec{ID: "BAMLC0A4CBBB", Name: "ICE BofA BBB US Corporate Index OAS", Label: "BBB Credit Spreads", Unit: "percent", Frequency: "daily", HistoryStart: "1996-12-31"},
+)
+
+// coferSeries lists the world COFER series; currency shares are percent of allocated reserves
+var coferSeries = attribute(imfAttribution,
+	SeriesSpec{ID: "COFER_USD", Name: "USD Share of Allocated FX Reserves", Label: "COFER USD", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_EUR", Name: "EUR Share of Allocated FX Reserves", Label: "COFER EUR", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: "1999-01-01"},
+	SeriesSpec{ID: "COFER_JPY", Name: "JPY Share of Allocated FX Reserves", Label: "COFER JPY", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_GBP", Name: "GBP Share of Allocated FX Reserves", Label: "COFER GBP", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_CNY", Name: "CNY Share of Allocated FX Reserves", Label: "COFER CNY", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: "2016-10-01"},
+	SeriesSpec{ID: "COFER_CAD", Name: "CAD Share of Allocated FX Reserves", Label: "COFER CAD", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: "2012-10-01"},
+	SeriesSpec{ID: "COFER_AUD", Name: "AUD Share of Allocated FX Reserves", Label: "COFER AUD", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: "2012-10-01"},
+	SeriesSpec{ID: "COFER_CHF", Name: "CHF Share of Allocated FX Reserves", Label: "COFER CHF", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_OTHER", Name: "Other Currencies Share of Allocated FX Reserves", Label: "COFER Other", Unit: "percent_of_allocated_reserves", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_TOTAL", Name: "Total Foreign Exchange Reserves", Label: "FX Reserves", Unit: "usd_billions", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_ALLOCATED", Name: "Allocated Foreign Exchange Reserves", Label: "Allocated Reserves", Unit: "usd_billions", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+	SeriesSpec{ID: "COFER_UNALLOCATED", Name: "Unallocated Foreign Exchange Reserves", Label: "Unallocated Reserves", Unit: "usd_billions", Frequency: "quarterly", HistoryStart: coferHistoryStart},
+)
+
+// Source schedules follow each publisher's release cadence.
+// Times are UTC; quarterly/monthly sources are polled weekly so a release is picked up within days.
+const (
+	scheduleYahoo = "*/15 * * * *" // intraday DXY, every 15 minutes
+	scheduleFRED  = DefaultSchedule
+	scheduleCIPS  = "0 12 * * *"
+	scheduleSWIFT = "0 12 * * 1"
+	scheduleIMF   = "30 12 * * 1"
+	scheduleWGC   = "0 13 * * 1"
+)
+
+// NewDefaultRegistry registers every production data source
+func NewDefaultRegistry(cfg *config.Config) *Registry {
+	r := NewRegistry()
+
+	r.MustRegister(NewFREDSource(NewFREDClient(cfg.FREDAPIKey), fredSeries))
+
+	yahoo := NewYahooFinanceClient()
+	r.MustRegister(WithBackfill(NewSource("yahoo_finance", scheduleYahoo, 20*time.Second, attribute(yahooAttribution,
+		SeriesSpec{ID: "DXY_REALTIME", Name: "US Dollar Index (Real-Time)", Label: "DXY", Unit: "index", Frequency: "intraday", HistoryStart: "2000-01-03"},
+	), func(ctx context.Context) ([]store.SeriesPoint, error) {
+		p, err := yahoo.FetchDXY(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []store.SeriesPoint{p}, nil
+	}), func(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error) {
+		return yahoo.FetchDXYRange(ctx, start, end)
+	}))
+
+	imf := NewIMFClient()
+	r.MustRegister(WithSourceBackfill(NewSource("imf_cofer", scheduleIMF, 2*time.Minute, coferSeries, imf.FetchCOFER), imf.FetchCOFERRange))
+
+	swift := NewSWIFTClient()
+	r.MustRegister(NewSource("swift_rmb_tracker", scheduleSWIFT, 2*time.Minute, attribute(swiftAttribution,
+		SeriesSpec{ID: "SWIFT_RMB", Name: "RMB Share of Global SWIFT Payments", Label: "SWIFT RMB", Unit: "percent_of_payments", Frequency: "monthly"},
+		SeriesSpec{ID: "SWIFT_RMB_RANK", Name: "RMB Rank Among SWIFT Payment Currencies", Label: "SWIFT RMB Rank", Unit: "rank", Frequency: "monthly"},
+		SeriesSpec{ID: "SWIFT_USD", Name: "USD Share of Global SWIFT Payments", Label: "SWIFT USD", Unit: "percent_of_payments", Frequency: "monthly"},
+	), swift.FetchRMBTrackerData))
+
+	cips := NewCIPSClient()
+	r.MustRegister(NewSource("cips", scheduleCIPS, time.Minute, attribute(cipsAttribution,
+		SeriesSpec{ID: "CIPS_PARTICIPANTS", Name: "CIPS Participants", Label: "CIPS Network", Unit: "count", Frequency: "updated_irregularly"},
+		SeriesSpec{ID: "CIPS_DAILY_AVG", Name: "CIPS Daily Average Volume", Label: "CIPS Daily Volume", Unit: "billion_rmb", Frequency: "daily_average"},
+		SeriesSpec{ID: "CIPS_ANNUAL_VOLUME", Name: "CIPS Annual Volume", Label: "CIPS Annual Volume", Unit: "trillion_rmb", Frequency: "annual"},
+	), cips.GetCIPSSeriesPoints))
+
+	wgc := NewWGCClient(cfg.WGCDemandURL, cfg.WGCReservesURL, cfg.WGCManualDir)
+	wgcDemand, wgcReserves := wgcAttribution, wgcAttribution
+	wgcDemand.Link = "https://www.gold.org/goldhub/research/gold-demand-trends"
+	wgcReserves.Link = "https://www.gold.org/goldhub/data/gold-reserves-by-country"
+	r.MustRegister(NewSource("world_gold_council", scheduleWGC, 2*time.Minute, append(
+		attribute(wgcDemand, SeriesSpec{ID: "WGC_CB_PURCHASES", Name: "Central Bank Gold Purchases", Label: "CB Gold Buying", Unit: "tonnes", Frequency: "quarterly"}),
+		attribute(wgcReserves, SeriesSpec{ID: "WGC_GOLD_RESERVE_SHARE", Name: "Gold Share of World Official Reserves", Label: "Gold Reserve Share", Unit: "percent_of_reserves", Frequency: "quarterly"})...,
+	), wgc.Fetch))
+
+	return r
+}
+
+// NewFREDSource exposes a fixed set of FRED series as one source.
+// A failing series doesn't block the others: Fetch returns the points it got
+// along with a joined error.
+func NewFREDSource(client *FREDClient, series []SeriesSpec) Backfiller {
+	src := NewSource("fred", scheduleFRED, time.Minute, series, func(ctx context.Context) ([]store.SeriesPoint, error) {
+		var points []store.SeriesPoint
+		var errs []error
+
+		for _, spec := range series {
+			result := client.FetchSeries(ctx, spec.ID)
+			if result.Err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", spec.ID, result.Err))
+				continue
+			}
+			points = append(points, result.Points...)
+		}
+
+		return points, errors.Join(errs...)
+	})
+
+	return WithBackfill(src, func(ctx context.Context, seriesID, start, end string) ([]store.SeriesPoint, error) {
+		result := client.FetchSeriesRange(ctx, seriesID, start, end)
+		return result.Points, result.Err
+	})
+}
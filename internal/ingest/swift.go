@@ -1,92 +1,259 @@
 package ingest
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+const (
+	// swiftDocumentCentre lists RMB Tracker reports, newest first
+	swiftDocumentCentre = "https://www.swift.com/our-solutions/compliance-and-shared-services/business-intelligence/renminbi/rmb-tracker/document-centre"
+	// swiftTrackerFallback is a known tracker download, used when the document centre can't be scraped
+	swiftTrackerFallback = "https://www.swift.com/swift-resource/248201/download"
 )
 
 type SWIFTClient struct {
-	httpClient *http.Client
+	httpClient     *http.Client
+	documentCentre string
 }
 
 func NewSWIFTClient() *SWIFTClient {
 	return &SWIFTClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+		httpClient:     newHTTPClient(30 * time.Second),
+		documentCentre: swiftDocumentCentre,
+	}
+}
+
+// RMBTrackerReport holds the headline figures from one SWIFT RMB Tracker
+type RMBTrackerReport struct {
+	Period string  // last day of the report month (YYYY-MM-DD)
+	Share  float64 // RMB share of global payments by value, percent
+	Rank   int     // RMB rank among global payment currencies
+
+	USDShare float64 // USD share of global payments by value, percent (0 if not reported)
+}
+
+// FetchRMBTrackerData downloads the latest RMB Tracker PDF and returns SWIFT_RMB,
+// SWIFT_RMB_RANK and SWIFT_USD points dated at the end of the report month
+func (c *SWIFTClient) FetchRMBTrackerData(ctx context.Context) ([]store.SeriesPoint, error) {
+	reportURL, err := c.latestTrackerURL(ctx)
+	if err != nil {
+		util.ErrorLogger.Printf("SWIFT document centre unavailable, using fallback tracker URL: %v", err)
+		reportURL = swiftTrackerFallback
+	}
+
+	body, err := c.get(ctx, reportURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SWIFT RMB Tracker: %w", err)
+	}
+
+	text, err := ExtractPDFText(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SWIFT RMB Tracker PDF: %w", err)
+	}
+
+	report, err := ParseRMBTracker(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.Points(reportURL), nil
+}
+
+// Points converts the report into SWIFT_RMB, SWIFT_RMB_RANK and SWIFT_USD observations
+func (r *RMBTrackerReport) Points(reportURL string) []store.SeriesPoint {
+	points := []store.SeriesPoint{{
+		Date:  r.Period,
+		Value: r.Share,
+		Meta: map[string]string{
+			"series_id":  "SWIFT_RMB",
+			"source":     "SWIFT",
+			"unit":       "percent_of_payments",
+			"frequency":  "monthly",
+			"report_url": reportURL,
 		},
+	}}
+
+	if r.Rank > 0 {
+		points = append(points, store.SeriesPoint{
+			Date:  r.Period,
+			Value: float64(r.Rank),
+			Meta: map[string]string{
+				"series_id":  "SWIFT_RMB_RANK",
+				"source":     "SWIFT",
+				"unit":       "rank",
+				"frequency":  "monthly",
+				"report_url": reportURL,
+			},
+		})
+	}
+
+	if r.USDShare > 0 {
+		points = append(points, store.SeriesPoint{
+			Date:  r.Period,
+			Value: r.USDShare,
+			Meta: map[string]string{
+				"series_id":  "SWIFT_USD",
+				"source":     "SWIFT",
+				"unit":       "percent_of_payments",
+				"frequency":  "monthly",
+				"report_url": reportURL,
+			},
+		})
 	}
+
+	return points
 }
 
-// FetchRMBTrackerData attempts to fetch latest RMB payment share from SWIFT
-// Note: SWIFT publishes monthly PDFs, which require parsing
-// This is a simplified version that would need PDF parsing in production
-func (c *SWIFTClient) FetchRMBTrackerData() (store.SeriesPoint, error) {
-	// SWIFT RMB Tracker URL (latest report)
-	// In production, this would:
-	// 1. Scrape the document centre page for latest PDF
-	// 2. Download the PDF
-	// 3. Parse the "RMB as % of global payments" figure
-	// 4. Extract the rank (e.g., "5th most used currency")
+var swiftTrackerLink = regexp.MustCompile(`href="([^"]*/swift-resource/(\d+)/download[^"]*)"`)
+
+// latestTrackerURL scrapes the document centre for the newest tracker download.
+// Resource IDs increase with each upload, so the highest ID is the latest report.
+func (c *SWIFTClient) latestTrackerURL(ctx context.Context) (string, error) {
+	body, err := c.get(ctx, c.documentCentre)
+	if err != nil {
+		return "", err
+	}
+
+	best, bestID := "", -1
+	for _, m := range swiftTrackerLink.FindAllStringSubmatch(string(body), -1) {
+		id, err := strconv.Atoi(m[2])
+		if err != nil || id <= bestID {
+			continue
+		}
+		best, bestID = m[1], id
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no tracker downloads found on %s", c.documentCentre)
+	}
 
-	// For MVP, we'll use a hardcoded recent value and add a scraper later
-	// Real implementation would use a PDF parsing library like pdftotext or Apache PDFBox
+	base, err := url.Parse(c.documentCentre)
+	if err != nil {
+		return "", err
+	}
+	link, err := base.Parse(strings.ReplaceAll(best, "&amp;", "&"))
+	if err != nil {
+		return "", err
+	}
+	return link.String(), nil
+}
 
-	url := "https://www.swift.com/swift-resource/248201/download"
+func (c *SWIFTClient) get(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to fetch SWIFT RMB Tracker: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return store.SeriesPoint{}, fmt.Errorf("SWIFT returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("SWIFT returned status %d for %s", resp.StatusCode, target)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	return io.ReadAll(resp.Body)
+}
+
+var (
+	rmbMonthYear = regexp.MustCompile(`(?i)\b(January|February|March|April|May|June|July|August|September|October|November|December)\s+(20\d{2})\b`)
+
+	// "... the RMB ... #4 most active currency ... with a share of 4.69%"
+	rmbHeadlineShare = regexp.MustCompile(`(?is)(?:RMB|renminbi)\b.{0,200}?\bshare\s+of\s+(\d{1,2}(?:\.\d+)?)\s*%`)
+	rmbHeadlineRank  = regexp.MustCompile(`(?is)(?:RMB|renminbi)\b.{0,120}?(?:#\s*(\d{1,2})\b|\b(\d{1,2})(?:st|nd|rd|th)\s+most)`)
+
+	// Ranking table rows: "4 RMB 4.69%", "1 USD 47.07%"
+	rmbTableRow = regexp.MustCompile(`(?m)^\s*(\d{1,2})\s+(?:RMB|CNY)\s+(\d{1,2}\.\d+)\s*%`)
+	usdTableRow = regexp.MustCompile(`(?m)^\s*(\d{1,2})\s+USD\s+(\d{1,2}\.\d+)\s*%`)
+)
+
+// ParseRMBTracker extracts the RMB payment share, rank and report month from tracker text,
+// plus the USD share from the global ranking table when present.
+//
+// The headline sentence is preferred; the global ranking table is used as a fallback.
+// A tracker mentions both its publication month and its data month; the data month is
+// the one repeated across chart captions, so the most frequent month wins.
+func ParseRMBTracker(text string) (*RMBTrackerReport, error) {
+	period, err := trackerPeriod(text)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to read SWIFT response: %w", err)
+		return nil, err
 	}
 
-	// Parse RMB percentage from PDF text
-	// Pattern: looking for "X.XX%" near "RMB" or "renminbi"
-	rmbPattern := regexp.MustCompile(`(?i)rmb.*?(\d+\.\d+)%|renminbi.*?(\d+\.\d+)%`)
-	matches := rmbPattern.FindStringSubmatch(string(body))
+	report := &RMBTrackerReport{Period: period}
 
-	var rmbShare float64
-	if len(matches) > 1 {
-		if matches[1] != "" {
-			rmbShare, _ = strconv.ParseFloat(matches[1], 64)
-		} else if matches[2] != "" {
-			rmbShare, _ = strconv.ParseFloat(matches[2], 64)
+	if m := rmbHeadlineShare.FindStringSubmatch(text); m != nil {
+		report.Share, _ = strconv.ParseFloat(m[1], 64)
+	}
+	if m := rmbHeadlineRank.FindStringSubmatch(text); m != nil {
+		rank := m[1]
+		if rank == "" {
+			rank = m[2]
 		}
+		report.Rank, _ = strconv.Atoi(rank)
 	}
 
-	// If parsing fails, return error - NO FAKE DATA
-	if rmbShare == 0 {
-		return store.SeriesPoint{}, fmt.Errorf("failed to parse RMB percentage from SWIFT PDF - PDF parsing not yet implemented")
+	if report.Share == 0 || report.Rank == 0 {
+		if m := rmbTableRow.FindStringSubmatch(text); m != nil {
+			if report.Rank == 0 {
+				report.Rank, _ = strconv.Atoi(m[1])
+			}
+			if report.Share == 0 {
+				report.Share, _ = strconv.ParseFloat(m[2], 64)
+			}
+		}
 	}
 
-	// Use current month as date
-	now := time.Now()
-	dateStr := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
+	// The first ranking table is global payments; later ones exclude the Eurozone
+	if m := usdTableRow.FindStringSubmatch(text); m != nil {
+		report.USDShare, _ = strconv.ParseFloat(m[2], 64)
+	}
 
-	return store.SeriesPoint{
-		Date:  dateStr,
-		Value: rmbShare,
-		Meta: map[string]string{
-			"series_id": "SWIFT_RMB",
-			"source":    "SWIFT",
-			"unit":      "percent_of_payments",
-			"frequency": "monthly",
-			"note":      "Requires PDF parsing - using approximation",
-		},
-	}, nil
+	if report.Share <= 0 || report.Share >= 100 {
+		return nil, fmt.Errorf("RMB payment share not found in SWIFT RMB Tracker")
+	}
+
+	return report, nil
+}
+
+// trackerPeriod returns the month-end date of the most frequently mentioned month
+func trackerPeriod(text string) (string, error) {
+	counts := make(map[string]int)
+	var order []string
+
+	for _, m := range rmbMonthYear.FindAllStringSubmatch(text, -1) {
+		t, err := time.Parse("January 2006", m[1]+" "+m[2])
+		if err != nil {
+			continue
+		}
+		key := t.AddDate(0, 1, -1).Format("2006-01-02")
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+	}
+
+	best := ""
+	for _, key := range order {
+		if best == "" || counts[key] > counts[best] {
+			best = key
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("report month not found in SWIFT RMB Tracker")
+	}
+	return best, nil
 }
@@ -0,0 +1,150 @@
+package ingest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"reserve-watch/internal/util"
+)
+
+func TestParseRMBTrackerFixtures(t *testing.T) {
+	tests := []struct {
+		file   string
+		period string
+		share  float64
+		rank   int
+		usd    float64
+	}{
+		// Uncompressed, simple font, ranking table inside a form XObject
+		{"testdata/swift_rmb_tracker_2023_12.pdf", "2023-12-31", 4.14, 4, 47.54},
+		// Compressed object streams, Type0 font with ToUnicode CMap, split content streams
+		{"testdata/swift_rmb_tracker_2024_09.pdf", "2024-09-30", 4.69, 4, 47.07},
+	}
+
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatalf("Failed to read fixture: %v", err)
+		}
+
+		text, err := ExtractPDFText(data)
+		if err != nil {
+			t.Fatalf("%s: failed to extract text: %v", tt.file, err)
+		}
+
+		report, err := ParseRMBTracker(text)
+		if err != nil {
+			t.Fatalf("%s: failed to parse tracker: %v\n%s", tt.file, err, text)
+		}
+
+		if report.Period != tt.period || report.Share != tt.share || report.Rank != tt.rank || report.USDShare != tt.usd {
+			t.Errorf("%s: expected %s %.2f%% #%d (USD %.2f%%), got %+v", tt.file, tt.period, tt.share, tt.rank, tt.usd, report)
+		}
+	}
+}
+
+func TestExtractPDFTextDecodesToUnicode(t *testing.T) {
+	data, err := os.ReadFile("testdata/swift_rmb_tracker_2024_09.pdf")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	text, err := ExtractPDFText(data)
+	if err != nil {
+		t.Fatalf("Failed to extract text: %v", err)
+	}
+
+	// Curly apostrophe comes from a bfchar entry, words from TJ kerning
+	for _, want := range []string{"RMB’s share", "with a share of 4.69%", "excluding payments within the Eurozone"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected extracted text to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	if _, err := ExtractPDFText([]byte("<html>not a pdf</html>")); err == nil {
+		t.Error("Expected error for non-PDF input")
+	}
+}
+
+func TestExtractPDFTextMalformed(t *testing.T) {
+	// Truncated and corrupt files must fail or yield partial text, never panic
+	for _, data := range []string{
+		"%PDF-1.4\n1 0 obj <</Type /Page>> <",
+		"%PDF-1.4\n1 0 obj <</Length -50>> stream\nabc\nendstream endobj",
+		"%PDF-1.4\n1 0 obj <</Length 99999999999>> stream\nabc\nendstream endobj",
+		"%PDF-1.4\n1 0 obj <</Type /ObjStm /N 1 /First -5 /Length 3>> stream\nabc\nendstream endobj",
+		"%PDF-1.4\n1 0 obj <</Type /ObjStm /N 1 /First 4 /Length 8>> stream\n1 -9 <<>>\nendstream endobj",
+	} {
+		ExtractPDFText([]byte(data))
+	}
+}
+
+func TestParseRMBTrackerTableFallback(t *testing.T) {
+	text := "RMB Tracker\nMarch 2024\nBased on value. February 2024\n1 USD 46.5%\n2 EUR 22.0%\n5 RMB 3.85%\nFebruary 2024"
+
+	report, err := ParseRMBTracker(text)
+	if err != nil {
+		t.Fatalf("Failed to parse tracker: %v", err)
+	}
+
+	if report.Period != "2024-02-29" || report.Share != 3.85 || report.Rank != 5 {
+		t.Errorf("Expected 2024-02-29 3.85%% #5, got %+v", report)
+	}
+
+	if _, err := ParseRMBTracker("RMB Tracker\nMarch 2024\nNo figures this month"); err == nil {
+		t.Error("Expected error when no share is present")
+	}
+}
+
+func TestFetchRMBTrackerData(t *testing.T) {
+	util.InitLogger("info")
+
+	pdf, err := os.ReadFile("testdata/swift_rmb_tracker_2024_09.pdf")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/document-centre":
+			w.Write([]byte(`<a href="/swift-resource/251001/download?language=en">RMB Tracker August 2024</a>
+				<a href="/swift-resource/251437/download?language=en">RMB Tracker September 2024</a>`))
+		case "/swift-resource/251437/download":
+			w.Write(pdf)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewSWIFTClient()
+	client.httpClient = server.Client()
+	client.documentCentre = server.URL + "/document-centre"
+
+	points, err := client.FetchRMBTrackerData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch tracker: %v", err)
+	}
+
+	if len(points) != 3 {
+		t.Fatalf("Expected share, rank and USD share points, got %d", len(points))
+	}
+
+	share, rank := points[0], points[1]
+	if share.Meta["series_id"] != "SWIFT_RMB" || share.Date != "2024-09-30" || share.Value != 4.69 {
+		t.Errorf("Unexpected share point %+v", share)
+	}
+	if rank.Meta["series_id"] != "SWIFT_RMB_RANK" || rank.Date != "2024-09-30" || rank.Value != 4 {
+		t.Errorf("Unexpected rank point %+v", rank)
+	}
+	if usd := points[2]; usd.Meta["series_id"] != "SWIFT_USD" || usd.Value != 47.07 {
+		t.Errorf("Unexpected USD share point %+v", usd)
+	}
+	if !strings.HasSuffix(share.Meta["report_url"], "/swift-resource/251437/download?language=en") {
+		t.Errorf("Expected latest report URL, got %s", share.Meta["report_url"])
+	}
+}
@@ -0,0 +1,400 @@
+package ingest
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a host has failed repeatedly and is cooling down
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CacheStatusHeader is set to "revalidated" on responses served from cache after a 304
+const CacheStatusHeader = "X-Ingest-Cache"
+
+// maxCachedBody caps the size of response bodies kept for conditional requests
+const maxCachedBody = 16 << 20
+
+// credentialParams are query parameters left out of cache keys, so API keys aren't
+// held in memory or reported in errors
+var credentialParams = []string{"api_key", "apikey", "access_token", "token"}
+
+type noCacheKey struct{}
+
+// withoutCache marks requests made with ctx as one-off, e.g. backfill pages, so
+// their responses aren't kept for conditional requests
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// DefaultTransport is shared by every ingest client so breaker state and cached
+// validators are per host/URL across the whole process
+var DefaultTransport = NewTransport(http.DefaultTransport)
+
+// newHTTPClient returns a client using the shared ingest transport
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: DefaultTransport,
+	}
+}
+
+// Transport is an http.RoundTripper for upstream data providers. It adds:
+//   - retries with exponential backoff and full jitter on network errors, 429 and 5xx
+//   - Retry-After handling (delta-seconds or HTTP date)
+//   - a per-host circuit breaker that fails fast after repeated failed requests
+//   - conditional GETs (ETag / If-Modified-Since) that reuse the cached body on 304
+//
+// Only GET and HEAD requests are retried. A request counts as one breaker failure
+// once its retries are exhausted. GETs that carry their own validators bypass the cache.
+// Cached bodies are evicted least recently used first once they pass MaxCacheBytes.
+type Transport struct {
+	Base             http.RoundTripper
+	MaxRetries       int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	MaxRetryAfter    time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	MaxCacheBytes    int
+
+	mu         sync.Mutex
+	breakers   map[string]*breaker
+	cache      map[string]*list.Element // of *cachedResponse
+	cacheOrder *list.List               // most recently used first
+	cacheBytes int
+
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+}
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+type cachedResponse struct {
+	key          string
+	etag         string
+	lastModified string
+	header       http.Header
+	body         []byte
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:             base,
+		MaxRetries:       3,
+		BaseDelay:        500 * time.Millisecond,
+		MaxDelay:         10 * time.Second,
+		MaxRetryAfter:    2 * time.Minute,
+		BreakerThreshold: 5,
+		BreakerCooldown:  5 * time.Minute,
+		MaxCacheBytes:    64 << 20,
+		breakers:         make(map[string]*breaker),
+		cache:            make(map[string]*list.Element),
+		cacheOrder:       list.New(),
+		sleep:            sleepContext,
+		now:              time.Now,
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	probe, err := t.allow(host)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed half-open probe re-opens the breaker straight away
+	retryable := !probe && (req.Method == http.MethodGet || req.Method == http.MethodHead)
+	cacheKey := ""
+	if req.Method == http.MethodGet && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" &&
+		req.Context().Value(noCacheKey{}) == nil {
+		cacheKey = cacheKeyFor(req.URL)
+		req = t.addValidators(req, cacheKey)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Base.RoundTrip(req)
+
+		failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !failed {
+			t.recordSuccess(host)
+			if cacheKey != "" {
+				return t.handleCache(cacheKey, resp)
+			}
+			return resp, nil
+		}
+
+		if !retryable || attempt >= t.MaxRetries {
+			t.recordFailure(host)
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+				if retryAfter > t.MaxRetryAfter {
+					// Too long to wait inside a single fetch; let the scheduler retry later
+					t.recordFailure(host)
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+		}
+
+		if deadline, ok := req.Context().Deadline(); ok && t.now().Add(delay).After(deadline) {
+			t.recordFailure(host)
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), delay); err != nil {
+			t.recordFailure(host)
+			return nil, err
+		}
+
+		// Stop retrying if other requests opened the breaker meanwhile
+		if _, err := t.allow(host); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt
+func (t *Transport) backoff(attempt int) time.Duration {
+	max := t.BaseDelay << attempt
+	if max <= 0 || max > t.MaxDelay {
+		max = t.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+// allow reports whether a request to host may be sent, and whether it is the
+// half-open probe
+func (t *Transport) allow(host string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[host]
+	if b == nil || b.openUntil.IsZero() {
+		return false, nil
+	}
+
+	if t.now().Before(b.openUntil) {
+		return false, fmt.Errorf("%w for %s until %s", ErrCircuitOpen, host, b.openUntil.Format(time.RFC3339))
+	}
+
+	// Half-open: let a single probe through
+	if b.probing {
+		return false, fmt.Errorf("%w for %s (probe in flight)", ErrCircuitOpen, host)
+	}
+	b.probing = true
+	return true, nil
+}
+
+func (t *Transport) recordSuccess(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.breakers, host)
+}
+
+func (t *Transport) recordFailure(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breakers[host]
+	if b == nil {
+		b = &breaker{}
+		t.breakers[host] = b
+	}
+
+	b.failures++
+	if b.probing || b.failures >= t.BreakerThreshold {
+		b.openUntil = t.now().Add(t.BreakerCooldown)
+		b.probing = false
+	}
+}
+
+// cacheKeyFor is the URL without credential query parameters
+func cacheKeyFor(u *url.URL) string {
+	q := u.Query()
+	stripped := false
+	for _, name := range credentialParams {
+		if q.Has(name) {
+			q.Del(name)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return u.String()
+	}
+
+	keyURL := *u
+	keyURL.RawQuery = q.Encode()
+	return keyURL.String()
+}
+
+// cached returns the cached response for key, or nil, marking it recently used
+func (t *Transport) cached(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	el := t.cache[key]
+	if el == nil {
+		return nil
+	}
+	t.cacheOrder.MoveToFront(el)
+	return el.Value.(*cachedResponse)
+}
+
+// remember caches c, evicting the least recently used responses over MaxCacheBytes
+func (t *Transport) remember(c *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if old := t.cache[c.key]; old != nil {
+		t.cacheBytes -= len(old.Value.(*cachedResponse).body)
+		t.cacheOrder.Remove(old)
+		delete(t.cache, c.key)
+	}
+	if len(c.body) > t.MaxCacheBytes {
+		return
+	}
+
+	t.cache[c.key] = t.cacheOrder.PushFront(c)
+	t.cacheBytes += len(c.body)
+	for t.cacheBytes > t.MaxCacheBytes {
+		oldest := t.cacheOrder.Back()
+		evicted := oldest.Value.(*cachedResponse)
+		t.cacheOrder.Remove(oldest)
+		delete(t.cache, evicted.key)
+		t.cacheBytes -= len(evicted.body)
+	}
+}
+
+func (t *Transport) addValidators(req *http.Request, key string) *http.Request {
+	cached := t.cached(key)
+	if cached == nil || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+	return req
+}
+
+func (t *Transport) handleCache(key string, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode == http.StatusNotModified {
+		cached := t.cached(key)
+		if cached == nil {
+			// We sent no validators, so don't hand the caller an empty body as if it were data
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s: 304 Not Modified without a cached response", key)
+		}
+
+		resp.Body.Close()
+		header := cached.header.Clone()
+		header.Set(CacheStatusHeader, "revalidated")
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(cached.body)),
+			ContentLength: int64(len(cached.body)),
+			Request:       resp.Request,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if len(body) > maxCachedBody {
+		// Too large to cache: hand back what we read plus the rest of the stream
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	t.remember(&cachedResponse{
+		key:          key,
+		etag:         etag,
+		lastModified: lastModified,
+		header:       resp.Header.Clone(),
+		body:         body,
+	})
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// parseRetryAfter parses a Retry-After header in delta-seconds or HTTP-date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
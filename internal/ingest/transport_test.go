@@ -0,0 +1,285 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestTransport() (*Transport, *[]time.Duration) {
+	var slept []time.Duration
+	tr := NewTransport(http.DefaultTransport)
+	tr.BaseDelay = time.Millisecond
+	tr.MaxDelay = 10 * time.Millisecond
+	tr.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return tr, &slept
+}
+
+func TestTransportRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport()
+	client := &http.Client{Transport: tr}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected request to succeed after retries: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("Expected 200 after 3 calls, got %d after %d", resp.StatusCode, calls)
+	}
+}
+
+func TestTransportHonoursRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	tr, slept := newTestTransport()
+	resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected request to succeed: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*slept) != 1 || (*slept)[0] != 7*time.Second {
+		t.Errorf("Expected a single 7s wait, got %v", *slept)
+	}
+
+	// Retry-After beyond the cap is returned to the caller instead of waiting
+	calls = 0
+	tr.MaxRetryAfter = 5 * time.Second
+	resp, err = (&http.Client{Transport: tr}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected response, got error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls != 1 {
+		t.Errorf("Expected 429 without retrying, got %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestTransportCircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	tr, _ := newTestTransport()
+	tr.MaxRetries = 0
+	tr.BreakerThreshold = 2
+	tr.BreakerCooldown = time.Minute
+	tr.now = func() time.Time { return now }
+	client := &http.Client{Transport: tr}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Expected upstream response before breaker opens: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected circuit open error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected open breaker to skip upstream, got %d calls", calls)
+	}
+
+	// After the cooldown a single probe is let through
+	now = now.Add(2 * time.Minute)
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected half-open probe to reach upstream: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 3 {
+		t.Errorf("Expected probe call, got %d calls", calls)
+	}
+
+	// The failed probe re-opens the breaker
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected breaker to re-open after failed probe, got %v", err)
+	}
+}
+
+func TestTransportBreakerCountsRequestsNotAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport()
+	tr.BreakerThreshold = 2
+	client := &http.Client{Transport: tr}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected upstream response after retries: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 4 {
+		t.Errorf("Expected 1 attempt plus 3 retries, got %d calls", calls)
+	}
+
+	// The retries above count as a single failure, so the breaker is still closed
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected second request to reach upstream: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected breaker open after 2 failed requests, got %v", err)
+	}
+}
+
+func TestTransportUnexpectedNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport()
+	if _, err := (&http.Client{Transport: tr}).Get(server.URL); err == nil {
+		t.Error("Expected error for a 304 to an unconditional request")
+	}
+}
+
+func TestTransportConditionalRequests(t *testing.T) {
+	var calls, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport()
+	client := &http.Client{Transport: tr}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+			t.Errorf("Expected cached payload on request %d, got %d %q", i, resp.StatusCode, body)
+		}
+		if i == 1 && resp.Header.Get(CacheStatusHeader) != "revalidated" {
+			t.Errorf("Expected revalidated marker on second response")
+		}
+	}
+
+	if calls != 2 || notModified != 1 {
+		t.Errorf("Expected second request to be conditional, got %d calls, %d not modified", calls, notModified)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if d, ok := parseRetryAfter("30", now); !ok || d != 30*time.Second {
+		t.Errorf("Expected 30s, got %v %v", d, ok)
+	}
+	if d, ok := parseRetryAfter("Mon, 01 Jan 2024 12:01:00 GMT", now); !ok || d != time.Minute {
+		t.Errorf("Expected 1m, got %v %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After to be ignored")
+	}
+}
+
+func TestTransportCacheBounds(t *testing.T) {
+	var conditional int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			atomic.AddInt32(&conditional, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		w.Write([]byte("0123456789"))
+	}))
+	defer server.Close()
+
+	tr, _ := newTestTransport()
+	tr.MaxCacheBytes = 25
+	client := &http.Client{Transport: tr}
+	get := func(ctx context.Context, path string) {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request for %s failed: %v", path, err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Credentials don't split or leak into cache keys
+	get(context.Background(), "/a?api_key=one&series=X")
+	if _, ok := tr.cache[server.URL+"/a?series=X"]; !ok || len(tr.cache) != 1 {
+		t.Errorf("Expected one entry keyed without api_key, got %d entries", len(tr.cache))
+	}
+	get(context.Background(), "/a?api_key=two&series=X")
+	if conditional != 1 {
+		t.Errorf("Expected the second key to revalidate the same entry, got %d conditional requests", conditional)
+	}
+
+	// Two 10-byte bodies fit in 25 bytes; a third evicts the least recently used
+	get(context.Background(), "/b")
+	get(context.Background(), "/a?series=X")
+	get(context.Background(), "/c")
+	if len(tr.cache) != 2 || tr.cacheBytes != 20 {
+		t.Errorf("Expected 2 cached bodies totalling 20 bytes, got %d (%d bytes)", len(tr.cache), tr.cacheBytes)
+	}
+	if _, ok := tr.cache[server.URL+"/b"]; ok {
+		t.Error("Expected /b to be evicted as least recently used")
+	}
+
+	// One-off requests aren't cached
+	get(withoutCache(context.Background()), "/d")
+	if _, ok := tr.cache[server.URL+"/d"]; ok {
+		t.Error("Expected a request made withoutCache not to be cached")
+	}
+}
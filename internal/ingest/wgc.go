@@ -1,52 +1,455 @@
 package ingest
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
 )
 
+// WGCClient ingests World Gold Council data files.
+//
+// Goldhub publishes quarterly central bank demand (Gold Demand Trends tables) and
+// gold's share of official reserves (World Official Gold Holdings) as XLSX/CSV
+// downloads whose URLs change with each release. Each dataset is fetched from its
+// configured URL; when that fails or no longer parses, the newest matching file in
+// the manual directory is used instead, so an operator can drop in an export.
 type WGCClient struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	demandURL   string
+	reservesURL string
+	manualDir   string
 }
 
-type wgcResponse struct {
-	Data []struct {
-		Period string  `json:"period"`
-		Value  float64 `json:"value"`
-		Type   string  `json:"type"`
-	} `json:"data"`
+func NewWGCClient(demandURL, reservesURL, manualDir string) *WGCClient {
+	return &WGCClient{
+		httpClient:  newHTTPClient(30 * time.Second),
+		demandURL:   demandURL,
+		reservesURL: reservesURL,
+		manualDir:   manualDir,
+	}
 }
 
-func NewWGCClient() *WGCClient {
-	return &WGCClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// Fetch returns WGC_CB_PURCHASES and WGC_GOLD_RESERVE_SHARE history.
+// Either dataset may fail independently; points from the other are still returned.
+func (c *WGCClient) Fetch(ctx context.Context) ([]store.SeriesPoint, error) {
+	var points []store.SeriesPoint
+	var errs []error
+
+	demand, err := c.FetchCentralBankPurchases(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	points = append(points, demand...)
+
+	share, err := c.FetchGoldReserveShare(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	points = append(points, share...)
+
+	return points, errors.Join(errs...)
+}
+
+// FetchCentralBankPurchases returns quarterly central bank net gold purchases in tonnes
+func (c *WGCClient) FetchCentralBankPurchases(ctx context.Context) ([]store.SeriesPoint, error) {
+	points, err := c.fetchDataset(ctx, "central bank demand", c.demandURL, parseCBDemand)
+	if err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].Meta["series_id"] = "WGC_CB_PURCHASES"
+		points[i].Meta["unit"] = "tonnes"
+		points[i].Meta["frequency"] = "quarterly"
 	}
+	return points, nil
 }
 
-// FetchCentralBankPurchases fetches central bank gold purchase data from WGC
-// World Gold Council publishes quarterly central bank demand data
-func (c *WGCClient) FetchCentralBankPurchases() (store.SeriesPoint, error) {
-	// WGC API endpoint (if available) or scrape from their reports
-	// Real endpoint would be: https://www.gold.org/goldhub/data/...
-	// For MVP, we'll use mock data based on recent reports
+// FetchGoldReserveShare returns gold's share of world official reserves in percent
+func (c *WGCClient) FetchGoldReserveShare(ctx context.Context) ([]store.SeriesPoint, error) {
+	points, err := c.fetchDataset(ctx, "gold reserve share", c.reservesURL, parseGoldReserveShare)
+	if err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].Meta["series_id"] = "WGC_GOLD_RESERVE_SHARE"
+		points[i].Meta["unit"] = "percent_of_reserves"
+	}
+	return points, nil
+}
+
+type wgcParser func(tables []dataTable) ([]store.SeriesPoint, error)
+
+func (c *WGCClient) fetchDataset(ctx context.Context, name, url string, parse wgcParser) ([]store.SeriesPoint, error) {
+	var errs []error
+
+	if url != "" {
+		points, err := c.fetchURL(ctx, url, parse)
+		if err == nil {
+			return tagWGCOrigin(points, url), nil
+		}
+		util.ErrorLogger.Printf("WGC %s download failed, trying %s: %v", name, c.manualDir, err)
+		errs = append(errs, fmt.Errorf("download: %w", err))
+	}
+
+	points, file, err := c.fromManualDir(parse)
+	if err == nil {
+		util.InfoLogger.Printf("WGC %s loaded from %s", name, file)
+		return tagWGCOrigin(points, file), nil
+	}
+	errs = append(errs, err)
 
-	url := "https://www.gold.org/goldhub/data/gold-demand-statistics"
+	return nil, fmt.Errorf("WGC %s: %w", name, errors.Join(errs...))
+}
+
+func (c *WGCClient) fetchURL(ctx context.Context, url string, parse wgcParser) ([]store.SeriesPoint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to fetch WGC data: %w - API integration not yet implemented", err)
+		return nil, fmt.Errorf("failed to fetch WGC data: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return store.SeriesPoint{}, fmt.Errorf("WGC API returned status %d - API integration not yet implemented", resp.StatusCode)
+		return nil, fmt.Errorf("WGC returned status %d", resp.StatusCode)
 	}
 
-	// Real WGC API parsing would go here
-	return store.SeriesPoint{}, fmt.Errorf("WGC API parsing not yet implemented - will retry on next fetch")
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WGC response: %w", err)
+	}
+
+	tables, err := readDataFile(url, data)
+	if err != nil {
+		return nil, err
+	}
+	return parse(tables)
+}
+
+// fromManualDir tries every XLSX/CSV file in the manual directory, newest first
+func (c *WGCClient) fromManualDir(parse wgcParser) ([]store.SeriesPoint, string, error) {
+	if c.manualDir == "" {
+		return nil, "", fmt.Errorf("no manual data directory configured")
+	}
+
+	entries, err := os.ReadDir(c.manualDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("no manual data files: %w", err)
+	}
+
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	var files []candidate
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".xlsx" && ext != ".csv") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, candidate{filepath.Join(c.manualDir, e.Name()), info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	for _, f := range files {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			continue
+		}
+		tables, err := readDataFile(f.path, data)
+		if err != nil {
+			continue
+		}
+		if points, err := parse(tables); err == nil {
+			return points, f.path, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("no usable XLSX/CSV file in %s", c.manualDir)
+}
+
+func tagWGCOrigin(points []store.SeriesPoint, origin string) []store.SeriesPoint {
+	for i := range points {
+		points[i].Meta["source"] = "World Gold Council"
+		points[i].Meta["data_file"] = origin
+	}
+	return points
+}
+
+var (
+	wgcCentralBankLabel = regexp.MustCompile(`(?i)^central\s+banks?\b`)
+	wgcShareHeader      = regexp.MustCompile(`(?i)(share|%\s*of\s*(total\s+)?reserves|percent\s+of\s+reserves)`)
+	wgcWorldLabel       = regexp.MustCompile(`(?i)^(world|global|world\s+total)$`)
+)
+
+// parseCBDemand finds the central bank row of a quarterly demand table.
+// Both wide layouts (quarters across the header) and long layouts
+// (one row per quarter with a "Central banks" column) are supported.
+func parseCBDemand(tables []dataTable) ([]store.SeriesPoint, error) {
+	for _, table := range tables {
+		if points := wideSeries(table, wgcCentralBankLabel, parseQuarterLabel); len(points) > 0 {
+			return points, nil
+		}
+		if points := longSeries(table, wgcCentralBankLabel, parseQuarterLabel); len(points) > 0 {
+			return points, nil
+		}
+	}
+	return nil, fmt.Errorf("no central bank demand row found")
+}
+
+// parseGoldReserveShare finds gold's share of world reserves, either as a time series
+// or as the "World" row of a by-country holdings table dated by its title
+func parseGoldReserveShare(tables []dataTable) ([]store.SeriesPoint, error) {
+	for _, table := range tables {
+		if points := longSeries(table, wgcShareHeader, parsePeriodLabel); validShares(points) {
+			return withFrequency(points, "quarterly"), nil
+		}
+		if points := wideSeries(table, wgcWorldLabel, parsePeriodLabel); validShares(points) {
+			return withFrequency(points, "quarterly"), nil
+		}
+		if point, ok := holdingsSnapshot(table); ok && validShares([]store.SeriesPoint{point}) {
+			return withFrequency([]store.SeriesPoint{point}, "monthly"), nil
+		}
+	}
+	return nil, fmt.Errorf("no gold share of reserves found")
+}
+
+// validShares rejects empty results and anything that isn't a percentage,
+// such as tonnage columns picked up from a demand table
+func validShares(points []store.SeriesPoint) bool {
+	for _, p := range points {
+		if p.Value < 0 || p.Value > 100 {
+			return false
+		}
+	}
+	return len(points) > 0
+}
+
+func withFrequency(points []store.SeriesPoint, frequency string) []store.SeriesPoint {
+	for i := range points {
+		points[i].Meta["frequency"] = frequency
+	}
+	return points
+}
+
+// wideSeries reads a row labelled by label whose values sit under period headers
+func wideSeries(table dataTable, label *regexp.Regexp, parsePeriod func(string) (string, bool)) []store.SeriesPoint {
+	header := -1
+	var periods map[int]string
+
+	for i, row := range table.Rows {
+		cols := make(map[int]string)
+		for j, cell := range row {
+			if date, ok := parsePeriod(cell); ok {
+				cols[j] = date
+			}
+		}
+		if len(cols) >= 2 {
+			header, periods = i, cols
+			continue
+		}
+
+		if header < 0 || !label.MatchString(rowLabel(row)) {
+			continue
+		}
+
+		var points []store.SeriesPoint
+		for j, date := range periods {
+			if j >= len(row) {
+				continue
+			}
+			if v, ok := parseNumber(row[j]); ok {
+				points = append(points, store.SeriesPoint{Date: date, Value: v, Meta: map[string]string{}})
+			}
+		}
+		if len(points) > 0 {
+			sortPoints(points)
+			return points
+		}
+	}
+	return nil
+}
+
+// longSeries reads a column whose header matches label, one period per row
+func longSeries(table dataTable, label *regexp.Regexp, parsePeriod func(string) (string, bool)) []store.SeriesPoint {
+	valueCol := -1
+	var points []store.SeriesPoint
+
+	for _, row := range table.Rows {
+		if valueCol < 0 {
+			// Header rows name at least two columns; a lone title cell doesn't count
+			if nonEmpty(row) < 2 {
+				continue
+			}
+			for j, cell := range row {
+				if label.MatchString(cell) {
+					valueCol = j
+					break
+				}
+			}
+			continue
+		}
+
+		if valueCol >= len(row) {
+			continue
+		}
+		for j, cell := range row {
+			if j == valueCol {
+				continue
+			}
+			if date, ok := parsePeriod(cell); ok {
+				if v, ok := parseNumber(row[valueCol]); ok {
+					points = append(points, store.SeriesPoint{Date: date, Value: v, Meta: map[string]string{}})
+				}
+				break
+			}
+		}
+	}
+
+	sortPoints(points)
+	return points
+}
+
+// holdingsSnapshot reads the World row's share column from a by-country table,
+// dated by the first period mentioned in the table's title rows
+func holdingsSnapshot(table dataTable) (store.SeriesPoint, bool) {
+	date := ""
+	shareCol := -1
+
+	for _, row := range table.Rows {
+		if shareCol < 0 {
+			for j, cell := range row {
+				if date == "" {
+					if d, ok := findPeriod(cell); ok {
+						date = d
+					}
+				}
+				if wgcShareHeader.MatchString(cell) {
+					shareCol = j
+				}
+			}
+			continue
+		}
+
+		if date != "" && shareCol < len(row) && wgcWorldLabel.MatchString(rowLabel(row)) {
+			if v, ok := parseNumber(row[shareCol]); ok {
+				return store.SeriesPoint{Date: date, Value: v, Meta: map[string]string{}}, true
+			}
+		}
+	}
+	return store.SeriesPoint{}, false
+}
+
+func rowLabel(row []string) string {
+	for _, cell := range row {
+		if cell != "" {
+			return strings.TrimSpace(cell)
+		}
+	}
+	return ""
+}
+
+func nonEmpty(row []string) int {
+	n := 0
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.ReplaceAll(s, ",", ""), "%"))
+	if s == "" || s == "-" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+func sortPoints(points []store.SeriesPoint) {
+	sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+}
+
+var (
+	quarterFirst = regexp.MustCompile(`(?i)^Q([1-4])\s*['’\-/ ]?\s*(\d{2}|\d{4})$`)
+	yearFirst    = regexp.MustCompile(`(?i)^(\d{4})\s*[-/ ]?\s*Q([1-4])$`)
+	periodInText = regexp.MustCompile(`(?i)\b(Q[1-4]\s*['’\-/ ]?\s*(?:\d{4}|\d{2})|\d{4}\s*[-/ ]?\s*Q[1-4]|(?:January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Sept|Oct|Nov|Dec)[a-z]*[\s\-]+\d{4})\b`)
+)
+
+// parseQuarterLabel converts labels such as Q1'24, Q1 2024 or 2024Q1 to the quarter-end date
+func parseQuarterLabel(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+
+	var year, quarter int
+	if m := quarterFirst.FindStringSubmatch(s); m != nil {
+		quarter, _ = strconv.Atoi(m[1])
+		year, _ = strconv.Atoi(m[2])
+		if len(m[2]) == 2 {
+			year += 2000
+		}
+	} else if m := yearFirst.FindStringSubmatch(s); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		quarter, _ = strconv.Atoi(m[2])
+	} else {
+		return "", false
+	}
+
+	return time.Date(year, time.Month(quarter*3)+1, 0, 0, 0, 0, 0, time.UTC).Format("2006-01-02"), true
+}
+
+// parsePeriodLabel accepts quarter labels, ISO dates and month names, returning the period-end date
+func parsePeriodLabel(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if date, ok := parseQuarterLabel(s); ok {
+		return date, true
+	}
+
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Format("2006-01-02"), true
+	}
+
+	for _, layout := range []string{"2006-01", "January 2006", "Jan 2006", "Jan-2006", "January-2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.AddDate(0, 1, -1).Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// findPeriod finds the first quarter or month-year mentioned in free text
+func findPeriod(s string) (string, bool) {
+	m := periodInText.FindString(s)
+	if m == "" {
+		return "", false
+	}
+	if date, ok := parsePeriodLabel(m); ok {
+		return date, true
+	}
+	// "Sept 2024" and similar abbreviations
+	fields := strings.Fields(strings.ReplaceAll(m, "-", " "))
+	if len(fields) == 2 && len(fields[0]) >= 3 {
+		return parsePeriodLabel(fields[0][:3] + " " + fields[1])
+	}
+	return "", false
 }
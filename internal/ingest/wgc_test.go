@@ -0,0 +1,206 @@
+package ingest
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+func readFixtureTables(t *testing.T, name string) []dataTable {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	tables, err := readDataFile(name, data)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", name, err)
+	}
+	return tables
+}
+
+// buildXLSX zips a one-sheet workbook around the given <sheetData> rows
+func buildXLSX(t *testing.T, rows string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to build XLSX: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSXCellRefs(t *testing.T) {
+	// A ref without column letters uses the cell's position
+	tables, err := readXLSX(buildXLSX(t, `<row><c r="1"><v>a</v></c><c r="C1"><v>c</v></c></row>`))
+	if err != nil {
+		t.Fatalf("Failed to read XLSX: %v", err)
+	}
+	if row := tables[0].Rows[0]; len(row) != 3 || row[0] != "a" || row[2] != "c" {
+		t.Errorf("Expected [a, , c], got %q", row)
+	}
+
+	if _, err := readXLSX(buildXLSX(t, `<row><c r="ZZZZZZZZ1"><v>x</v></c></row>`)); err == nil {
+		t.Error("Expected error for a column beyond XFD")
+	}
+	if _, err := readXLSX(buildXLSX(t, `<row><c r="XFD1"><v>x</v></c></row>`)); err != nil {
+		t.Errorf("Expected the last column to be accepted: %v", err)
+	}
+}
+
+func TestParseCBDemandXLSX(t *testing.T) {
+	points, err := parseCBDemand(readFixtureTables(t, "wgc_gold_demand_trends.xlsx"))
+	if err != nil {
+		t.Fatalf("Failed to parse demand table: %v", err)
+	}
+
+	if len(points) != 7 {
+		t.Fatalf("Expected 7 quarters, got %d", len(points))
+	}
+
+	first, last := points[0], points[len(points)-1]
+	if first.Date != "2023-03-31" || first.Value != 286.3 {
+		t.Errorf("Expected Q1'23 = 286.3 on 2023-03-31, got %+v", first)
+	}
+	if last.Date != "2024-09-30" || last.Value != 186.2 {
+		t.Errorf("Expected Q3'24 = 186.2 on 2024-09-30, got %+v", last)
+	}
+}
+
+func TestParseGoldReserveShare(t *testing.T) {
+	// By-country holdings table dated by its title
+	points, err := parseGoldReserveShare(readFixtureTables(t, "wgc_world_official_gold_holdings.xlsx"))
+	if err != nil {
+		t.Fatalf("Failed to parse holdings: %v", err)
+	}
+	if len(points) != 1 || points[0].Date != "2024-09-30" || points[0].Value != 17.6 {
+		t.Errorf("Expected World share 17.6 on 2024-09-30, got %+v", points)
+	}
+
+	// Long-format CSV time series
+	points, err = parseGoldReserveShare(readFixtureTables(t, "wgc_gold_reserve_share.csv"))
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(points) != 4 || points[0].Date != "2023-12-31" || points[3].Value != 17.6 {
+		t.Errorf("Unexpected CSV points %+v", points)
+	}
+
+	// A demand table must not be mistaken for reserve shares
+	if _, err := parseGoldReserveShare(readFixtureTables(t, "wgc_gold_demand_trends.xlsx")); err == nil {
+		t.Error("Expected demand table to be rejected as a share source")
+	}
+}
+
+func TestParseQuarterLabel(t *testing.T) {
+	tests := map[string]string{
+		"Q1'24":   "2024-03-31",
+		"Q2 2023": "2023-06-30",
+		"2024Q3":  "2024-09-30",
+		"2023-Q4": "2023-12-31",
+		"Q4’22":   "2022-12-31",
+	}
+	for label, want := range tests {
+		if got, ok := parseQuarterLabel(label); !ok || got != want {
+			t.Errorf("parseQuarterLabel(%q) = %q, expected %q", label, got, want)
+		}
+	}
+
+	if _, ok := parseQuarterLabel("Tonnes"); ok {
+		t.Error("Expected non-quarter label to be rejected")
+	}
+}
+
+func TestWGCManualFallback(t *testing.T) {
+	util.InitLogger("info")
+
+	// The download now serves an HTML landing page instead of a data file
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>This file has moved</body></html>"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for _, name := range []string{"wgc_gold_demand_trends.xlsx", "wgc_world_official_gold_holdings.xlsx"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("Failed to read fixture: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatalf("Failed to write manual file: %v", err)
+		}
+	}
+
+	client := NewWGCClient(server.URL+"/demand.xlsx", server.URL+"/holdings.xlsx", dir)
+	client.httpClient = server.Client()
+
+	points, err := client.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Expected manual fallback to succeed: %v", err)
+	}
+
+	bySeries := make(map[string][]store.SeriesPoint)
+	for _, p := range points {
+		bySeries[p.Meta["series_id"]] = append(bySeries[p.Meta["series_id"]], p)
+	}
+
+	if len(bySeries["WGC_CB_PURCHASES"]) != 7 {
+		t.Errorf("Expected 7 CB purchase points, got %d", len(bySeries["WGC_CB_PURCHASES"]))
+	}
+	share := bySeries["WGC_GOLD_RESERVE_SHARE"]
+	if len(share) != 1 || share[0].Value != 17.6 {
+		t.Errorf("Expected gold share 17.6 from manual file, got %+v", share)
+	}
+	if share[0].Meta["data_file"] != filepath.Join(dir, "wgc_world_official_gold_holdings.xlsx") {
+		t.Errorf("Expected data_file to record manual file, got %s", share[0].Meta["data_file"])
+	}
+}
+
+func TestWGCDownload(t *testing.T) {
+	util.InitLogger("info")
+
+	data, err := os.ReadFile("testdata/wgc_gold_reserve_share.csv")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	client := NewWGCClient("", server.URL+"/share.csv", "")
+	client.httpClient = server.Client()
+
+	points, err := client.FetchGoldReserveShare(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch share: %v", err)
+	}
+	if len(points) != 4 || points[0].Meta["series_id"] != "WGC_GOLD_RESERVE_SHARE" {
+		t.Errorf("Unexpected points %+v", points)
+	}
+
+	if _, err := client.FetchCentralBankPurchases(context.Background()); err == nil {
+		t.Error("Expected error with no demand URL and no manual directory")
+	}
+}
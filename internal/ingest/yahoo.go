@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,25 +21,91 @@ type yahooResponse struct {
 				RegularMarketPrice float64 `json:"regularMarketPrice"`
 				RegularMarketTime  int64   `json:"regularMarketTime"`
 			} `json:"meta"`
+			Timestamp  []int64 `json:"timestamp"`
+			Indicators struct {
+				Quote []struct {
+					Close []*float64 `json:"close"`
+				} `json:"quote"`
+			} `json:"indicators"`
 		} `json:"result"`
 	} `json:"chart"`
 }
 
 func NewYahooFinanceClient() *YahooFinanceClient {
 	return &YahooFinanceClient{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		httpClient: newHTTPClient(10 * time.Second),
 	}
 }
 
 // FetchDXY fetches the real-time DXY (US Dollar Index) from Yahoo Finance
-func (c *YahooFinanceClient) FetchDXY() (store.SeriesPoint, error) {
-	url := "https://query1.finance.yahoo.com/v8/finance/chart/DX-Y.NYB?interval=1d&range=1d"
+func (c *YahooFinanceClient) FetchDXY(ctx context.Context) (store.SeriesPoint, error) {
+	yahooResp, err := c.fetchChart(ctx, "interval=1d&range=1d")
+	if err != nil {
+		return store.SeriesPoint{}, err
+	}
+
+	result := yahooResp.Chart.Result[0]
+	timestamp := time.Unix(result.Meta.RegularMarketTime, 0)
+
+	return store.SeriesPoint{
+		Date:  timestamp.Format("2006-01-02"),
+		Value: result.Meta.RegularMarketPrice,
+		Meta: map[string]string{
+			"series_id": "DXY_REALTIME",
+			"source":    "yahoo_finance",
+			"timestamp": timestamp.Format(time.RFC3339),
+		},
+		SourceUpdatedAt: &timestamp,
+	}, nil
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// FetchDXYRange fetches daily DXY closes between two dates (YYYY-MM-DD, inclusive)
+func (c *YahooFinanceClient) FetchDXYRange(ctx context.Context, start, end string) ([]store.SeriesPoint, error) {
+	startTime, err := time.Parse("2006-01-02", start)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	endTime, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	query := fmt.Sprintf("interval=1d&period1=%d&period2=%d", startTime.Unix(), endTime.AddDate(0, 0, 1).Unix())
+	yahooResp, err := c.fetchChart(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := yahooResp.Chart.Result[0]
+	if len(result.Indicators.Quote) == 0 {
+		return nil, nil
+	}
+	closes := result.Indicators.Quote[0].Close
+
+	var points []store.SeriesPoint
+	for i, ts := range result.Timestamp {
+		if i >= len(closes) || closes[i] == nil {
+			continue
+		}
+		points = append(points, store.SeriesPoint{
+			Date:  time.Unix(ts, 0).UTC().Format("2006-01-02"),
+			Value: *closes[i],
+			Meta: map[string]string{
+				"series_id": "DXY_REALTIME",
+				"source":    "yahoo_finance",
+			},
+		})
+	}
+
+	return points, nil
+}
+
+func (c *YahooFinanceClient) fetchChart(ctx context.Context, query string) (*yahooResponse, error) {
+	url := "https://query1.finance.yahoo.com/v8/finance/chart/DX-Y.NYB?" + query
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Add headers to avoid rate limiting
@@ -47,37 +114,26 @@ func (c *YahooFinanceClient) FetchDXY() (store.SeriesPoint, error) {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to fetch Yahoo Finance data: %w", err)
+		return nil, fmt.Errorf("failed to fetch Yahoo Finance data: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 429 {
-		return store.SeriesPoint{}, fmt.Errorf("Yahoo Finance API rate limited (429) - will retry on next fetch")
+		return nil, fmt.Errorf("Yahoo Finance API rate limited (429) after retries")
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return store.SeriesPoint{}, fmt.Errorf("Yahoo Finance API returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("Yahoo Finance API returned status %d", resp.StatusCode)
 	}
 
 	var yahooResp yahooResponse
 	if err := json.NewDecoder(resp.Body).Decode(&yahooResp); err != nil {
-		return store.SeriesPoint{}, fmt.Errorf("failed to decode Yahoo response: %w", err)
+		return nil, fmt.Errorf("failed to decode Yahoo response: %w", err)
 	}
 
 	if len(yahooResp.Chart.Result) == 0 {
-		return store.SeriesPoint{}, fmt.Errorf("no data returned from Yahoo Finance")
+		return nil, fmt.Errorf("no data returned from Yahoo Finance")
 	}
 
-	result := yahooResp.Chart.Result[0]
-	timestamp := time.Unix(result.Meta.RegularMarketTime, 0)
-
-	return store.SeriesPoint{
-		Date:  timestamp.Format("2006-01-02"),
-		Value: result.Meta.RegularMarketPrice,
-		Meta: map[string]string{
-			"series_id": "DXY",
-			"source":    "yahoo_finance",
-			"timestamp": timestamp.Format(time.RFC3339),
-		},
-	}, nil
+	return &yahooResp, nil
 }
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"sync"
+	"time"
+
+	"reserve-watch/internal/util"
+)
+
+// SeriesUpdated is published after SavePoints inserts observations or changes their values
+type SeriesUpdated struct {
+	SeriesID string
+	Points   []SeriesPoint // new or revised observations, in the order saved
+	Previous *SeriesPoint  // latest observation before the save, nil for a new series
+	Latest   SeriesPoint   // latest observation after the save
+	At       time.Time
+}
+
+// LatestChanged reports whether the update moved the series' latest observation,
+// as opposed to only revising history
+func (e SeriesUpdated) LatestChanged() bool {
+	return e.Previous == nil || e.Previous.Date != e.Latest.Date || e.Previous.Value != e.Latest.Value
+}
+
+// Bus is an in-process event publisher. Each subscriber runs in its own goroutine
+// and receives events in publish order. Events that queue up while a subscriber is
+// busy are handed over together, so slow work like recomputing an index runs once
+// per burst rather than once per series.
+type Bus[T any] struct {
+	mu      sync.Mutex
+	idle    *sync.Cond
+	subs    map[int]*subscription[T]
+	nextID  int
+	pending int // events queued or being handled across all subscribers
+}
+
+type subscription[T any] struct {
+	name  string
+	fn    func([]T)
+	queue []T
+	wake  chan struct{}
+	done  chan struct{}
+}
+
+func NewBus[T any]() *Bus[T] {
+	b := &Bus[T]{subs: make(map[int]*subscription[T])}
+	b.idle = sync.NewCond(&b.mu)
+	return b
+}
+
+// Subscribe registers fn for every later event and returns a function that
+// unsubscribes it. name identifies the subscriber in logs.
+func (b *Bus[T]) Subscribe(name string, fn func([]T)) func() {
+	sub := &subscription[T]{
+		name: name,
+		fn:   fn,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	b.mu.Unlock()
+
+	go b.run(sub)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+// Publish queues an event for every subscriber without waiting for them
+func (b *Bus[T]) Publish(e T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, sub := range b.subs {
+		sub.queue = append(sub.queue, e)
+		b.pending++
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Wait blocks until every published event has been handled
+func (b *Bus[T]) Wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for b.pending > 0 {
+		b.idle.Wait()
+	}
+}
+
+func (b *Bus[T]) run(sub *subscription[T]) {
+	for {
+		select {
+		case <-sub.done:
+			b.mu.Lock()
+			b.finish(len(sub.queue))
+			sub.queue = nil
+			b.mu.Unlock()
+			return
+		case <-sub.wake:
+		}
+
+		b.mu.Lock()
+		batch := sub.queue
+		sub.queue = nil
+		b.mu.Unlock()
+
+		if len(batch) > 0 {
+			b.deliver(sub, batch)
+		}
+
+		b.mu.Lock()
+		b.finish(len(batch))
+		b.mu.Unlock()
+	}
+}
+
+// deliver calls the subscriber, keeping it alive if it panics
+func (b *Bus[T]) deliver(sub *subscription[T], batch []T) {
+	defer func() {
+		if r := recover(); r != nil {
+			util.ErrorLogger.Printf("Event subscriber %s panicked: %v", sub.name, r)
+		}
+	}()
+	sub.fn(batch)
+}
+
+// finish marks n events handled; the caller holds b.mu
+func (b *Bus[T]) finish(n int) {
+	b.pending -= n
+	if b.pending == 0 {
+		b.idle.Broadcast()
+	}
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func TestSavePointsPublishesChanges(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	var mu sync.Mutex
+	var events []SeriesUpdated
+	store.Events().Subscribe("test", func(batch []SeriesUpdated) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, batch...)
+	})
+
+	save := func(points ...SeriesPoint) []SeriesUpdated {
+		t.Helper()
+		if err := store.SavePoints("VIXCLS", points, time.Now()); err != nil {
+			t.Fatalf("Failed to save points: %v", err)
+		}
+		store.Events().Wait()
+		mu.Lock()
+		defer mu.Unlock()
+		got := events
+		events = nil
+		return got
+	}
+
+	got := save(SeriesPoint{Date: "2024-01-02", Value: 13}, SeriesPoint{Date: "2024-01-03", Value: 14})
+	if len(got) != 1 || len(got[0].Points) != 2 || got[0].Previous != nil || got[0].Latest.Date != "2024-01-03" || !got[0].LatestChanged() {
+		t.Fatalf("Expected one event for a new series, got %+v", got)
+	}
+
+	if got := save(SeriesPoint{Date: "2024-01-03", Value: 14}); len(got) != 0 {
+		t.Errorf("Expected no event for an unchanged point, got %+v", got)
+	}
+
+	got = save(SeriesPoint{Date: "2024-01-02", Value: 12.5}, SeriesPoint{Date: "2024-01-03", Value: 14})
+	if len(got) != 1 || len(got[0].Points) != 1 || got[0].Points[0].Date != "2024-01-02" {
+		t.Fatalf("Expected one revised point, got %+v", got)
+	}
+	if got[0].LatestChanged() {
+		t.Error("Expected a revision of history not to change the latest observation")
+	}
+}
+
+func TestBusBatchesWhileBusy(t *testing.T) {
+	util.InitLogger("info")
+	bus := NewBus[SeriesUpdated]()
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var batches [][]SeriesUpdated
+	bus.Subscribe("slow", func(batch []SeriesUpdated) {
+		mu.Lock()
+		batches = append(batches, batch)
+		first := len(batches) == 1
+		mu.Unlock()
+		if first {
+			<-release
+		}
+	})
+	unsubscribe := bus.Subscribe("panics", func([]SeriesUpdated) { panic("boom") })
+
+	bus.Publish(SeriesUpdated{SeriesID: "A"})
+	for {
+		mu.Lock()
+		started := len(batches) == 1
+		mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	bus.Publish(SeriesUpdated{SeriesID: "B"})
+	bus.Publish(SeriesUpdated{SeriesID: "C"})
+	close(release)
+	bus.Wait()
+	unsubscribe()
+
+	if len(batches) != 2 || len(batches[1]) != 2 || batches[1][0].SeriesID != "B" || batches[1][1].SeriesID != "C" {
+		t.Errorf("Expected events queued while busy to arrive together in order, got %+v", batches)
+	}
+}
@@ -0,0 +1,294 @@
+package store
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migration is one numbered schema change, read from NNN_name.up.sql and
+// NNN_name.down.sql
+type Migration struct {
+	Version  int
+	Name     string // file name without the direction, e.g. "008_alert_state"
+	Up       string
+	Down     string
+	Checksum string // SHA-256 of Up
+}
+
+// MigrationStatus is a migration and whether it has been applied
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	Modified  bool // the up migration changed after it was applied
+}
+
+// legacyVersion is the last migration from before schema_migrations existed. Those
+// were re-run on every start, so a database without schema_migrations may have any
+// prefix of them applied already.
+const legacyVersion = 10
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadMigrations reads the migrations in fsys, oldest first. Every version needs
+// both an up and a down file.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	hasDown := make(map[int]bool)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".sql") {
+			continue
+		}
+		m := migrationFile.FindStringSubmatch(file.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s is not named NNN_name.up.sql or NNN_name.down.sql", file.Name())
+		}
+
+		version, _ := strconv.Atoi(m[1])
+		name := m[1] + "_" + m[2]
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", migration.Name, name, version)
+		}
+
+		data, err := fs.ReadFile(fsys, file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file.Name(), err)
+		}
+		if m[3] == "up" {
+			sum := sha256.Sum256(data)
+			migration.Up, migration.Checksum = string(data), hex.EncodeToString(sum[:])
+		} else {
+			migration.Down, hasDown[version] = string(data), true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" || !hasDown[m.Version] {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrate applies every pending migration in fsys in order, each in its own
+// transaction, and returns the ones applied. It refuses to run if an applied
+// migration was edited or the database has migrations this build doesn't know.
+func (s *Store) Migrate(fsys fs.FS) ([]Migration, error) {
+	statuses, err := s.MigrationStatus(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, st := range statuses {
+		if st.Modified {
+			return nil, fmt.Errorf("migration %s was modified after it was applied; add a new migration instead", st.Name)
+		}
+	}
+
+	adopting, err := s.isUnversioned()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, st := range statuses {
+		if st.AppliedAt != nil {
+			continue
+		}
+		if err := s.applyMigration(st.Migration, adopting && st.Version <= legacyVersion); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %s: %w", st.Name, err)
+		}
+		applied = append(applied, st.Migration)
+	}
+
+	return applied, nil
+}
+
+// MigrateDown reverts the newest applied migrations, up to steps of them, and
+// returns the ones reverted
+func (s *Store) MigrateDown(fsys fs.FS, steps int) ([]Migration, error) {
+	statuses, err := s.MigrationStatus(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted []Migration
+	for i := len(statuses) - 1; i >= 0 && len(reverted) < steps; i-- {
+		st := statuses[i]
+		if st.AppliedAt == nil {
+			continue
+		}
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return reverted, err
+		}
+		if _, err := tx.Exec(st.Down); err != nil {
+			tx.Rollback()
+			return reverted, fmt.Errorf("failed to revert migration %s: %w", st.Name, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = ?`, st.Version); err != nil {
+			tx.Rollback()
+			return reverted, err
+		}
+		if err := tx.Commit(); err != nil {
+			return reverted, err
+		}
+
+		reverted = append(reverted, st.Migration)
+	}
+
+	return reverted, nil
+}
+
+// MigrationStatus lists the migrations in fsys and whether each is applied
+func (s *Store) MigrationStatus(fsys fs.FS) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(`
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at TEXT NOT NULL DEFAULT (datetime('now'))
+)`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	rows, err := s.db.Query(`SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type applied struct {
+		name, checksum string
+		at             time.Time
+	}
+	done := make(map[int]applied)
+	for rows.Next() {
+		var version int
+		var a applied
+		var appliedAt string
+		if err := rows.Scan(&version, &a.name, &a.checksum, &appliedAt); err != nil {
+			return nil, err
+		}
+		a.at, _ = time.Parse("2006-01-02 15:04:05", appliedAt)
+		done[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		statuses[i] = MigrationStatus{Migration: m}
+		if a, ok := done[m.Version]; ok {
+			at := a.at
+			statuses[i].AppliedAt = &at
+			statuses[i].Modified = a.checksum != m.Checksum
+			delete(done, m.Version)
+		}
+	}
+	for version, a := range done {
+		return nil, fmt.Errorf("database has migration %s (version %d) that this build doesn't know about", a.name, version)
+	}
+
+	return statuses, nil
+}
+
+// isUnversioned reports whether the database predates schema_migrations: it has
+// tables but no migration has been recorded
+func (s *Store) isUnversioned() (bool, error) {
+	var recorded, tables int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&recorded); err != nil {
+		return false, err
+	}
+	if recorded > 0 {
+		return false, nil
+	}
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'series_points'`).Scan(&tables)
+	return tables > 0, err
+}
+
+// applyMigration runs an up migration and records it in one transaction. adopt
+// re-runs a legacy migration on a database that may already have it; those only
+// use IF NOT EXISTS, except for ADD COLUMN, which SQLite can't make conditional.
+func (s *Store) applyMigration(m Migration, adopt bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if adopt {
+		if err := execAdopting(tx, m.Up); err != nil {
+			return err
+		}
+	} else if _, err := tx.Exec(m.Up); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+INSERT INTO schema_migrations (version, name, checksum)
+VALUES (?, ?, ?)
+`, m.Version, m.Name, m.Checksum); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execAdopting runs a legacy migration, re-running it one statement at a time and
+// skipping columns that already exist if it fails on one. Statements are split on
+// ';', which the legacy migrations with ADD COLUMN never use in comments or strings.
+func execAdopting(tx *sql.Tx, migration string) error {
+	if _, err := tx.Exec(`SAVEPOINT adopt`); err != nil {
+		return err
+	}
+	_, err := tx.Exec(migration)
+	if err == nil || !isDuplicateColumn(err) {
+		return err
+	}
+	if _, err := tx.Exec(`ROLLBACK TO adopt`); err != nil {
+		return err
+	}
+
+	for _, stmt := range strings.Split(migration, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+		if _, err := tx.Exec(stmt); err != nil && !isDuplicateColumn(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func isDuplicateColumn(err error) bool {
+	return strings.Contains(err.Error(), "duplicate column name")
+}
@@ -0,0 +1,166 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"reserve-watch/migrations"
+)
+
+func TestMigrate(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	fsys := fstest.MapFS{
+		"001_test.up.sql":    {Data: []byte(`CREATE TABLE test (id INTEGER PRIMARY KEY);`)},
+		"001_test.down.sql":  {Data: []byte(`DROP TABLE test;`)},
+		"002_extra.up.sql":   {Data: []byte(`ALTER TABLE test ADD COLUMN name TEXT;`)},
+		"002_extra.down.sql": {Data: []byte(`ALTER TABLE test DROP COLUMN name;`)},
+	}
+
+	applied, err := store.Migrate(fsys)
+	if err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Name != "001_test" || applied[1].Name != "002_extra" {
+		t.Fatalf("Expected both migrations applied in order, got %+v", applied)
+	}
+	if _, err := store.db.Exec(`INSERT INTO test (id, name) VALUES (1, 'a')`); err != nil {
+		t.Errorf("Expected test table with name column: %v", err)
+	}
+
+	if applied, err := store.Migrate(fsys); err != nil || len(applied) != 0 {
+		t.Errorf("Expected second run to apply nothing, got %+v, %v", applied, err)
+	}
+
+	reverted, err := store.MigrateDown(fsys, 1)
+	if err != nil {
+		t.Fatalf("Failed to revert migration: %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Name != "002_extra" {
+		t.Fatalf("Expected newest migration reverted, got %+v", reverted)
+	}
+	statuses, err := store.MigrationStatus(fsys)
+	if err != nil {
+		t.Fatalf("Failed to get migration status: %v", err)
+	}
+	if statuses[0].AppliedAt == nil || statuses[1].AppliedAt != nil {
+		t.Errorf("Expected only 001 applied after reverting, got %+v", statuses)
+	}
+
+	fsys["001_test.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE test (id INTEGER PRIMARY KEY, extra TEXT);`)}
+	if _, err := store.Migrate(fsys); err == nil {
+		t.Error("Expected error for a migration modified after it was applied")
+	}
+
+	delete(fsys, "001_test.down.sql")
+	if _, err := LoadMigrations(fsys); err == nil {
+		t.Error("Expected error for a migration without a down file")
+	}
+}
+
+func TestMigrateAdoptsLegacyDatabase(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	// Databases from before schema_migrations ran every file on each start, so
+	// the ALTER TABLE migrations may already be in place
+	for _, m := range all {
+		if m.Version > 8 {
+			break
+		}
+		if _, err := store.db.Exec(m.Up); err != nil {
+			t.Fatalf("Failed to apply %s: %v", m.Name, err)
+		}
+	}
+
+	applied, err := store.Migrate(migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to adopt legacy database: %v", err)
+	}
+	if len(applied) != len(all) {
+		t.Errorf("Expected every migration recorded, got %d of %d", len(applied), len(all))
+	}
+
+	var state string
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('alerts') WHERE name = 'state'`).Scan(&state); err != nil || state != "1" {
+		t.Errorf("Expected alerts.state to exist once, got %q, %v", state, err)
+	}
+
+	reverted, err := store.MigrateDown(migrations.FS, len(all))
+	if err != nil {
+		t.Fatalf("Failed to revert every migration: %v", err)
+	}
+	if len(reverted) != len(all) {
+		t.Errorf("Expected every migration reverted, got %d", len(reverted))
+	}
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Errorf("Expected migrations to re-apply after reverting: %v", err)
+	}
+}
+
+func TestCOFERQuarterDateMigration(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// Stop just before 006 so the old period-dated points can be stored and then
+	// normalized when it is applied
+	before := fstest.MapFS{}
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	for _, m := range all {
+		if m.Version < 6 {
+			before[m.Name+".up.sql"] = &fstest.MapFile{Data: []byte(m.Up)}
+			before[m.Name+".down.sql"] = &fstest.MapFile{Data: []byte(m.Down)}
+		}
+	}
+	if _, err := store.Migrate(before); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	for _, p := range []SeriesPoint{
+		{Date: "2024-Q1", Value: 2.1},
+		{Date: "2024-Q2", Value: 2.2},
+		{Date: "2024-06-30", Value: 2.15},
+	} {
+		if _, err := store.db.Exec(`INSERT INTO series_points (series_name, date, value) VALUES ('COFER_CNY', ?, ?)`, p.Date, p.Value); err != nil {
+			t.Fatalf("Failed to insert point: %v", err)
+		}
+	}
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run remaining migrations: %v", err)
+	}
+
+	points, err := store.GetRecentPoints("COFER_CNY", 10)
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 points after normalization, got %+v", points)
+	}
+	if points[0].Date != "2024-06-30" || points[0].Value != 2.15 {
+		t.Errorf("Expected existing quarter-end point to win, got %+v", points[0])
+	}
+	if points[1].Date != "2024-03-31" || points[1].Value != 2.1 {
+		t.Errorf("Expected 2024-Q1 to become 2024-03-31, got %+v", points[1])
+	}
+}
@@ -1,20 +1,40 @@
 package store
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type SeriesPoint struct {
-	Date  string
-	Value float64
-	Meta  map[string]string
+	Date            string
+	Value           float64
+	Meta            map[string]string
+	SourceUpdatedAt *time.Time // when the source published or last revised it, nil if it doesn't say
+	IngestedAt      time.Time  // when it was last fetched; set by the store
+}
+
+// Series is a catalog entry describing what a series ID means. FirstDate, LastDate
+// and Observations are computed from series_points when read.
+type Series struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Label        string `json:"label"` // short name for tiles, posts and exports
+	Unit         string `json:"unit"`
+	Frequency    string `json:"frequency"`
+	Source       string `json:"source"`   // ingest source, e.g. "fred"
+	Provider     string `json:"provider"` // publisher credited in attribution
+	License      string `json:"license"`
+	LicenseURL   string `json:"license_url"`
+	Link         string `json:"link"`
+	FirstDate    string `json:"first_date"` // empty before the first observation
+	LastDate     string `json:"last_date"`
+	Observations int    `json:"observations"`
 }
 
 type Post struct {
@@ -29,16 +49,42 @@ type Post struct {
 }
 
 type Alert struct {
-	ID              int64
-	UserEmail       string
-	Name            string
-	SeriesID        string
-	Condition       string // 'above' or 'below'
-	Threshold       float64
-	WebhookURL      string
-	IsActive        bool
-	LastTriggeredAt *time.Time
-	CreatedAt       time.Time
+	ID               int64
+	UserEmail        string
+	Name             string
+	SeriesID         string
+	Condition        string // 'above', 'below' or an alert expression
+	Threshold        float64
+	WebhookURL       string
+	WebhookSecret    string // signs webhook payloads
+	Channels         []AlertChannel
+	IsActive         bool
+	RearmBand        float64 // distance back past the threshold before the alert can fire again
+	Confirmations    int     // consecutive observations beyond the threshold needed to fire
+	CooldownMinutes  int     // minimum time between triggers
+	State            string  // AlertArmed, AlertPending or AlertTriggered
+	BreachCount      int     // consecutive observations beyond the threshold so far
+	LastObservedDate string  // newest observation the state reflects (per-series dates joined by "|" for expressions)
+	StateChangedAt   *time.Time
+	LastTriggeredAt  *time.Time
+	CreatedAt        time.Time
+}
+
+// Alert states
+const (
+	AlertArmed     = "armed"     // on the safe side of the threshold
+	AlertPending   = "pending"   // beyond the threshold, awaiting confirmations or the cooldown
+	AlertTriggered = "triggered" // fired; waiting for the value to re-arm
+)
+
+// DefaultAlertCooldownMinutes applies when an alert doesn't set its own cooldown
+const DefaultAlertCooldownMinutes = 60
+
+// AlertChannel is one place an alert is delivered: an email address, phone number
+// or webhook URL depending on Type
+type AlertChannel struct {
+	Type   string `json:"type"` // 'email', 'webhook', 'slack', 'discord', 'teams' or 'sms'
+	Target string `json:"target"`
 }
 
 type AlertHistory struct {
@@ -48,7 +94,54 @@ type AlertHistory struct {
 	Value         float64
 	Threshold     float64
 	TriggeredAt   time.Time
-	WebhookStatus string
+	WebhookStatus string // summary of Deliveries: 'success', 'failed' or 'skipped'
+	Deliveries    []ChannelDelivery
+}
+
+// ChannelDelivery is the outcome of delivering a trigger to one channel
+type ChannelDelivery struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target"`
+	Status  string `json:"status"` // 'success', 'failed', 'skipped', or 'pending'/'dead' for webhook deliveries
+	Error   string `json:"error,omitempty"`
+
+	DeliveryID int64 `json:"delivery_id,omitempty"` // webhook_deliveries row
+}
+
+// WebhookDelivery is one webhook POST in the delivery log/outbox
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	AlertID        int64      `json:"alert_id"`
+	HistoryID      int64      `json:"history_id"`
+	ReplayOf       int64      `json:"replay_of,omitempty"`
+	Target         string     `json:"target"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Secret string `json:"-"` // the alert's signing secret
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending = "pending" // awaiting a retry
+	DeliverySending = "sending" // an attempt is in flight; claimable again if it hasn't finished by next_attempt_at
+	DeliverySuccess = "success"
+	DeliveryDead    = "dead" // out of attempts
+)
+
+// WebhookDeliveryFilter narrows ListWebhookDeliveries
+type WebhookDeliveryFilter struct {
+	UserEmail string // alerts owned by this user
+	AlertID   int64
+	Status    string
+	Limit     int
 }
 
 type Lead struct {
@@ -86,8 +179,66 @@ type SocialPost struct {
 	EngagementCount int
 }
 
+// BackfillProgress records the date range already covered by a historical backfill
+type BackfillProgress struct {
+	SeriesID       string
+	StartDate      string
+	CoveredThrough string
+	PointsSaved    int
+	CompletedAt    *time.Time
+	UpdatedAt      time.Time
+}
+
+// SignalTransition records a signal moving from one status to another
+type SignalTransition struct {
+	ID         int64     `json:"id"`
+	SignalKey  string    `json:"signal_key"`
+	SeriesID   string    `json:"series_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Value      float64   `json:"value"`
+	AsOf       string    `json:"as_of"`
+	Rule       string    `json:"rule"`
+	Why        string    `json:"why"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SignalTransitionFilter narrows GetSignalTransitions; zero values match everything
+type SignalTransitionFilter struct {
+	SignalKey string
+	Since     time.Time
+	Limit     int
+}
+
+// FeedEvent is a signal transition as listed on the public alerts feed. User alert
+// triggers are private to their owner and never appear there.
+type FeedEvent struct {
+	ID         string // "signal-42"
+	SeriesID   string
+	SignalKey  string
+	FromStatus string
+	Status     string // new signal status
+	Value      float64
+	AsOf       string
+	Rule       string
+	Why        string
+	OccurredAt time.Time
+}
+
+// FeedFilter narrows ListFeedEvents; zero values match everything
+type FeedFilter struct {
+	SeriesID string
+	Status   string
+	Since    time.Time
+	Limit    int
+	Offset   int
+}
+
 type Store struct {
-	db *sql.DB
+	db      *sql.DB
+	events  *Bus[SeriesUpdated]
+	signals *Bus[SignalTransition]
+	asOf    time.Time // reads see observations as recorded at this time; zero for current values
 }
 
 func New(dsn string) (*Store, error) {
@@ -100,120 +251,392 @@ func New(dsn string) (*Store, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Store{db: db}, nil
+	return &Store{db: db, events: NewBus[SeriesUpdated](), signals: NewBus[SignalTransition]()}, nil
 }
 
-func (s *Store) Migrate(migrationsDir string) error {
-	files, err := os.ReadDir(migrationsDir)
-	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
-	}
+// Events is the bus SavePoints publishes series updates to
+func (s *Store) Events() *Bus[SeriesUpdated] {
+	return s.events
+}
 
-	for _, file := range files {
-		if filepath.Ext(file.Name()) != ".sql" {
-			continue
-		}
+// SignalEvents is the bus RecordSignalState publishes status changes to
+func (s *Store) SignalEvents() *Bus[SignalTransition] {
+	return s.signals
+}
 
-		data, err := os.ReadFile(filepath.Join(migrationsDir, file.Name()))
-		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %w", file.Name(), err)
-		}
+// AsOf returns a read-only view of the store in which GetLatestPoint, GetRecentPoints
+// and GetPointsBetween return observations as they had been recorded at t: later
+// observations are hidden and revised ones show their earlier value. Anything built
+// on those reads, like indices and signals, can be reproduced by passing the view.
+func (s *Store) AsOf(t time.Time) *Store {
+	view := *s
+	view.asOf = t.UTC()
+	return &view
+}
 
-		if _, err := s.db.Exec(string(data)); err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", file.Name(), err)
-		}
-	}
+// vintageTime is the recorded_at format of series_point_vintages
+const vintageTime = "2006-01-02 15:04:05.000"
 
-	return nil
+// pointsTable is the table expression point reads select from: series_points, or
+// the latest vintage of each observation recorded by s.asOf
+func (s *Store) pointsTable() (string, []interface{}) {
+	if s.asOf.IsZero() {
+		return "series_points", nil
+	}
+	asOf := s.asOf.Format(vintageTime)
+	return `(
+SELECT v.series_name, v.date, v.value, v.meta, v.source_updated_at, v.recorded_at AS ingested_at
+FROM series_point_vintages v
+WHERE v.recorded_at <= ? AND v.deleted = 0 AND v.id = (
+    SELECT MAX(n.id) FROM series_point_vintages n
+    WHERE n.series_name = v.series_name AND n.date = v.date AND n.recorded_at <= ?
+)
+)`, []interface{}{asOf, asOf}
 }
 
+// SavePoints upserts observations and publishes a SeriesUpdated event if any of them
+// were new or changed value. sourceUpdatedAt applies to points that don't carry
+// their own SourceUpdatedAt; pass the zero time when the source doesn't report one.
 func (s *Store) SavePoints(seriesName string, points []SeriesPoint, sourceUpdatedAt time.Time) error {
+	if !s.asOf.IsZero() {
+		return fmt.Errorf("cannot save points through a point-in-time view")
+	}
+
+	previous, err := s.GetLatestPoint(seriesName)
+	if err != nil {
+		return err
+	}
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	existing, err := tx.Prepare(`
+SELECT value FROM series_points
+WHERE series_name = ? AND date = ?
+`)
+	if err != nil {
+		return err
+	}
+	defer existing.Close()
+
 	stmt, err := tx.Prepare(`
 INSERT INTO series_points (series_name, date, value, meta, source_updated_at)
 VALUES (?, ?, ?, ?, ?)
 ON CONFLICT(series_name, date) DO UPDATE SET
 value = excluded.value,
 meta = excluded.meta,
-source_updated_at = excluded.source_updated_at
+source_updated_at = COALESCE(excluded.source_updated_at, source_updated_at),
+ingested_at = CURRENT_TIMESTAMP
 `)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
+	// New and revised values are kept as vintages so reads can be reproduced AsOf
+	vintage, err := tx.Prepare(`
+INSERT INTO series_point_vintages (series_name, date, value, meta, source_updated_at, recorded_at)
+SELECT series_name, date, value, meta, source_updated_at, ?
+FROM series_points
+WHERE series_name = ? AND date = ?
+`)
+	if err != nil {
+		return err
+	}
+	defer vintage.Close()
+	recordedAt := time.Now().UTC().Format(vintageTime)
+
+	var changed []SeriesPoint
 	for _, p := range points {
+		var old float64
+		isChanged := false
+		switch err := existing.QueryRow(seriesName, p.Date).Scan(&old); {
+		case err == sql.ErrNoRows:
+			isChanged = true
+		case err != nil:
+			return err
+		case old != p.Value:
+			isChanged = true
+		}
+
+		updatedAt := sourceUpdatedAt
+		if p.SourceUpdatedAt != nil {
+			updatedAt = *p.SourceUpdatedAt
+		}
+		var updated sql.NullString
+		if !updatedAt.IsZero() {
+			updated = sql.NullString{String: updatedAt.UTC().Format(time.RFC3339), Valid: true}
+		}
+
 		metaJSON, _ := json.Marshal(p.Meta)
-		_, err := stmt.Exec(seriesName, p.Date, p.Value, string(metaJSON), sourceUpdatedAt.Format(time.RFC3339))
+		_, err := stmt.Exec(seriesName, p.Date, p.Value, string(metaJSON), updated)
 		if err != nil {
 			return err
 		}
+
+		if isChanged {
+			if _, err := vintage.Exec(recordedAt, seriesName, p.Date); err != nil {
+				return err
+			}
+			changed = append(changed, p)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if len(changed) > 0 {
+		latest, err := s.GetLatestPoint(seriesName)
+		if err != nil || latest == nil {
+			return err
+		}
+		s.events.Publish(SeriesUpdated{
+			SeriesID: seriesName,
+			Points:   changed,
+			Previous: previous,
+			Latest:   *latest,
+			At:       time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// DeletePoints removes a series' observations on the given dates. Each removal is
+// kept as a vintage, so AsOf reads from before it still see the observation.
+func (s *Store) DeletePoints(seriesName string, dates []string) error {
+	if !s.asOf.IsZero() {
+		return fmt.Errorf("cannot delete points through a point-in-time view")
+	}
+	if len(dates) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
+	recordedAt := time.Now().UTC().Format(vintageTime)
+	for _, date := range dates {
+		if _, err := tx.Exec(`
+INSERT INTO series_point_vintages (series_name, date, value, meta, source_updated_at, recorded_at, deleted)
+SELECT series_name, date, value, meta, source_updated_at, ?, 1
+FROM series_points
+WHERE series_name = ? AND date = ?
+`, recordedAt, seriesName, date); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM series_points WHERE series_name = ? AND date = ?`, seriesName, date); err != nil {
+			return err
+		}
+	}
 	return tx.Commit()
 }
 
+// pointColumns are the series_points columns scanPoint reads. ingested_at is
+// declared DATETIME, so it is formatted to keep the driver from converting it;
+// formatting also drops the milliseconds of vintage times.
+const pointColumns = `date, value, meta, source_updated_at, strftime('%Y-%m-%d %H:%M:%S', ingested_at)`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPoint(row rowScanner) (SeriesPoint, error) {
+	var p SeriesPoint
+	var metaJSON, sourceUpdated, ingested sql.NullString
+
+	if err := row.Scan(&p.Date, &p.Value, &metaJSON, &sourceUpdated, &ingested); err != nil {
+		return p, err
+	}
+
+	if metaJSON.String != "" {
+		json.Unmarshal([]byte(metaJSON.String), &p.Meta)
+	}
+	if t, err := time.Parse(time.RFC3339, sourceUpdated.String); err == nil {
+		p.SourceUpdatedAt = &t
+	}
+	if t := parseNullTime(ingested); t != nil {
+		p.IngestedAt = *t
+	}
+
+	return p, nil
+}
+
+func (s *Store) queryPoints(query string, args ...interface{}) ([]SeriesPoint, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []SeriesPoint
+	for rows.Next() {
+		p, err := scanPoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
 func (s *Store) GetLatestPoint(seriesName string) (*SeriesPoint, error) {
-	row := s.db.QueryRow(`
-SELECT date, value, meta
-FROM series_points
+	table, args := s.pointsTable()
+	p, err := scanPoint(s.db.QueryRow(`
+SELECT `+pointColumns+`
+FROM `+table+`
 WHERE series_name = ?
 ORDER BY date DESC
 LIMIT 1
-`, seriesName)
-
-	var p SeriesPoint
-	var metaJSON string
-
-	if err := row.Scan(&p.Date, &p.Value, &metaJSON); err != nil {
+`, append(args, seriesName)...))
+	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	if metaJSON != "" {
-		json.Unmarshal([]byte(metaJSON), &p.Meta)
-	}
-
 	return &p, nil
 }
 
 func (s *Store) GetRecentPoints(seriesName string, limit int) ([]SeriesPoint, error) {
-	rows, err := s.db.Query(`
-SELECT date, value, meta
-FROM series_points
+	table, args := s.pointsTable()
+	return s.queryPoints(`
+SELECT `+pointColumns+`
+FROM `+table+`
 WHERE series_name = ?
 ORDER BY date DESC
 LIMIT ?
-`, seriesName, limit)
+`, append(args, seriesName, limit)...)
+}
+
+// GetPointsBetween returns a series' points with start <= date <= end, oldest first.
+// An empty start or end leaves that side of the range open.
+func (s *Store) GetPointsBetween(seriesName, start, end string) ([]SeriesPoint, error) {
+	return s.GetPoints(seriesName, PointFilter{Start: start, End: end})
+}
+
+// PointFilter selects a page of a series' points by date
+type PointFilter struct {
+	Start string // first date, inclusive; empty for the earliest
+	End   string // last date, inclusive; empty for the latest
+	After string // cursor: only dates after this one, e.g. the last date of the previous page
+	Limit int    // 0 for no limit
+}
+
+// GetPoints returns a series' points matching filter, oldest first. It reads a date
+// range off the (series_name, date) index, so pages cost the same wherever they start.
+func (s *Store) GetPoints(seriesName string, filter PointFilter) ([]SeriesPoint, error) {
+	end := filter.End
+	if end == "" {
+		end = "9999-12-31"
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	table, args := s.pointsTable()
+	return s.queryPoints(`
+SELECT `+pointColumns+`
+FROM `+table+`
+WHERE series_name = ? AND date >= ? AND date > ? AND date <= ?
+ORDER BY date ASC
+LIMIT ?
+`, append(args, seriesName, filter.Start, filter.After, end, limit)...)
+}
+
+// SyncSeries upserts catalog entries in display order. Entries no longer declared
+// are kept, since their observations may still be stored.
+func (s *Store) SyncSeries(series []Series) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+INSERT INTO series (id, name, label, unit, frequency, source, provider, license, license_url, link, position)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+name = excluded.name,
+label = excluded.label,
+unit = excluded.unit,
+frequency = excluded.frequency,
+source = excluded.source,
+provider = excluded.provider,
+license = excluded.license,
+license_url = excluded.license_url,
+link = excluded.link,
+position = excluded.position,
+updated_at = datetime('now')
+`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for i, e := range series {
+		label := e.Label
+		if label == "" {
+			label = e.Name
+		}
+		if _, err := stmt.Exec(e.ID, e.Name, label, e.Unit, e.Frequency, e.Source, e.Provider, e.License, e.LicenseURL, e.Link, i); err != nil {
+			return fmt.Errorf("failed to save series %s: %w", e.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListSeries returns the catalog in display order with coverage from series_points
+func (s *Store) ListSeries() ([]Series, error) {
+	return s.querySeries(`ORDER BY s.position, s.id`)
+}
+
+// GetSeries returns a catalog entry, or nil if the ID isn't in the catalog
+func (s *Store) GetSeries(id string) (*Series, error) {
+	series, err := s.querySeries(`WHERE s.id = ?`, id)
+	if err != nil || len(series) == 0 {
+		return nil, err
+	}
+	return &series[0], nil
+}
+
+// querySeries reads catalog entries with their coverage. The coverage subqueries are
+// correlated so each one only walks its own series on idx_series_date.
+func (s *Store) querySeries(where string, args ...interface{}) ([]Series, error) {
+	rows, err := s.db.Query(`
+SELECT s.id, s.name, s.label, s.unit, s.frequency, s.source, s.provider, s.license, s.license_url, s.link,
+       COALESCE((SELECT MIN(date) FROM series_points WHERE series_name = s.id), ''),
+       COALESCE((SELECT MAX(date) FROM series_points WHERE series_name = s.id), ''),
+       (SELECT COUNT(*) FROM series_points WHERE series_name = s.id)
+FROM series s
+`+where, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var points []SeriesPoint
+	var series []Series
 	for rows.Next() {
-		var p SeriesPoint
-		var metaJSON string
-
-		if err := rows.Scan(&p.Date, &p.Value, &metaJSON); err != nil {
+		var e Series
+		if err := rows.Scan(&e.ID, &e.Name, &e.Label, &e.Unit, &e.Frequency, &e.Source, &e.Provider, &e.License, &e.LicenseURL, &e.Link,
+			&e.FirstDate, &e.LastDate, &e.Observations); err != nil {
 			return nil, err
 		}
-
-		if metaJSON != "" {
-			json.Unmarshal([]byte(metaJSON), &p.Meta)
-		}
-
-		points = append(points, p)
+		series = append(series, e)
 	}
 
-	return points, nil
+	return series, rows.Err()
 }
 
 func (s *Store) SavePost(post *Post) error {
@@ -232,10 +655,30 @@ VALUES (?, ?, ?, ?, ?, ?)
 
 // CreateAlert creates a new alert
 func (s *Store) CreateAlert(alert *Alert) error {
+	if alert.Confirmations < 1 {
+		alert.Confirmations = 1
+	}
+	if alert.State == "" {
+		alert.State = AlertArmed
+	}
+	if alert.WebhookSecret == "" {
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		alert.WebhookSecret = "whsec_" + hex.EncodeToString(secret)
+	}
+
+	channelsJSON, err := marshalChannels(alert.Channels)
+	if err != nil {
+		return err
+	}
+
 	result, err := s.db.Exec(`
-INSERT INTO alerts (user_email, name, series_id, condition, threshold, webhook_url, is_active)
-VALUES (?, ?, ?, ?, ?, ?, ?)
-`, alert.UserEmail, alert.Name, alert.SeriesID, alert.Condition, alert.Threshold, alert.WebhookURL, alert.IsActive)
+INSERT INTO alerts (user_email, name, series_id, condition, threshold, webhook_url, webhook_secret, channels, is_active, rearm_band, confirmations, cooldown_minutes, state)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`, alert.UserEmail, alert.Name, alert.SeriesID, alert.Condition, alert.Threshold, alert.WebhookURL, alert.WebhookSecret, channelsJSON, alert.IsActive,
+		alert.RearmBand, alert.Confirmations, alert.CooldownMinutes, alert.State)
 
 	if err != nil {
 		return err
@@ -245,47 +688,56 @@ VALUES (?, ?, ?, ?, ?, ?, ?)
 	return nil
 }
 
+func marshalChannels(channels []AlertChannel) (string, error) {
+	if len(channels) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(channels)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+const alertColumns = `id, user_email, name, series_id, condition, threshold, webhook_url, webhook_secret, channels, is_active,
+rearm_band, confirmations, cooldown_minutes, state, breach_count, last_observed_date, state_changed_at,
+last_triggered_at, created_at`
+
 // ListAlerts lists all alerts for a user
 func (s *Store) ListAlerts(userEmail string) ([]Alert, error) {
-	rows, err := s.db.Query(`
-SELECT id, user_email, name, series_id, condition, threshold, webhook_url, is_active, last_triggered_at, created_at
+	return s.queryAlerts(`
+SELECT `+alertColumns+`
 FROM alerts
 WHERE user_email = ?
 ORDER BY created_at DESC
 `, userEmail)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var alerts []Alert
-	for rows.Next() {
-		var a Alert
-		var lastTriggered sql.NullString
-
-		if err := rows.Scan(&a.ID, &a.UserEmail, &a.Name, &a.SeriesID, &a.Condition, &a.Threshold, &a.WebhookURL, &a.IsActive, &lastTriggered, &a.CreatedAt); err != nil {
-			return nil, err
-		}
-
-		if lastTriggered.Valid {
-			t, _ := time.Parse(time.RFC3339, lastTriggered.String)
-			a.LastTriggeredAt = &t
-		}
+}
 
-		alerts = append(alerts, a)
+// GetAlert gets one alert, or nil if not found
+func (s *Store) GetAlert(id int64) (*Alert, error) {
+	alerts, err := s.queryAlerts(`
+SELECT `+alertColumns+`
+FROM alerts
+WHERE id = ?
+`, id)
+	if err != nil || len(alerts) == 0 {
+		return nil, err
 	}
-
-	return alerts, nil
+	return &alerts[0], nil
 }
 
 // GetActiveAlerts gets all active alerts
 func (s *Store) GetActiveAlerts() ([]Alert, error) {
-	rows, err := s.db.Query(`
-SELECT id, user_email, name, series_id, condition, threshold, webhook_url, is_active, last_triggered_at, created_at
+	return s.queryAlerts(`
+SELECT ` + alertColumns + `
 FROM alerts
 WHERE is_active = 1
 ORDER BY created_at DESC
 `)
+}
+
+func (s *Store) queryAlerts(query string, args ...interface{}) ([]Alert, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -294,21 +746,39 @@ ORDER BY created_at DESC
 	var alerts []Alert
 	for rows.Next() {
 		var a Alert
-		var lastTriggered sql.NullString
+		var webhookURL, stateChanged, lastTriggered sql.NullString
+		var channelsJSON, createdAt string
 
-		if err := rows.Scan(&a.ID, &a.UserEmail, &a.Name, &a.SeriesID, &a.Condition, &a.Threshold, &a.WebhookURL, &a.IsActive, &lastTriggered, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.UserEmail, &a.Name, &a.SeriesID, &a.Condition, &a.Threshold, &webhookURL, &a.WebhookSecret, &channelsJSON, &a.IsActive,
+			&a.RearmBand, &a.Confirmations, &a.CooldownMinutes, &a.State, &a.BreachCount, &a.LastObservedDate, &stateChanged,
+			&lastTriggered, &createdAt); err != nil {
 			return nil, err
 		}
 
-		if lastTriggered.Valid {
-			t, _ := time.Parse(time.RFC3339, lastTriggered.String)
-			a.LastTriggeredAt = &t
+		a.WebhookURL = webhookURL.String
+		if channelsJSON != "" {
+			json.Unmarshal([]byte(channelsJSON), &a.Channels)
 		}
+		a.StateChangedAt = parseNullTime(stateChanged)
+		a.LastTriggeredAt = parseNullTime(lastTriggered)
+		a.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
 
 		alerts = append(alerts, a)
 	}
 
-	return alerts, nil
+	return alerts, rows.Err()
+}
+
+// parseNullTime parses a nullable datetime('now') column
+func parseNullTime(v sql.NullString) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", v.String)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 // DeleteAlert deletes an alert
@@ -320,22 +790,76 @@ WHERE id = ? AND user_email = ?
 	return err
 }
 
-// UpdateAlertTriggered updates the last triggered time for an alert
-func (s *Store) UpdateAlertTriggered(id int64) error {
+// UpdateAlert saves an alert's editable fields. A changed series, condition or
+// threshold restarts the state machine so the next evaluation re-establishes
+// which side of the threshold the value is on.
+func (s *Store) UpdateAlert(alert *Alert) error {
+	channelsJSON, err := marshalChannels(alert.Channels)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+UPDATE alerts
+SET state = CASE WHEN series_id != ? OR condition != ? OR threshold != ? THEN ? ELSE state END,
+breach_count = CASE WHEN series_id != ? OR condition != ? OR threshold != ? THEN 0 ELSE breach_count END,
+last_observed_date = CASE WHEN series_id != ? OR condition != ? OR threshold != ? THEN '' ELSE last_observed_date END,
+name = ?, series_id = ?, condition = ?, threshold = ?, webhook_url = ?, channels = ?,
+rearm_band = ?, confirmations = ?, cooldown_minutes = ?
+WHERE id = ? AND user_email = ?
+`, alert.SeriesID, alert.Condition, alert.Threshold, AlertArmed,
+		alert.SeriesID, alert.Condition, alert.Threshold,
+		alert.SeriesID, alert.Condition, alert.Threshold,
+		alert.Name, alert.SeriesID, alert.Condition, alert.Threshold, alert.WebhookURL, channelsJSON,
+		alert.RearmBand, alert.Confirmations, alert.CooldownMinutes,
+		alert.ID, alert.UserEmail)
+	return err
+}
+
+// SetAlertActive pauses or resumes an alert. Resuming re-arms it so observations
+// made while paused don't fire it.
+func (s *Store) SetAlertActive(id int64, userEmail string, active bool) error {
+	_, err := s.db.Exec(`
+UPDATE alerts
+SET state = CASE WHEN ? AND is_active = 0 THEN ? ELSE state END,
+breach_count = CASE WHEN ? AND is_active = 0 THEN 0 ELSE breach_count END,
+last_observed_date = CASE WHEN ? AND is_active = 0 THEN '' ELSE last_observed_date END,
+is_active = ?
+WHERE id = ? AND user_email = ?
+`, active, AlertArmed, active, active, active, id, userEmail)
+	return err
+}
+
+// SaveAlertState stores the alert's state machine after an evaluation. fired also
+// records the trigger time.
+func (s *Store) SaveAlertState(alert *Alert, fired bool) error {
 	_, err := s.db.Exec(`
 UPDATE alerts
-SET last_triggered_at = datetime('now')
+SET state_changed_at = CASE WHEN state != ? THEN datetime('now') ELSE state_changed_at END,
+state = ?,
+breach_count = ?,
+last_observed_date = ?,
+last_triggered_at = CASE WHEN ? THEN datetime('now') ELSE last_triggered_at END
 WHERE id = ?
-`, id)
+`, alert.State, alert.State, alert.BreachCount, alert.LastObservedDate, fired, alert.ID)
 	return err
 }
 
 // SaveAlertHistory saves an alert trigger to history
 func (s *Store) SaveAlertHistory(history *AlertHistory) error {
+	deliveriesJSON := ""
+	if len(history.Deliveries) > 0 {
+		data, err := json.Marshal(history.Deliveries)
+		if err != nil {
+			return err
+		}
+		deliveriesJSON = string(data)
+	}
+
 	result, err := s.db.Exec(`
-INSERT INTO alert_history (alert_id, series_id, value, threshold, webhook_status)
-VALUES (?, ?, ?, ?, ?)
-`, history.AlertID, history.SeriesID, history.Value, history.Threshold, history.WebhookStatus)
+INSERT INTO alert_history (alert_id, series_id, value, threshold, webhook_status, deliveries)
+VALUES (?, ?, ?, ?, ?, ?)
+`, history.AlertID, history.SeriesID, history.Value, history.Threshold, history.WebhookStatus, deliveriesJSON)
 
 	if err != nil {
 		return err
@@ -345,6 +869,220 @@ VALUES (?, ?, ?, ?, ?)
 	return nil
 }
 
+// SetAlertHistoryDeliveries updates a trigger's per-channel outcomes once deliveries finish
+func (s *Store) SetAlertHistoryDeliveries(id int64, status string, deliveries []ChannelDelivery) error {
+	data, err := json.Marshal(deliveries)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+UPDATE alert_history
+SET webhook_status = ?, deliveries = ?
+WHERE id = ?
+`, status, string(data), id)
+	return err
+}
+
+// GetAlertHistory gets one alert trigger, or nil if not found
+func (s *Store) GetAlertHistory(id int64) (*AlertHistory, error) {
+	var h AlertHistory
+	var webhookStatus sql.NullString
+	var triggeredAt, deliveriesJSON string
+
+	err := s.db.QueryRow(`
+SELECT id, alert_id, series_id, value, threshold, triggered_at, webhook_status, deliveries
+FROM alert_history
+WHERE id = ?
+`, id).Scan(&h.ID, &h.AlertID, &h.SeriesID, &h.Value, &h.Threshold, &triggeredAt, &webhookStatus, &deliveriesJSON)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	h.TriggeredAt, _ = time.Parse("2006-01-02 15:04:05", triggeredAt)
+	h.WebhookStatus = webhookStatus.String
+	if deliveriesJSON != "" {
+		json.Unmarshal([]byte(deliveriesJSON), &h.Deliveries)
+	}
+
+	return &h, nil
+}
+
+// ListAlertHistory lists an alert's triggers, newest first
+func (s *Store) ListAlertHistory(alertID int64, limit int) ([]AlertHistory, error) {
+	rows, err := s.db.Query(`
+SELECT id, alert_id, series_id, value, threshold, triggered_at, webhook_status, deliveries
+FROM alert_history
+WHERE alert_id = ?
+ORDER BY triggered_at DESC, id DESC
+LIMIT ?
+`, alertID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []AlertHistory
+	for rows.Next() {
+		var h AlertHistory
+		var webhookStatus sql.NullString
+		var triggeredAt, deliveriesJSON string
+
+		if err := rows.Scan(&h.ID, &h.AlertID, &h.SeriesID, &h.Value, &h.Threshold, &triggeredAt, &webhookStatus, &deliveriesJSON); err != nil {
+			return nil, err
+		}
+
+		h.TriggeredAt, _ = time.Parse("2006-01-02 15:04:05", triggeredAt)
+		h.WebhookStatus = webhookStatus.String
+		if deliveriesJSON != "" {
+			json.Unmarshal([]byte(deliveriesJSON), &h.Deliveries)
+		}
+
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
+
+// CreateWebhookDelivery adds a delivery to the outbox, claimed by the caller for its
+// first attempt: retries only pick it up if that attempt hasn't finished within lease
+func (s *Store) CreateWebhookDelivery(d *WebhookDelivery, lease time.Duration) error {
+	leaseUntil := time.Now().Add(lease)
+	d.Status, d.NextAttemptAt = DeliverySending, &leaseUntil
+
+	result, err := s.db.Exec(`
+INSERT INTO webhook_deliveries (alert_id, history_id, replay_of, target, payload, status, next_attempt_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`, d.AlertID, d.HistoryID, d.ReplayOf, d.Target, d.Payload, d.Status, formatNullTime(d.NextAttemptAt))
+
+	if err != nil {
+		return err
+	}
+
+	d.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// UpdateWebhookDelivery records the outcome of a delivery attempt
+func (s *Store) UpdateWebhookDelivery(d *WebhookDelivery) error {
+	_, err := s.db.Exec(`
+UPDATE webhook_deliveries
+SET status = ?, attempts = ?, last_error = ?, last_status_code = ?, next_attempt_at = ?, delivered_at = ?, updated_at = datetime('now')
+WHERE id = ?
+`, d.Status, d.Attempts, d.LastError, d.LastStatusCode, formatNullTime(d.NextAttemptAt), formatNullTime(d.DeliveredAt), d.ID)
+	return err
+}
+
+// formatNullTime formats a time for a nullable datetime('now')-style column
+func formatNullTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+const webhookDeliveryQuery = `
+SELECT d.id, d.alert_id, d.history_id, d.replay_of, d.target, d.payload, d.status, d.attempts, d.last_error,
+	d.last_status_code, d.next_attempt_at, d.delivered_at, d.created_at, d.updated_at, a.webhook_secret
+FROM webhook_deliveries d
+JOIN alerts a ON a.id = d.alert_id
+WHERE 1 = 1`
+
+// GetWebhookDelivery gets one delivery, or nil if not found
+func (s *Store) GetWebhookDelivery(id int64) (*WebhookDelivery, error) {
+	deliveries, err := s.queryWebhookDeliveries(webhookDeliveryQuery+" AND d.id = ?", id)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return &deliveries[0], nil
+}
+
+// ListWebhookDeliveries lists deliveries, newest first
+func (s *Store) ListWebhookDeliveries(filter WebhookDeliveryFilter) ([]WebhookDelivery, error) {
+	query := webhookDeliveryQuery
+	var args []interface{}
+
+	if filter.UserEmail != "" {
+		query += " AND a.user_email = ?"
+		args = append(args, filter.UserEmail)
+	}
+	if filter.AlertID != 0 {
+		query += " AND d.alert_id = ?"
+		args = append(args, filter.AlertID)
+	}
+	if filter.Status != "" {
+		query += " AND d.status = ?"
+		args = append(args, filter.Status)
+	}
+	query += " ORDER BY d.id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return s.queryWebhookDeliveries(query, args...)
+}
+
+// ClaimDueWebhookDelivery claims the oldest delivery whose next attempt is due: a
+// pending retry, or an attempt still marked sending after its lease ran out. The
+// delivery is marked sending until lease passes, so no other caller claims it while
+// it is attempted. It returns nil if nothing is due.
+func (s *Store) ClaimDueWebhookDelivery(now time.Time, lease time.Duration) (*WebhookDelivery, error) {
+	due := now.UTC().Format("2006-01-02 15:04:05")
+	var id int64
+	err := s.db.QueryRow(`
+UPDATE webhook_deliveries
+SET status = ?, next_attempt_at = ?, updated_at = datetime('now')
+WHERE id = (
+    SELECT id FROM webhook_deliveries
+    WHERE status IN (?, ?) AND next_attempt_at <= ?
+    ORDER BY next_attempt_at, id
+    LIMIT 1
+) AND status IN (?, ?) AND next_attempt_at <= ?
+RETURNING id
+`, DeliverySending, now.Add(lease).UTC().Format("2006-01-02 15:04:05"),
+		DeliveryPending, DeliverySending, due, DeliveryPending, DeliverySending, due).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetWebhookDelivery(id)
+}
+
+func (s *Store) queryWebhookDeliveries(query string, args ...interface{}) ([]WebhookDelivery, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		var nextAttempt, delivered sql.NullString
+		var createdAt, updatedAt string
+
+		if err := rows.Scan(&d.ID, &d.AlertID, &d.HistoryID, &d.ReplayOf, &d.Target, &d.Payload, &d.Status, &d.Attempts, &d.LastError,
+			&d.LastStatusCode, &nextAttempt, &delivered, &createdAt, &updatedAt, &d.Secret); err != nil {
+			return nil, err
+		}
+		d.NextAttemptAt = parseNullTime(nextAttempt)
+		d.DeliveredAt = parseNullTime(delivered)
+		d.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+		d.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
 // SaveLead creates or updates a lead
 func (s *Store) SaveLead(lead *Lead) error {
 	result, err := s.db.Exec(`
@@ -537,6 +1275,254 @@ LIMIT 1
 	return &post, nil
 }
 
+// RecordSignalState saves a signal's status if it differs from the last recorded one.
+// It fills in FromStatus, ID and CreatedAt and reports whether a transition was recorded.
+func (s *Store) RecordSignalState(t *SignalTransition) (bool, error) {
+	// One statement reads the current state and inserts, so concurrent callers can't
+	// both record the same transition
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	var from string
+	err := s.db.QueryRow(`
+INSERT INTO signal_transitions (signal_key, series_id, from_status, to_status, value, as_of, rule, why, created_at)
+SELECT ?, ?, COALESCE(current.to_status, ''), ?, ?, ?, ?, ?, ?
+FROM (SELECT 1)
+LEFT JOIN (
+    SELECT to_status FROM signal_transitions
+    WHERE signal_key = ?
+    ORDER BY id DESC
+    LIMIT 1
+) current
+WHERE current.to_status IS NULL OR current.to_status != ?
+RETURNING id, from_status
+`, t.SignalKey, t.SeriesID, t.ToStatus, t.Value, t.AsOf, t.Rule, t.Why, createdAt.Format("2006-01-02 15:04:05"),
+		t.SignalKey, t.ToStatus).Scan(&t.ID, &from)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	t.FromStatus = from
+	t.CreatedAt = createdAt
+	s.signals.Publish(*t)
+	return true, nil
+}
+
+// GetSignalTransitions lists recorded transitions, newest first
+func (s *Store) GetSignalTransitions(filter SignalTransitionFilter) ([]SignalTransition, error) {
+	query := `
+SELECT id, signal_key, series_id, from_status, to_status, value, as_of, rule, why, created_at
+FROM signal_transitions
+WHERE 1 = 1`
+	var args []interface{}
+
+	if filter.SignalKey != "" {
+		query += " AND signal_key = ?"
+		args = append(args, filter.SignalKey)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	query += " ORDER BY id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return s.querySignalTransitions(query, args...)
+}
+
+// GetCurrentSignalStates returns the latest transition for every signal
+func (s *Store) GetCurrentSignalStates() ([]SignalTransition, error) {
+	return s.querySignalTransitions(`
+SELECT id, signal_key, series_id, from_status, to_status, value, as_of, rule, why, created_at
+FROM signal_transitions
+WHERE id IN (SELECT MAX(id) FROM signal_transitions GROUP BY signal_key)
+ORDER BY signal_key
+`)
+}
+
+func (s *Store) querySignalTransitions(query string, args ...interface{}) ([]SignalTransition, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []SignalTransition
+	for rows.Next() {
+		var t SignalTransition
+		var createdAt string
+
+		if err := rows.Scan(&t.ID, &t.SignalKey, &t.SeriesID, &t.FromStatus, &t.ToStatus, &t.Value, &t.AsOf, &t.Rule, &t.Why, &createdAt); err != nil {
+			return nil, err
+		}
+		t.CreatedAt, _ = time.Parse("2006-01-02 15:04:05", createdAt)
+
+		transitions = append(transitions, t)
+	}
+
+	return transitions, rows.Err()
+}
+
+// feedEventsQuery reads signal transitions as feed events
+const feedEventsQuery = `
+SELECT id, series_id, signal_key, from_status, status, value, as_of, rule, why, occurred_at
+FROM (
+	SELECT id, series_id, signal_key, from_status, to_status AS status, value, as_of, rule, why, created_at AS occurred_at
+	FROM signal_transitions
+)
+WHERE 1 = 1`
+
+// ListFeedEvents lists signal transitions, newest first
+func (s *Store) ListFeedEvents(filter FeedFilter) ([]FeedEvent, error) {
+	query := feedEventsQuery
+	var args []interface{}
+
+	if filter.SeriesID != "" {
+		query += " AND series_id = ?"
+		args = append(args, filter.SeriesID)
+	}
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND occurred_at >= ?"
+		args = append(args, filter.Since.UTC().Format("2006-01-02 15:04:05"))
+	}
+	query += " ORDER BY occurred_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	return s.queryFeedEvents(query, args...)
+}
+
+// GetFeedEvent gets one feed event by its ID ("signal-42"), or nil if not found
+func (s *Store) GetFeedEvent(id string) (*FeedEvent, error) {
+	var rowID int64
+	if _, err := fmt.Sscanf(id, "signal-%d", &rowID); err != nil || fmt.Sprintf("signal-%d", rowID) != id {
+		return nil, nil
+	}
+
+	events, err := s.queryFeedEvents(feedEventsQuery+" AND id = ?", rowID)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return &events[0], nil
+}
+
+func (s *Store) queryFeedEvents(query string, args ...interface{}) ([]FeedEvent, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []FeedEvent
+	for rows.Next() {
+		var e FeedEvent
+		var rowID int64
+		var occurredAt string
+
+		if err := rows.Scan(&rowID, &e.SeriesID, &e.SignalKey, &e.FromStatus, &e.Status, &e.Value, &e.AsOf, &e.Rule, &e.Why, &occurredAt); err != nil {
+			return nil, err
+		}
+		e.ID = fmt.Sprintf("signal-%d", rowID)
+		e.OccurredAt, _ = time.Parse("2006-01-02 15:04:05", occurredAt)
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// GetBackfillProgress gets backfill coverage for a series, or nil if never backfilled
+func (s *Store) GetBackfillProgress(seriesID string) (*BackfillProgress, error) {
+	var p BackfillProgress
+	var completedAt sql.NullString
+	var updatedAt string
+
+	err := s.db.QueryRow(`
+SELECT series_id, start_date, covered_through, points_saved, completed_at, updated_at
+FROM backfill_progress
+WHERE series_id = ?
+`, seriesID).Scan(&p.SeriesID, &p.StartDate, &p.CoveredThrough, &p.PointsSaved, &completedAt, &updatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	p.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+	if completedAt.Valid {
+		t, _ := time.Parse("2006-01-02 15:04:05", completedAt.String)
+		p.CompletedAt = &t
+	}
+
+	return &p, nil
+}
+
+// SaveBackfillProgress records backfill coverage for a series
+func (s *Store) SaveBackfillProgress(p *BackfillProgress) error {
+	var completedAt interface{}
+	if p.CompletedAt != nil {
+		completedAt = p.CompletedAt.UTC().Format("2006-01-02 15:04:05")
+	}
+
+	_, err := s.db.Exec(`
+INSERT INTO backfill_progress (series_id, start_date, covered_through, points_saved, completed_at, updated_at)
+VALUES (?, ?, ?, ?, ?, datetime('now'))
+ON CONFLICT(series_id) DO UPDATE SET
+start_date = excluded.start_date,
+covered_through = excluded.covered_through,
+points_saved = excluded.points_saved,
+completed_at = excluded.completed_at,
+updated_at = excluded.updated_at
+`, p.SeriesID, p.StartDate, p.CoveredThrough, p.PointsSaved, completedAt)
+	return err
+}
+
+// ListBackfillProgress lists backfill coverage for all series
+func (s *Store) ListBackfillProgress() ([]BackfillProgress, error) {
+	rows, err := s.db.Query(`
+SELECT series_id, start_date, covered_through, points_saved, completed_at, updated_at
+FROM backfill_progress
+ORDER BY series_id
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var progress []BackfillProgress
+	for rows.Next() {
+		var p BackfillProgress
+		var completedAt sql.NullString
+		var updatedAt string
+
+		if err := rows.Scan(&p.SeriesID, &p.StartDate, &p.CoveredThrough, &p.PointsSaved, &completedAt, &updatedAt); err != nil {
+			return nil, err
+		}
+
+		p.UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", updatedAt)
+		if completedAt.Valid {
+			t, _ := time.Parse("2006-01-02 15:04:05", completedAt.String)
+			p.CompletedAt = &t
+		}
+
+		progress = append(progress, p)
+	}
+
+	return progress, rows.Err()
+}
+
 func (s *Store) Close() error {
 	return s.db.Close()
 }
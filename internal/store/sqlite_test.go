@@ -1,10 +1,13 @@
 package store
 
 import (
-	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"reserve-watch/migrations"
 )
 
 func TestNew(t *testing.T) {
@@ -22,33 +25,6 @@ func TestNew(t *testing.T) {
 	}
 }
 
-func TestMigrate(t *testing.T) {
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "test.db")
-	migrationsDir := filepath.Join(tmpDir, "migrations")
-
-	os.MkdirAll(migrationsDir, 0755)
-
-	migration := `CREATE TABLE test (id INTEGER PRIMARY KEY);`
-	os.WriteFile(filepath.Join(migrationsDir, "001_test.sql"), []byte(migration), 0644)
-
-	store, err := New(dbPath)
-	if err != nil {
-		t.Fatalf("Failed to create store: %v", err)
-	}
-	defer store.Close()
-
-	if err := store.Migrate(migrationsDir); err != nil {
-		t.Fatalf("Failed to run migrations: %v", err)
-	}
-
-	var tableName string
-	err = store.db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name='test'").Scan(&tableName)
-	if err != nil {
-		t.Errorf("Expected test table to exist: %v", err)
-	}
-}
-
 func TestSaveAndGetPoints(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -59,8 +35,7 @@ func TestSaveAndGetPoints(t *testing.T) {
 	}
 	defer store.Close()
 
-	migrationsDir := "../../migrations"
-	if err := store.Migrate(migrationsDir); err != nil {
+	if _, err := store.Migrate(migrations.FS); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -92,6 +67,56 @@ func TestSaveAndGetPoints(t *testing.T) {
 	}
 }
 
+func TestPointMetaAndFreshness(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	published := time.Date(2024, 1, 16, 21, 17, 2, 0, time.UTC)
+	before := time.Now().UTC().Add(-time.Second).Truncate(time.Second)
+	if err := store.SavePoints("TEST_SERIES", []SeriesPoint{
+		{Date: "2024-01-15", Value: 100.5, Meta: map[string]string{"unit": "index"}, SourceUpdatedAt: &published},
+		{Date: "2024-01-14", Value: 99.8},
+	}, time.Time{}); err != nil {
+		t.Fatalf("Failed to save points: %v", err)
+	}
+
+	points, err := store.GetPointsBetween("TEST_SERIES", "", "")
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(points))
+	}
+	if points[0].SourceUpdatedAt != nil {
+		t.Errorf("Expected no source timestamp when the source doesn't report one, got %v", points[0].SourceUpdatedAt)
+	}
+	latest := points[1]
+	if latest.Meta["unit"] != "index" {
+		t.Errorf("Expected meta to round-trip, got %v", latest.Meta)
+	}
+	if latest.SourceUpdatedAt == nil || !latest.SourceUpdatedAt.Equal(published) {
+		t.Errorf("Expected source timestamp %v, got %v", published, latest.SourceUpdatedAt)
+	}
+	if latest.IngestedAt.Before(before) || latest.IngestedAt.After(time.Now().Add(time.Second)) {
+		t.Errorf("Expected ingestion time around now, got %v", latest.IngestedAt)
+	}
+
+	// A later save without a source timestamp keeps the known one
+	if err := store.SavePoints("TEST_SERIES", []SeriesPoint{{Date: "2024-01-15", Value: 100.5}}, time.Time{}); err != nil {
+		t.Fatalf("Failed to save points: %v", err)
+	}
+	if p, _ := store.GetLatestPoint("TEST_SERIES"); p == nil || p.SourceUpdatedAt == nil || !p.SourceUpdatedAt.Equal(published) {
+		t.Errorf("Expected source timestamp to be kept, got %+v", p)
+	}
+}
+
 func TestGetRecentPoints(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -102,8 +127,7 @@ func TestGetRecentPoints(t *testing.T) {
 	}
 	defer store.Close()
 
-	migrationsDir := "../../migrations"
-	if err := store.Migrate(migrationsDir); err != nil {
+	if _, err := store.Migrate(migrations.FS); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -142,8 +166,7 @@ func TestSavePost(t *testing.T) {
 	}
 	defer store.Close()
 
-	migrationsDir := "../../migrations"
-	if err := store.Migrate(migrationsDir); err != nil {
+	if _, err := store.Migrate(migrations.FS); err != nil {
 		t.Fatalf("Failed to run migrations: %v", err)
 	}
 
@@ -165,3 +188,468 @@ func TestSavePost(t *testing.T) {
 		t.Error("Expected post ID to be set after save")
 	}
 }
+
+func TestGetPointsBetween(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	points := []SeriesPoint{
+		{Date: "2024-03-31", Value: 3},
+		{Date: "2024-01-31", Value: 1},
+		{Date: "2024-02-29", Value: 2},
+	}
+	if err := store.SavePoints("TEST_SERIES", points, time.Now()); err != nil {
+		t.Fatalf("Failed to save points: %v", err)
+	}
+
+	between, err := store.GetPointsBetween("TEST_SERIES", "2024-02-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+	if len(between) != 2 || between[0].Date != "2024-02-29" || between[1].Date != "2024-03-31" {
+		t.Errorf("Expected Feb and Mar oldest first, got %+v", between)
+	}
+
+	all, err := store.GetPointsBetween("TEST_SERIES", "", "")
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected open range to return 3 points, got %d", len(all))
+	}
+
+	// Paging with the last date of each page as the cursor
+	first, err := store.GetPoints("TEST_SERIES", PointFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to get first page: %v", err)
+	}
+	if len(first) != 2 || first[1].Date != "2024-02-29" {
+		t.Fatalf("Expected Jan and Feb on the first page, got %+v", first)
+	}
+	next, err := store.GetPoints("TEST_SERIES", PointFilter{After: first[1].Date, Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to get next page: %v", err)
+	}
+	if len(next) != 1 || next[0].Date != "2024-03-31" {
+		t.Errorf("Expected only Mar after the cursor, got %+v", next)
+	}
+}
+
+func TestRecordSignalState(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	var published []SignalTransition
+	store.SignalEvents().Subscribe("test", func(batch []SignalTransition) {
+		published = append(published, batch...)
+	})
+
+	record := func(key, status string, value float64) bool {
+		t.Helper()
+		changed, err := store.RecordSignalState(&SignalTransition{SignalKey: key, SeriesID: "VIXCLS", ToStatus: status, Value: value, AsOf: "2024-01-02"})
+		if err != nil {
+			t.Fatalf("Failed to record state: %v", err)
+		}
+		return changed
+	}
+
+	if !record("vix", "good", 15) {
+		t.Error("Expected first state to be recorded")
+	}
+	if record("vix", "good", 16) {
+		t.Error("Expected unchanged status not to be recorded")
+	}
+	if !record("vix", "watch", 22) {
+		t.Error("Expected status change to be recorded")
+	}
+	record("bbb_oas", "good", 150)
+
+	store.SignalEvents().Wait()
+	if len(published) != 3 || published[1].FromStatus != "good" || published[1].ToStatus != "watch" {
+		t.Errorf("Expected the 3 recorded transitions to be published, got %+v", published)
+	}
+
+	transitions, err := store.GetSignalTransitions(SignalTransitionFilter{SignalKey: "vix"})
+	if err != nil {
+		t.Fatalf("Failed to get transitions: %v", err)
+	}
+	if len(transitions) != 2 {
+		t.Fatalf("Expected 2 vix transitions, got %d", len(transitions))
+	}
+	if transitions[0].FromStatus != "good" || transitions[0].ToStatus != "watch" || transitions[0].Value != 22 {
+		t.Errorf("Expected newest good -> watch transition first, got %+v", transitions[0])
+	}
+	if transitions[0].CreatedAt.IsZero() {
+		t.Error("Expected created_at to be parsed")
+	}
+
+	current, err := store.GetCurrentSignalStates()
+	if err != nil {
+		t.Fatalf("Failed to get current states: %v", err)
+	}
+	if len(current) != 2 || current[1].SignalKey != "vix" || current[1].ToStatus != "watch" {
+		t.Errorf("Expected current state per signal, got %+v", current)
+	}
+}
+
+func TestRecordSignalStateConcurrent(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	// The bus subscriber and the social poster may record the same change at once
+	var wg sync.WaitGroup
+	var recorded atomic.Int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			changed, err := store.RecordSignalState(&SignalTransition{SignalKey: "vix", SeriesID: "VIXCLS", ToStatus: "watch", Value: 22})
+			if err != nil {
+				t.Errorf("Failed to record state: %v", err)
+			}
+			if changed {
+				recorded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	transitions, err := store.GetSignalTransitions(SignalTransitionFilter{SignalKey: "vix"})
+	if err != nil {
+		t.Fatalf("Failed to get transitions: %v", err)
+	}
+	if recorded.Load() != 1 || len(transitions) != 1 {
+		t.Errorf("Expected exactly one transition recorded, got %d reported and %d stored", recorded.Load(), len(transitions))
+	}
+}
+
+func TestListFeedEvents(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	store.RecordSignalState(&SignalTransition{SignalKey: "vix", SeriesID: "VIXCLS", ToStatus: "good", Value: 15, AsOf: "2024-01-02"})
+	store.RecordSignalState(&SignalTransition{SignalKey: "vix", SeriesID: "VIXCLS", ToStatus: "watch", Value: 22, AsOf: "2024-01-03"})
+
+	alert := &Alert{UserEmail: "a@example.com", Name: "VIX high", SeriesID: "VIXCLS", Condition: "above", Threshold: 20, IsActive: true}
+	if err := store.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	if err := store.SaveAlertHistory(&AlertHistory{AlertID: alert.ID, SeriesID: "VIXCLS", Value: 22, Threshold: 20, WebhookStatus: "skipped"}); err != nil {
+		t.Fatalf("Failed to save alert history: %v", err)
+	}
+
+	events, err := store.ListFeedEvents(FeedFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list feed events: %v", err)
+	}
+	// Alert triggers are private to their owner and stay off the public feed
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 signal events, got %d", len(events))
+	}
+	if events[0].ID != "signal-2" || events[0].FromStatus != "good" || events[0].Status != "watch" {
+		t.Errorf("Expected newest transition first, got %+v", events[0])
+	}
+
+	watch, err := store.ListFeedEvents(FeedFilter{Status: "watch"})
+	if err != nil {
+		t.Fatalf("Failed to filter feed events: %v", err)
+	}
+	if len(watch) != 1 || watch[0].Value != 22 {
+		t.Errorf("Expected 1 watch event, got %+v", watch)
+	}
+
+	page, err := store.ListFeedEvents(FeedFilter{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("Failed to page feed events: %v", err)
+	}
+	if len(page) != 1 || page[0].ID != "signal-1" {
+		t.Errorf("Expected oldest transition on second page, got %+v", page)
+	}
+
+	event, err := store.GetFeedEvent("signal-1")
+	if err != nil {
+		t.Fatalf("Failed to get feed event: %v", err)
+	}
+	if event == nil || event.Status != "good" || event.Value != 15 {
+		t.Errorf("Expected signal-1 at good, got %+v", event)
+	}
+	if alertEvent, _ := store.GetFeedEvent("alert-1"); alertEvent != nil {
+		t.Errorf("Expected alert triggers not to be feed events, got %+v", alertEvent)
+	}
+	if missing, _ := store.GetFeedEvent("signal-99"); missing != nil {
+		t.Errorf("Expected missing event to be nil, got %+v", missing)
+	}
+}
+
+func TestMigrateTwice(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := store.Migrate(migrations.FS); err != nil {
+			t.Fatalf("Failed to run migrations (pass %d): %v", i+1, err)
+		}
+	}
+
+	alert := &Alert{UserEmail: "a@example.com", Name: "VIX", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true, RearmBand: 2}
+	if err := store.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	alerts, err := store.ListAlerts("a@example.com")
+	if err != nil {
+		t.Fatalf("Failed to list alerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].State != AlertArmed || alerts[0].Confirmations != 1 || alerts[0].RearmBand != 2 || alerts[0].CreatedAt.IsZero() {
+		t.Errorf("Expected armed alert with its settings, got %+v", alerts)
+	}
+}
+
+func TestUpdateAlert(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	alert := &Alert{UserEmail: "a@example.com", Name: "VIX", SeriesID: "VIXCLS", Condition: "above", Threshold: 25, IsActive: true}
+	if err := store.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	alert.State, alert.BreachCount, alert.LastObservedDate = AlertTriggered, 1, "2024-01-02"
+	if err := store.SaveAlertState(alert, true); err != nil {
+		t.Fatalf("Failed to save alert state: %v", err)
+	}
+
+	// Renaming keeps the state machine
+	alert.Name = "VIX spike"
+	if err := store.UpdateAlert(alert); err != nil {
+		t.Fatalf("Failed to update alert: %v", err)
+	}
+	got, err := store.GetAlert(alert.ID)
+	if err != nil || got == nil {
+		t.Fatalf("Failed to get alert: %v", err)
+	}
+	if got.Name != "VIX spike" || got.State != AlertTriggered || got.LastObservedDate != "2024-01-02" {
+		t.Errorf("Expected renamed alert to stay triggered, got %+v", got)
+	}
+
+	// A new threshold re-arms it
+	alert.Threshold = 30
+	if err := store.UpdateAlert(alert); err != nil {
+		t.Fatalf("Failed to update alert: %v", err)
+	}
+	got, _ = store.GetAlert(alert.ID)
+	if got.Threshold != 30 || got.State != AlertArmed || got.BreachCount != 0 || got.LastObservedDate != "" {
+		t.Errorf("Expected re-armed alert at the new threshold, got %+v", got)
+	}
+
+	// Pausing only applies to the owner's alert
+	if err := store.SetAlertActive(alert.ID, "b@example.com", false); err != nil {
+		t.Fatalf("Failed to pause alert: %v", err)
+	}
+	if got, _ = store.GetAlert(alert.ID); !got.IsActive {
+		t.Error("Expected alert to stay active for another user")
+	}
+	if err := store.SetAlertActive(alert.ID, "a@example.com", false); err != nil {
+		t.Fatalf("Failed to pause alert: %v", err)
+	}
+	active, _ := store.GetActiveAlerts()
+	if len(active) != 0 {
+		t.Errorf("Expected no active alerts after pausing, got %d", len(active))
+	}
+
+	if missing, err := store.GetAlert(alert.ID + 1); err != nil || missing != nil {
+		t.Errorf("Expected nil for a missing alert, got %+v, %v", missing, err)
+	}
+}
+
+func TestListAlertHistory(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	for i, value := range []float64{26, 27, 28} {
+		h := &AlertHistory{AlertID: 1, SeriesID: "VIXCLS", Value: value, Threshold: 25, WebhookStatus: "success"}
+		if i == 2 {
+			h.Deliveries = []ChannelDelivery{{Channel: "email", Target: "a@example.com", Status: "success"}}
+		}
+		if err := store.SaveAlertHistory(h); err != nil {
+			t.Fatalf("Failed to save alert history: %v", err)
+		}
+	}
+	if err := store.SaveAlertHistory(&AlertHistory{AlertID: 2, SeriesID: "DTWEXBGS", Value: 120, Threshold: 110}); err != nil {
+		t.Fatalf("Failed to save alert history: %v", err)
+	}
+
+	history, err := store.ListAlertHistory(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to list alert history: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 triggers, got %d", len(history))
+	}
+	if history[0].Value != 28 || history[1].Value != 27 {
+		t.Errorf("Expected newest first, got %.0f then %.0f", history[0].Value, history[1].Value)
+	}
+	if len(history[0].Deliveries) != 1 || history[0].Deliveries[0].Channel != "email" {
+		t.Errorf("Expected deliveries to round-trip, got %+v", history[0].Deliveries)
+	}
+}
+
+func TestSeriesCatalog(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	if err := store.SyncSeries([]Series{
+		{ID: "VIXCLS", Name: "CBOE Volatility Index (VIX)", Label: "VIX", Unit: "index", Frequency: "daily", Source: "fred"},
+		{ID: "COFER_CNY", Name: "CNY Share of Allocated FX Reserves", Unit: "percent_of_allocated_reserves", Source: "imf_cofer"},
+	}); err != nil {
+		t.Fatalf("Failed to sync series: %v", err)
+	}
+
+	if err := store.SavePoints("VIXCLS", []SeriesPoint{
+		{Date: "2024-01-02", Value: 13},
+		{Date: "2024-01-03", Value: 14},
+	}, time.Time{}); err != nil {
+		t.Fatalf("Failed to save points: %v", err)
+	}
+
+	series, err := store.ListSeries()
+	if err != nil {
+		t.Fatalf("Failed to list series: %v", err)
+	}
+	if len(series) != 2 || series[0].ID != "VIXCLS" {
+		t.Fatalf("Expected both series in sync order, got %+v", series)
+	}
+	if series[0].FirstDate != "2024-01-02" || series[0].LastDate != "2024-01-03" || series[0].Observations != 2 {
+		t.Errorf("Expected coverage from stored points, got %+v", series[0])
+	}
+	if series[1].Label != series[1].Name || series[1].Observations != 0 || series[1].FirstDate != "" {
+		t.Errorf("Expected label to default to name and no coverage, got %+v", series[1])
+	}
+
+	// Re-syncing updates entries in place
+	if err := store.SyncSeries([]Series{{ID: "VIXCLS", Name: "VIX", Unit: "index", Source: "fred"}}); err != nil {
+		t.Fatalf("Failed to re-sync series: %v", err)
+	}
+	vix, err := store.GetSeries("VIXCLS")
+	if err != nil || vix == nil || vix.Name != "VIX" {
+		t.Errorf("Expected updated VIXCLS entry, got %+v, %v", vix, err)
+	}
+	if missing, err := store.GetSeries("IMF_COFER"); err != nil || missing != nil {
+		t.Errorf("Expected no entry for an unknown ID, got %+v, %v", missing, err)
+	}
+}
+
+func TestPointVintagesAsOf(t *testing.T) {
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	if _, err := store.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	if err := store.SavePoints("COFER_CNY", []SeriesPoint{
+		{Date: "2024-03-31", Value: 2.1},
+		{Date: "2024-06-30", Value: 2.2},
+	}, time.Time{}); err != nil {
+		t.Fatalf("Failed to save points: %v", err)
+	}
+
+	before := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	// The source revises Q2 and publishes Q3
+	if err := store.SavePoints("COFER_CNY", []SeriesPoint{
+		{Date: "2024-03-31", Value: 2.1},
+		{Date: "2024-06-30", Value: 2.25},
+		{Date: "2024-09-30", Value: 2.3},
+	}, time.Time{}); err != nil {
+		t.Fatalf("Failed to save revised points: %v", err)
+	}
+
+	current, err := store.GetRecentPoints("COFER_CNY", 10)
+	if err != nil {
+		t.Fatalf("Failed to get points: %v", err)
+	}
+	if len(current) != 3 || current[1].Value != 2.25 {
+		t.Errorf("Expected current reads to include the revision, got %+v", current)
+	}
+
+	view := store.AsOf(before)
+	past, err := view.GetRecentPoints("COFER_CNY", 10)
+	if err != nil {
+		t.Fatalf("Failed to get points as of %v: %v", before, err)
+	}
+	if len(past) != 2 || past[0].Date != "2024-06-30" || past[0].Value != 2.2 {
+		t.Errorf("Expected Q2 at its original value and no Q3, got %+v", past)
+	}
+
+	latest, err := view.GetLatestPoint("COFER_CNY")
+	if err != nil || latest == nil || latest.Value != 2.2 {
+		t.Errorf("Expected latest point as of %v to be 2.2, got %+v, %v", before, latest, err)
+	}
+
+	var vintages int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM series_point_vintages WHERE series_name = 'COFER_CNY'`).Scan(&vintages); err != nil {
+		t.Fatalf("Failed to count vintages: %v", err)
+	}
+	if vintages != 4 {
+		t.Errorf("Expected 4 vintages (unchanged values aren't repeated), got %d", vintages)
+	}
+
+	if err := view.SavePoints("COFER_CNY", []SeriesPoint{{Date: "2024-12-31", Value: 2.4}}, time.Time{}); err == nil {
+		t.Error("Expected error saving through a point-in-time view")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"reserve-watch/internal/alerts"
 	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
 )
@@ -59,12 +60,8 @@ func (s *Server) handleCreateAlert(w http.ResponseWriter, r *http.Request) {
 	return
 
 	var req struct {
-		UserEmail  string  `json:"user_email"`
-		Name       string  `json:"name"`
-		SeriesID   string  `json:"series_id"`
-		Condition  string  `json:"condition"`
-		Threshold  float64 `json:"threshold"`
-		WebhookURL string  `json:"webhook_url"`
+		UserEmail string `json:"user_email"`
+		alertFields
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -73,29 +70,23 @@ func (s *Server) handleCreateAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate inputs
-	if req.UserEmail == "" || req.Name == "" || req.SeriesID == "" {
+	if req.UserEmail == "" {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "user_email, name, and series_id are required"})
 		return
 	}
 
-	if req.Condition != "above" && req.Condition != "below" {
+	alert := &store.Alert{
+		UserEmail:       req.UserEmail,
+		IsActive:        true,
+		CooldownMinutes: store.DefaultAlertCooldownMinutes,
+	}
+	if msg := s.applyAlertFields(alert, req.alertFields); msg != "" {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "condition must be 'above' or 'below'"})
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
 		return
 	}
 
-	alert := &store.Alert{
-		UserEmail:  req.UserEmail,
-		Name:       req.Name,
-		SeriesID:   req.SeriesID,
-		Condition:  req.Condition,
-		Threshold:  req.Threshold,
-		WebhookURL: req.WebhookURL,
-		IsActive:   true,
-	}
-
 	if err := s.store.CreateAlert(alert); err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
 			w.WriteHeader(http.StatusConflict)
@@ -115,18 +106,123 @@ func (s *Server) handleCreateAlert(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleDeleteAlert deletes an alert
-func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// alertFields are the editable alert fields shared by create and PATCH. Nil fields
+// are left unchanged.
+type alertFields struct {
+	Name       *string  `json:"name"`
+	SeriesID   *string  `json:"series_id"`
+	Condition  *string  `json:"condition"`
+	Threshold  *float64 `json:"threshold"`
+	Expression *string  `json:"expression"` // replaces series_id/condition/threshold, e.g. "VIXCLS > 25 AND BAMLC0A4CBBB > 300"
+	WebhookURL *string  `json:"webhook_url"`
+
+	Channels        *[]store.AlertChannel `json:"channels"`
+	RearmBand       *float64              `json:"rearm_band"`
+	Confirmations   *int                  `json:"confirmations"`
+	CooldownMinutes *int                  `json:"cooldown_minutes"`
+}
+
+// applyAlertFields validates the fields and copies them onto the alert. It returns
+// an error message for the client, or "" if the alert is valid.
+func (s *Server) applyAlertFields(alert *store.Alert, f alertFields) string {
+	if f.Name != nil {
+		alert.Name = *f.Name
+	}
+
+	if f.Expression != nil && *f.Expression != "" {
+		expr, err := alerts.ParseExpr(*f.Expression)
+		if err != nil {
+			return "Invalid expression: " + err.Error()
+		}
+		for _, id := range expr.Series() {
+			if points, err := s.store.GetRecentPoints(id, 1); err != nil || len(points) == 0 {
+				return "Unknown series in expression: " + id
+			}
+		}
+
+		// The expression is stored as the condition, keyed on the first series it reads
+		alert.SeriesID = expr.Series()[0]
+		alert.Condition = expr.String()
+		alert.Threshold = 0
+	} else {
+		// series_id and threshold don't apply to an expression alert; switching it to
+		// above/below must set both rather than inherit the expression's
+		if alerts.IsExpression(alert.Condition) {
+			if f.Condition == nil && (f.SeriesID != nil || f.Threshold != nil) {
+				return "series_id and threshold don't apply to an expression alert: change expression, or set condition to 'above' or 'below'"
+			}
+			if f.Condition != nil && (f.SeriesID == nil || f.Threshold == nil) {
+				return "switching an expression alert to 'above' or 'below' needs series_id and threshold"
+			}
+		}
+
+		if f.SeriesID != nil {
+			alert.SeriesID = *f.SeriesID
+		}
+		if f.Condition != nil {
+			if *f.Condition != "above" && *f.Condition != "below" {
+				return "condition must be 'above' or 'below', or set expression"
+			}
+			alert.Condition = *f.Condition
+		}
+		if f.Threshold != nil {
+			alert.Threshold = *f.Threshold
+		}
+	}
+
+	if alert.Name == "" || alert.SeriesID == "" {
+		return "user_email, name, and series_id are required"
+	}
+	if alert.Condition == "" {
+		return "condition must be 'above' or 'below', or set expression"
 	}
 
+	if f.WebhookURL != nil {
+		alert.WebhookURL = *f.WebhookURL
+	}
+	if f.Channels != nil {
+		for _, ch := range *f.Channels {
+			if err := alerts.ValidateChannel(ch); err != nil {
+				return err.Error()
+			}
+		}
+		alert.Channels = *f.Channels
+	}
+
+	if (f.RearmBand != nil && *f.RearmBand < 0) || (f.Confirmations != nil && *f.Confirmations < 0) ||
+		(f.CooldownMinutes != nil && *f.CooldownMinutes < 0) {
+		return "rearm_band, confirmations and cooldown_minutes can't be negative"
+	}
+	if f.RearmBand != nil {
+		alert.RearmBand = *f.RearmBand
+	}
+	if f.Confirmations != nil {
+		alert.Confirmations = *f.Confirmations
+	}
+	if alert.Confirmations < 1 {
+		alert.Confirmations = 1
+	}
+	if f.CooldownMinutes != nil {
+		alert.CooldownMinutes = *f.CooldownMinutes
+	}
+
+	return ""
+}
+
+// handleAlertAPI handles a single alert, owned by ?email={email}:
+//
+//	DELETE /api/alerts/{id}
+//	PATCH  /api/alerts/{id}
+//	POST   /api/alerts/{id}/pause
+//	POST   /api/alerts/{id}/resume
+//	GET    /api/alerts/{id}/history
+//	POST   /api/alerts/{id}/test
+func (s *Server) handleAlertAPI(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// Extract alert ID from path: /api/alerts/{id}
+	// Extract alert ID and action from path: /api/alerts/{id}[/{action}]
 	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
-	if len(parts) < 3 {
+	if len(parts) < 3 || len(parts) > 4 {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Alert ID required"})
 		return
@@ -139,6 +235,30 @@ func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	action := ""
+	if len(parts) == 4 {
+		action = parts[3]
+	}
+
+	methods := map[string]string{
+		"":        http.MethodDelete + ", " + http.MethodPatch,
+		"pause":   http.MethodPost,
+		"resume":  http.MethodPost,
+		"history": http.MethodGet,
+		"test":    http.MethodPost,
+	}
+	allowed, ok := methods[action]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unknown alert action: " + action})
+		return
+	}
+	if !strings.Contains(allowed, r.Method) {
+		w.Header().Set("Allow", allowed)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
 	email := r.URL.Query().Get("email")
 	if email == "" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -146,7 +266,36 @@ func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.store.DeleteAlert(id, email); err != nil {
+	alert, err := s.store.GetAlert(id)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load alert %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to load alert"})
+		return
+	}
+	if alert == nil || alert.UserEmail != email {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Alert not found"})
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodDelete:
+		s.handleDeleteAlert(w, alert)
+	case action == "":
+		s.handleUpdateAlert(w, r, alert)
+	case action == "pause" || action == "resume":
+		s.handleSetAlertActive(w, alert, action == "resume")
+	case action == "history":
+		s.handleAlertHistory(w, r, alert)
+	case action == "test":
+		s.handleTestAlert(w, alert)
+	}
+}
+
+// handleDeleteAlert deletes an alert
+func (s *Server) handleDeleteAlert(w http.ResponseWriter, alert *store.Alert) {
+	if err := s.store.DeleteAlert(alert.ID, alert.UserEmail); err != nil {
 		util.ErrorLogger.Printf("Failed to delete alert: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to delete alert"})
@@ -155,3 +304,216 @@ func (s *Server) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(map[string]string{"message": "Alert deleted successfully"})
 }
+
+// handleUpdateAlert applies a partial update. Changing what the alert watches
+// restarts its state machine.
+func (s *Server) handleUpdateAlert(w http.ResponseWriter, r *http.Request, alert *store.Alert) {
+	var req alertFields
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body"})
+		return
+	}
+
+	if msg := s.applyAlertFields(alert, req); msg != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
+		return
+	}
+
+	if err := s.store.UpdateAlert(alert); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Alert with same parameters already exists"})
+			return
+		}
+
+		util.ErrorLogger.Printf("Failed to update alert %d: %v", alert.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update alert"})
+		return
+	}
+
+	s.writeAlert(w, alert.ID)
+}
+
+// handleSetAlertActive pauses or resumes an alert
+func (s *Server) handleSetAlertActive(w http.ResponseWriter, alert *store.Alert, active bool) {
+	if err := s.store.SetAlertActive(alert.ID, alert.UserEmail, active); err != nil {
+		util.ErrorLogger.Printf("Failed to update alert %d: %v", alert.ID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to update alert"})
+		return
+	}
+
+	s.writeAlert(w, alert.ID)
+}
+
+// writeAlert responds with the alert as stored
+func (s *Server) writeAlert(w http.ResponseWriter, id int64) {
+	alert, err := s.store.GetAlert(id)
+	if err != nil || alert == nil {
+		util.ErrorLogger.Printf("Failed to reload alert %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to load alert"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alert": alert,
+	})
+}
+
+// handleAlertHistory lists an alert's triggers, newest first
+func (s *Server) handleAlertHistory(w http.ResponseWriter, r *http.Request, alert *store.Alert) {
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	history, err := s.store.ListAlertHistory(alert.ID, limit)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to list alert history: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to list alert history"})
+		return
+	}
+	if history == nil {
+		history = []store.AlertHistory{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"alert_id": alert.ID,
+		"history":  history,
+		"count":    len(history),
+	})
+}
+
+// handleTestAlert sends a test notification to the alert's channels without
+// recording a trigger
+func (s *Server) handleTestAlert(w http.ResponseWriter, alert *store.Alert) {
+	if len(alerts.Channels(alert)) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Alert has no delivery channels"})
+		return
+	}
+
+	deliveries, err := alerts.SendTest(s.store, s.notifiers, alert)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Can't evaluate alert: " + err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+	})
+}
+
+// handleDeliveriesAPI lists a user's webhook deliveries: GET /api/deliveries?email={email}
+func (s *Server) handleDeliveriesAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	email := q.Get("email")
+	if email == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "email parameter required"})
+		return
+	}
+
+	filter := store.WebhookDeliveryFilter{UserEmail: email, Status: q.Get("status"), Limit: 100}
+	if id, err := strconv.ParseInt(q.Get("alert_id"), 10, 64); err == nil {
+		filter.AlertID = id
+	}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 && limit <= 1000 {
+		filter.Limit = limit
+	}
+
+	deliveries, err := s.store.ListWebhookDeliveries(filter)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to list webhook deliveries: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to list deliveries"})
+		return
+	}
+	if deliveries == nil {
+		deliveries = []store.WebhookDelivery{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deliveries": deliveries,
+		"count":      len(deliveries),
+	})
+}
+
+// handleReplayDelivery re-sends a logged webhook: POST /api/deliveries/{id}/replay?email={email}
+func (s *Server) handleReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 4 || parts[3] != "replay" {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Use POST /api/deliveries/{id}/replay"})
+		return
+	}
+
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid delivery ID"})
+		return
+	}
+
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "email parameter required"})
+		return
+	}
+
+	delivery, err := s.store.GetWebhookDelivery(id)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load webhook delivery %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to load delivery"})
+		return
+	}
+	if delivery == nil || !s.ownsAlert(email, delivery.AlertID) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Delivery not found"})
+		return
+	}
+
+	replay, err := alerts.ReplayDelivery(s.store, id)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to replay webhook delivery %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to replay delivery"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"delivery": replay,
+	})
+}
+
+// ownsAlert reports whether an alert belongs to the user
+func (s *Server) ownsAlert(email string, alertID int64) bool {
+	alert, err := s.store.GetAlert(alertID)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load alert %d: %v", alertID, err)
+		return false
+	}
+	return alert != nil && alert.UserEmail == email
+}
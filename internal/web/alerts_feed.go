@@ -1,24 +1,256 @@
 package web
 
 import (
+	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
 )
 
+// alertsFeedPageSize is the number of events per alerts feed page
+const alertsFeedPageSize = 20
+
+// FeedItem is a feed event formatted for the alerts page and the RSS/Atom/JSON feeds
+type FeedItem struct {
+	ID         string
+	Path       string // permalink
+	Title      string
+	Name       string // series display name
+	Status     string // crisis, watch, neutral or good
+	StatusName string
+	Reason     string
+	Change     string
+	When       string // relative, e.g. "2 hours ago"
+	Detail     string
+	Time       time.Time
+}
+
+// FeedOption is a choice in the alerts feed filter dropdowns
+type FeedOption struct {
+	Value    string
+	Label    string
+	Selected bool
+}
+
+// describeFeedEvent turns a stored event into display text
+func describeFeedEvent(ev store.FeedEvent, rs *analytics.RuleSet) FeedItem {
+	name := ev.SeriesID
+	if rule, ok := rs.Rule(ev.SeriesID); ok && rule.Name != "" {
+		name = rule.Name
+	}
+
+	item := FeedItem{
+		ID:         ev.ID,
+		Path:       "/alerts-feed/" + ev.ID,
+		Name:       name,
+		Status:     ev.Status,
+		StatusName: statusName(ev.Status),
+		Reason:     ev.Why,
+		When:       timeAgo(ev.OccurredAt),
+		Time:       ev.OccurredAt,
+	}
+
+	item.Title = feedEmoji(ev.Status) + " " + name
+	item.Change = "First recorded as " + statusName(ev.Status)
+	if ev.FromStatus != "" {
+		item.Change = "Changed from " + statusName(ev.FromStatus) + " to " + statusName(ev.Status)
+	}
+	if ev.Rule != "" {
+		item.Change += " • rule " + ev.Rule
+	}
+	item.Detail = fmt.Sprintf("Triggered at %s (data as of %s)", formatFloat(ev.Value, 2), ev.AsOf)
+	return item
+}
+
+// feedFilter reads the series/status/page query params shared by the page and the feeds
+func feedFilter(r *http.Request) (store.FeedFilter, int) {
+	q := r.URL.Query()
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	return store.FeedFilter{
+		SeriesID: q.Get("series"),
+		Status:   q.Get("status"),
+		Limit:    alertsFeedPageSize,
+		Offset:   (page - 1) * alertsFeedPageSize,
+	}, page
+}
+
+// loadFeedItems lists one page of events; more reports whether another page follows
+func (s *Server) loadFeedItems(filter store.FeedFilter) (items []FeedItem, more bool, err error) {
+	filter.Limit++
+	events, err := s.store.ListFeedEvents(filter)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(events) == filter.Limit {
+		more = true
+		events = events[:len(events)-1]
+	}
+
+	rs := analytics.Rules()
+	items = make([]FeedItem, len(events))
+	for i, ev := range events {
+		items[i] = describeFeedEvent(ev, rs)
+	}
+	return items, more, nil
+}
+
 func (s *Server) handleAlertsFeed(w http.ResponseWriter, r *http.Request) {
+	if id := strings.TrimPrefix(r.URL.Path, "/alerts-feed/"); id != r.URL.Path && id != "" {
+		s.handleAlertsFeedEvent(w, r, id)
+		return
+	}
+
+	filter, page := feedFilter(r)
+	items, more, err := s.loadFeedItems(filter)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load alerts feed: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pageURL := func(page int) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		return "/alerts-feed?" + q.Encode()
+	}
+
+	filterQuery := url.Values{}
+	if filter.SeriesID != "" {
+		filterQuery.Set("series", filter.SeriesID)
+	}
+	if filter.Status != "" {
+		filterQuery.Set("status", filter.Status)
+	}
+
+	data := alertsFeedData{
+		Items:       items,
+		Page:        page,
+		FeedQuery:   template.URL(filterQuery.Encode()),
+		SeriesOpts:  []FeedOption{{Value: "", Label: "All series", Selected: filter.SeriesID == ""}},
+		StatusOpts:  []FeedOption{{Value: "", Label: "All statuses", Selected: filter.Status == ""}},
+		Filtered:    filter.SeriesID != "" || filter.Status != "",
+		Description: "Every signal status change, newest first",
+	}
+	for _, rule := range analytics.Rules().Series {
+		data.SeriesOpts = append(data.SeriesOpts, FeedOption{Value: rule.SeriesID, Label: rule.Name, Selected: rule.SeriesID == filter.SeriesID})
+	}
+	for _, status := range []string{"crisis", "watch", "neutral", "good"} {
+		data.StatusOpts = append(data.StatusOpts, FeedOption{Value: status, Label: statusName(status), Selected: status == filter.Status})
+	}
+	if page > 1 {
+		data.PrevURL = pageURL(page - 1)
+	}
+	if more {
+		data.NextURL = pageURL(page + 1)
+	}
+
+	s.renderAlertsFeed(w, data)
+}
+
+// handleAlertsFeedEvent renders the permalink page for one event
+func (s *Server) handleAlertsFeedEvent(w http.ResponseWriter, r *http.Request, id string) {
+	ev, err := s.store.GetFeedEvent(id)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load feed event %s: %v", id, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if ev == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	item := describeFeedEvent(*ev, analytics.Rules())
+	s.renderAlertsFeed(w, alertsFeedData{
+		Items:       []FeedItem{item},
+		Permalink:   true,
+		Description: item.Title + " — " + item.Time.UTC().Format("Jan 2, 2006 15:04 UTC"),
+	})
+}
+
+type alertsFeedData struct {
+	Items       []FeedItem
+	Permalink   bool
+	Description string
+	Page        int
+	PrevURL     string
+	NextURL     string
+	FeedQuery   template.URL
+	SeriesOpts  []FeedOption
+	StatusOpts  []FeedOption
+	Filtered    bool
+}
+
+func (s *Server) renderAlertsFeed(w http.ResponseWriter, data alertsFeedData) {
 	tmpl := template.Must(template.New("alerts-feed").Parse(alertsFeedTemplate))
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := tmpl.Execute(w, nil); err != nil {
+	if err := tmpl.Execute(w, data); err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
 
+// statusName capitalizes a status for display, e.g. "watch" -> "Watch"
+func statusName(status string) string {
+	if status == "" {
+		return ""
+	}
+	return strings.ToUpper(status[:1]) + status[1:]
+}
+
+func feedEmoji(status string) string {
+	switch status {
+	case "crisis":
+		return "🚨"
+	case "watch":
+		return "⚠️"
+	case "good":
+		return "✅"
+	}
+	return "ℹ️"
+}
+
+// timeAgo formats how long ago t was, e.g. "3 hours ago"
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Hour:
+		return "just now"
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour") + " ago"
+	case d < 14*24*time.Hour:
+		return plural(int(d.Hours()/24), "day") + " ago"
+	default:
+		return plural(int(d.Hours()/24/7), "week") + " ago"
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
 const alertsFeedTemplate = `<!DOCTYPE html>
 <html lang="en">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>Recent Alerts - Reserve Watch</title>
+    <meta name="description" content="{{.Description}}">
+    <link rel="alternate" type="application/rss+xml" title="Reserve Watch Alerts (RSS)" href="/alerts-feed.rss{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">
+    <link rel="alternate" type="application/atom+xml" title="Reserve Watch Alerts (Atom)" href="/alerts-feed.atom{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">
+    <link rel="alternate" type="application/feed+json" title="Reserve Watch Alerts (JSON Feed)" href="/alerts-feed.json{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">
     <style>
         * { margin: 0; padding: 0; box-sizing: border-box; }
         body {
@@ -67,6 +299,10 @@ const alertsFeedTemplate = `<!DOCTYPE html>
             border-left-color: #10b981;
             background: rgba(16,185,129,0.05);
         }
+        .alert-neutral {
+            border-left-color: #667eea;
+            background: rgba(102,126,234,0.05);
+        }
         .alert-header {
             display: flex;
             justify-content: space-between;
@@ -97,6 +333,10 @@ const alertsFeedTemplate = `<!DOCTYPE html>
             background: rgba(16,185,129,0.2);
             color: #10b981;
         }
+        .badge-neutral {
+            background: rgba(102,126,234,0.2);
+            color: #667eea;
+        }
         .alert-reason {
             font-size: 1em;
             line-height: 1.6;
@@ -140,6 +380,49 @@ const alertsFeedTemplate = `<!DOCTYPE html>
         .cta-button:hover {
             transform: translateY(-2px);
         }
+        .alert-title a {
+            color: inherit;
+            text-decoration: none;
+        }
+        .alert-title a:hover {
+            text-decoration: underline;
+        }
+        .feed-filters {
+            display: flex;
+            gap: 10px;
+            flex-wrap: wrap;
+            justify-content: center;
+            margin-bottom: 30px;
+        }
+        .feed-filters select, .feed-filters button {
+            padding: 8px 14px;
+            border-radius: 8px;
+            border: 1px solid rgba(255,255,255,0.2);
+            background: rgba(255,255,255,0.08);
+            color: #e0e0e0;
+            font-size: 0.95em;
+        }
+        .feed-filters button {
+            background: #667eea;
+            color: white;
+            cursor: pointer;
+        }
+        .feed-links {
+            text-align: center;
+            font-size: 0.9em;
+            margin-bottom: 25px;
+        }
+        .feed-links a, .pagination a {
+            color: #667eea;
+            text-decoration: none;
+            font-weight: 600;
+            margin: 0 8px;
+        }
+        .pagination {
+            display: flex;
+            justify-content: space-between;
+            margin-top: 10px;
+        }
         .back-link {
             display: inline-block;
             color: #667eea;
@@ -157,78 +440,59 @@ const alertsFeedTemplate = `<!DOCTYPE html>
         <a href="/" class="back-link">← Back to Dashboard</a>
         
         <h1>🔔 Recent Alerts</h1>
-        <p class="subtitle">See what signals have fired in the last 30 days</p>
-
-        <!-- Sample alerts - in production these would be dynamically generated -->
-        <div class="alert-card alert-crisis">
-            <div class="alert-header">
-                <div class="alert-title">🚨 USD Index (DXY)</div>
-                <span class="alert-badge badge-crisis">Crisis</span>
-            </div>
-            <div class="alert-reason">
-                Strong dollar at elevated levels (106.5). EM borrowers face stress, import costs rising.
-            </div>
-            <div class="alert-action">
-                → Action: Review FX hedges, check EM exposure
-            </div>
-            <div class="alert-time">2 days ago • Triggered at 106.42</div>
-        </div>
+        <p class="subtitle">{{.Description}}</p>
 
-        <div class="alert-card alert-watch">
-            <div class="alert-header">
-                <div class="alert-title">⚠️ VIX</div>
-                <span class="alert-badge badge-watch">Watch</span>
-            </div>
-            <div class="alert-reason">
-                Volatility index at 22 (above 20 watch threshold). Market uncertainty rising.
-            </div>
-            <div class="alert-action">
-                → Action: Review Trigger Watch checklist
-            </div>
-            <div class="alert-time">5 days ago • Triggered at 22.1</div>
+        {{if .Permalink}}
+        <a href="/alerts-feed" class="back-link">← All alerts</a>
+        {{else}}
+        <form class="feed-filters" method="get" action="/alerts-feed">
+            <select name="series" aria-label="Series">
+                {{range .SeriesOpts}}<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Label}}</option>{{end}}
+            </select>
+            <select name="status" aria-label="Status">
+                {{range .StatusOpts}}<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Label}}</option>{{end}}
+            </select>
+            <button type="submit">Filter</button>
+        </form>
+        <div class="feed-links">
+            Subscribe:
+            <a href="/alerts-feed.rss{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">RSS</a>
+            <a href="/alerts-feed.atom{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">Atom</a>
+            <a href="/alerts-feed.json{{if .FeedQuery}}?{{.FeedQuery}}{{end}}">JSON Feed</a>
         </div>
+        {{end}}
 
-        <div class="alert-card alert-crisis">
+        {{range .Items}}
+        <div class="alert-card alert-{{.Status}}">
             <div class="alert-header">
-                <div class="alert-title">🚨 BBB OAS Spread</div>
-                <span class="alert-badge badge-crisis">Crisis</span>
+                <div class="alert-title"><a href="{{.Path}}">{{.Title}}</a></div>
+                <span class="alert-badge badge-{{.Status}}">{{.StatusName}}</span>
             </div>
+            {{if .Reason}}
             <div class="alert-reason">
-                BBB credit spreads widening past 200 bps. Corporate borrowing stress increasing.
+                {{.Reason}}
             </div>
+            {{end}}
             <div class="alert-action">
-                → Action: Run Crash-Drill checklist, review credit exposure
+                → {{.Change}}
             </div>
-            <div class="alert-time">1 week ago • Triggered at 215 bps</div>
+            <div class="alert-time">{{.When}} • {{.Detail}}</div>
         </div>
-
-        <div class="alert-card alert-watch">
-            <div class="alert-header">
-                <div class="alert-title">⚠️ SWIFT RMB Payments</div>
-                <span class="alert-badge badge-watch">Watch</span>
-            </div>
+        {{else}}
+        <div class="alert-card alert-good">
             <div class="alert-reason">
-                RMB payment share approaching 3% watch threshold. De-dollarization trend accelerating.
-            </div>
-            <div class="alert-action">
-                → Action: Monitor settlement shift trends
+                {{if .Filtered}}No events match these filters.{{else}}No signal has changed status yet.{{end}}
             </div>
-            <div class="alert-time">2 weeks ago • Triggered at 2.9%</div>
         </div>
+        {{end}}
 
-        <div class="alert-card alert-good">
-            <div class="alert-header">
-                <div class="alert-title">✅ Central Bank Gold</div>
-                <span class="alert-badge badge-good">Good</span>
-            </div>
-            <div class="alert-reason">
-                CB gold purchases normalized to moderate levels. Diversification pace steady.
-            </div>
-            <div class="alert-action">
-                → No immediate action required
-            </div>
-            <div class="alert-time">3 weeks ago</div>
+        {{if or .PrevURL .NextURL}}
+        <div class="pagination">
+            <span>{{if .PrevURL}}<a href="{{.PrevURL}}">← Newer</a>{{end}}</span>
+            <span>Page {{.Page}}</span>
+            <span>{{if .NextURL}}<a href="{{.NextURL}}">Older →</a>{{end}}</span>
         </div>
+        {{end}}
 
         <!-- CTA Box -->
         <div class="cta-box">
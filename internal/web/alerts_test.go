@@ -0,0 +1,185 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"reserve-watch/internal/alerts"
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+	"reserve-watch/migrations"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	util.InitLogger("info")
+	db, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Migrate(migrations.FS); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+	for series, value := range map[string]float64{"VIXCLS": 20, "BAMLC0A4CBBB": 280} {
+		if err := db.SavePoints(series, []store.SeriesPoint{{Date: "2024-01-02", Value: value}}, time.Now()); err != nil {
+			t.Fatalf("Failed to save point: %v", err)
+		}
+	}
+	return NewServer(db, "0", "", "", "", alerts.Notifiers{})
+}
+
+func createTestAlert(t *testing.T, s *Server, alert *store.Alert) *store.Alert {
+	t.Helper()
+	alert.UserEmail, alert.Name, alert.IsActive = "a@example.com", "Test alert", true
+	if err := s.store.CreateAlert(alert); err != nil {
+		t.Fatalf("Failed to create alert: %v", err)
+	}
+	return alert
+}
+
+// alertRequest calls the single-alert API and decodes the JSON response into out
+func alertRequest(t *testing.T, s *Server, method, target, body string, out interface{}) int {
+	t.Helper()
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	s.handleAlertAPI(w, r)
+	if out != nil && w.Code < 300 {
+		if err := json.NewDecoder(w.Body).Decode(out); err != nil {
+			t.Fatalf("Failed to decode %s %s response: %v", method, target, err)
+		}
+	}
+	return w.Code
+}
+
+func TestPatchThresholdAlert(t *testing.T) {
+	s := newTestServer(t)
+	alert := createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "above", Threshold: 25})
+
+	var resp struct{ Alert store.Alert }
+	code := alertRequest(t, s, http.MethodPatch, "/api/alerts/1?email=a@example.com", `{"threshold": 30, "cooldown_minutes": 120}`, &resp)
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if resp.Alert.ID != alert.ID || resp.Alert.Threshold != 30 || resp.Alert.CooldownMinutes != 120 || resp.Alert.Condition != "above" {
+		t.Errorf("Expected threshold 30 and cooldown 120 with the condition kept, got %+v", resp.Alert)
+	}
+
+	if code := alertRequest(t, s, http.MethodPatch, "/api/alerts/1?email=a@example.com", `{"condition": "sideways"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown condition, got %d", code)
+	}
+	if code := alertRequest(t, s, http.MethodPatch, "/api/alerts/1?email=a@example.com", `{"expression": "1 > 0"}`, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an expression reading no series, got %d", code)
+	}
+}
+
+func TestPatchExpressionAlert(t *testing.T) {
+	s := newTestServer(t)
+	createTestAlert(t, s, &store.Alert{SeriesID: "BAMLC0A4CBBB", Condition: "VIXCLS > 25 AND BAMLC0A4CBBB > 300"})
+
+	for _, body := range []string{`{"threshold": 30}`, `{"series_id": "VIXCLS"}`, `{"condition": "above", "threshold": 30}`} {
+		if code := alertRequest(t, s, http.MethodPatch, "/api/alerts/1?email=a@example.com", body, nil); code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s on an expression alert, got %d", body, code)
+		}
+	}
+	stored, _ := s.store.GetAlert(1)
+	if stored.Condition != "VIXCLS > 25 AND BAMLC0A4CBBB > 300" {
+		t.Errorf("Expected rejected edits to leave the expression, got %q", stored.Condition)
+	}
+
+	var resp struct{ Alert store.Alert }
+	code := alertRequest(t, s, http.MethodPatch, "/api/alerts/1?email=a@example.com", `{"condition": "above", "series_id": "VIXCLS", "threshold": 30}`, &resp)
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200 switching to a threshold alert, got %d", code)
+	}
+	if resp.Alert.Condition != "above" || resp.Alert.SeriesID != "VIXCLS" || resp.Alert.Threshold != 30 {
+		t.Errorf("Expected VIXCLS above 30, got %+v", resp.Alert)
+	}
+}
+
+func TestPauseResumeAlert(t *testing.T) {
+	s := newTestServer(t)
+	createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "above", Threshold: 25})
+
+	var resp struct{ Alert store.Alert }
+	if code := alertRequest(t, s, http.MethodPost, "/api/alerts/1/pause?email=a@example.com", "", &resp); code != http.StatusOK || resp.Alert.IsActive {
+		t.Errorf("Expected the alert to be paused, got %d %+v", code, resp.Alert)
+	}
+	if code := alertRequest(t, s, http.MethodPost, "/api/alerts/1/resume?email=a@example.com", "", &resp); code != http.StatusOK || !resp.Alert.IsActive {
+		t.Errorf("Expected the alert to be resumed, got %d %+v", code, resp.Alert)
+	}
+	if code := alertRequest(t, s, http.MethodGet, "/api/alerts/1/pause?email=a@example.com", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET pause, got %d", code)
+	}
+}
+
+func TestAlertHistoryLimit(t *testing.T) {
+	s := newTestServer(t)
+	alert := createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "above", Threshold: 25})
+	for i := 0; i < 3; i++ {
+		h := &store.AlertHistory{AlertID: alert.ID, SeriesID: "VIXCLS", Value: float64(26 + i), Threshold: 25, WebhookStatus: "skipped"}
+		if err := s.store.SaveAlertHistory(h); err != nil {
+			t.Fatalf("Failed to save history: %v", err)
+		}
+	}
+
+	var resp struct {
+		History []store.AlertHistory
+		Count   int
+	}
+	if code := alertRequest(t, s, http.MethodGet, "/api/alerts/1/history?email=a@example.com&limit=2", "", &resp); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if resp.Count != 2 || len(resp.History) != 2 || resp.History[0].Value != 28 {
+		t.Errorf("Expected the 2 newest triggers, got %+v", resp.History)
+	}
+}
+
+func TestTestAlert(t *testing.T) {
+	s := newTestServer(t)
+	createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "above", Threshold: 25})
+	if code := alertRequest(t, s, http.MethodPost, "/api/alerts/1/test?email=a@example.com", "", nil); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an alert without channels, got %d", code)
+	}
+
+	createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "below", Threshold: 15,
+		Channels: []store.AlertChannel{{Type: alerts.ChannelEmail, Target: "a@example.com"}}})
+	var resp struct{ Deliveries []store.ChannelDelivery }
+	if code := alertRequest(t, s, http.MethodPost, "/api/alerts/2/test?email=a@example.com", "", &resp); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(resp.Deliveries) != 1 || resp.Deliveries[0].Status != "skipped" {
+		t.Errorf("Expected one skipped email delivery without a notifier, got %+v", resp.Deliveries)
+	}
+}
+
+func TestAlertAPIOwnership(t *testing.T) {
+	s := newTestServer(t)
+	createTestAlert(t, s, &store.Alert{SeriesID: "VIXCLS", Condition: "above", Threshold: 25})
+
+	requests := []struct{ method, target, body string }{
+		{http.MethodPatch, "/api/alerts/1?email=b@example.com", `{"threshold": 30}`},
+		{http.MethodDelete, "/api/alerts/1?email=b@example.com", ""},
+		{http.MethodPost, "/api/alerts/1/pause?email=b@example.com", ""},
+		{http.MethodPost, "/api/alerts/1/resume?email=b@example.com", ""},
+		{http.MethodGet, "/api/alerts/1/history?email=b@example.com", ""},
+		{http.MethodPost, "/api/alerts/1/test?email=b@example.com", ""},
+		{http.MethodPatch, "/api/alerts/99?email=a@example.com", `{"threshold": 30}`},
+	}
+	for _, req := range requests {
+		if code := alertRequest(t, s, req.method, req.target, req.body, nil); code != http.StatusNotFound {
+			t.Errorf("Expected 404 for %s %s, got %d", req.method, req.target, code)
+		}
+	}
+
+	stored, _ := s.store.GetAlert(1)
+	if stored == nil || stored.Threshold != 25 || !stored.IsActive {
+		t.Errorf("Expected the alert to be untouched, got %+v", stored)
+	}
+}
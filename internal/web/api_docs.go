@@ -103,6 +103,11 @@ const apiDocsTemplate = `<!DOCTYPE html>
             color: white;
         }
         
+        .method-patch {
+            background: #f59e0b;
+            color: white;
+        }
+        
         .endpoint-path {
             font-family: 'Courier New', monospace;
             color: #667eea;
@@ -211,8 +216,8 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
                 <tr><td>series_id</td><td>string</td><td>Unique series identifier</td></tr>
                 <tr><td>date</td><td>string</td><td>Date of data point</td></tr>
                 <tr><td>value</td><td>float64</td><td>Numeric value</td></tr>
-                <tr><td>source_updated_at</td><td>string</td><td>When source published data</td></tr>
-                <tr><td>ingested_at</td><td>string</td><td>When we fetched the data</td></tr>
+                <tr><td>source_updated_at</td><td>string</td><td>When the source last published or revised the series (RFC 3339); null if the source doesn't report it</td></tr>
+                <tr><td>ingested_at</td><td>string</td><td>When we last fetched the observation (RFC 3339)</td></tr>
             </table>
             <h4>Example Response</h4>
             <pre><code>[
@@ -220,8 +225,8 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
     "series_id": "DTWEXBGS",
     "date": "2025-10-27",
     "value": 121.45,
-    "source_updated_at": "2025-10-27",
-    "ingested_at": "2025-10-27T06:00:00Z"
+    "source_updated_at": "2025-10-27T20:17:02Z",
+    "ingested_at": "2025-10-28T06:00:04Z"
   }
 ]</code></pre>
         </div>
@@ -234,22 +239,98 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
   "series_id": "DXY_REALTIME",
   "date": "2025-10-27",
   "value": 104.23,
-  "source_updated_at": "2025-10-27 09:45",
+  "source_updated_at": "2025-10-27T09:45:00Z",
   "ingested_at": "2025-10-27T09:45:12Z"
 }</code></pre>
         </div>
 
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/series</span></h3>
+            <p>List every series in the catalog with its metadata and stored coverage. Filter with <code>?source=fred</code>.</p>
+            <h4>Response Fields</h4>
+            <table>
+                <tr><th>Field</th><th>Type</th><th>Description</th></tr>
+                <tr><td>id</td><td>string</td><td>Series ID used by every other endpoint</td></tr>
+                <tr><td>name, label</td><td>string</td><td>Full and short names</td></tr>
+                <tr><td>unit, frequency</td><td>string</td><td>e.g. <code>percent_of_allocated_reserves</code>, <code>quarterly</code></td></tr>
+                <tr><td>source, provider</td><td>string</td><td>Ingest source and the publisher credited</td></tr>
+                <tr><td>license, license_url</td><td>string</td><td>Terms the data is used under</td></tr>
+                <tr><td>link</td><td>string</td><td>The series on the provider's site</td></tr>
+                <tr><td>first_date, last_date</td><td>string</td><td>Stored coverage; empty before the first observation</td></tr>
+                <tr><td>observations</td><td>int</td><td>Stored observation count</td></tr>
+            </table>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/series/{id}</span></h3>
+            <p>Get one catalog entry (404 for unknown IDs)</p>
+            <h4>Example Response</h4>
+            <pre><code>{
+  "id": "COFER_CNY",
+  "name": "CNY Share of Allocated FX Reserves",
+  "label": "COFER CNY",
+  "unit": "percent_of_allocated_reserves",
+  "frequency": "quarterly",
+  "source": "imf_cofer",
+  "provider": "IMF COFER",
+  "license": "IMF terms of use; cite International Monetary Fund, COFER",
+  "license_url": "https://www.imf.org/external/terms.htm",
+  "link": "https://data.imf.org/?sk=E6A5F467-C14B-4AA8-9F6D-5A09EC4E62A4",
+  "first_date": "2016-12-31",
+  "last_date": "2025-06-30",
+  "observations": 35
+}</code></pre>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/series/{id}/observations</span></h3>
+            <p>Observations of a series, oldest first, optionally resampled and transformed. Pass several comma-separated IDs (up to 10) to get them aligned on a common date axis, with <code>null</code> where a series has no observation on a date.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>start, end</td><td>string</td><td>Date range, YYYY-MM-DD, inclusive (default: all)</td></tr>
+                <tr><td>limit</td><td>int</td><td>Observations per page (default: 1000, max: 10000)</td></tr>
+                <tr><td>cursor</td><td>string</td><td><code>next_cursor</code> of the previous page; <code>null</code> on the last page</td></tr>
+                <tr><td>frequency</td><td>string</td><td><code>weekly</code> (ending Friday), <code>monthly</code> or <code>quarterly</code>, dated at the end of each period</td></tr>
+                <tr><td>aggregation</td><td>string</td><td><code>last</code> (default), <code>mean</code> or <code>sum</code> of each period</td></tr>
+                <tr><td>transform</td><td>string</td><td><code>pct_change</code> (from the previous observation), <code>yoy</code> (% change from a year earlier), <code>log</code> or <code>index</code> (first observation in range = 100)</td></tr>
+                <tr><td>as_of</td><td>string</td><td>Point in time, as for <code>/api/history</code></td></tr>
+            </table>
+            <h4>Example</h4>
+            <pre><code>curl "https://web-production-4c1d00.up.railway.app/api/series/DTWEXBGS/observations?start=2024-01-01&frequency=monthly&aggregation=mean&transform=yoy"
+curl "https://web-production-4c1d00.up.railway.app/api/series/DTWEXBGS,VIXCLS/observations?start=2025-01-01&limit=2"</code></pre>
+            <h4>Example Response</h4>
+            <pre><code>{
+  "series": ["DTWEXBGS", "VIXCLS"],
+  "start": "2025-01-01",
+  "end": "",
+  "frequency": "",
+  "aggregation": "",
+  "transform": "",
+  "as_of": null,
+  "count": 2,
+  "observations": [
+    {"date": "2025-01-02", "values": {"DTWEXBGS": 129.5, "VIXCLS": 17.93}},
+    {"date": "2025-01-03", "values": {"DTWEXBGS": 129.3, "VIXCLS": null}}
+  ],
+  "next_cursor": "2025-01-03"
+}</code></pre>
+            <p>With a single series, each observation is <code>{"date": ..., "value": ...}</code>.</p>
+        </div>
+
         <div class="endpoint">
             <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/history?series={id}&limit={n}</span></h3>
-            <p>Get historical data for a specific series</p>
+            <p>Get historical data for a specific series. Sources revise past observations; every value an observation has had is kept, so <code>as_of</code> returns the data exactly as we had recorded it at that time. Values are only kept from when vintage tracking was added: observations stored before then are dated when they were last fetched, so an earlier <code>as_of</code> leaves them out.</p>
             <h4>Parameters</h4>
             <table>
                 <tr><th>Param</th><th>Type</th><th>Description</th></tr>
                 <tr><td>series</td><td>string</td><td>Series ID (e.g., DTWEXBGS, COFER_CNY)</td></tr>
                 <tr><td>limit</td><td>int</td><td>Number of points to return (default: 30, max: 365)</td></tr>
+                <tr><td>as_of</td><td>string</td><td>Point in time, RFC3339 or YYYY-MM-DD (end of that day, UTC). Omit for current values. History recorded before vintage tracking starts at its last fetch</td></tr>
             </table>
             <h4>Example</h4>
-            <pre><code>curl "https://web-production-4c1d00.up.railway.app/api/history?series=DTWEXBGS&limit=90"</code></pre>
+            <pre><code>curl "https://web-production-4c1d00.up.railway.app/api/history?series=DTWEXBGS&limit=90"
+curl "https://web-production-4c1d00.up.railway.app/api/history?series=COFER_CNY&as_of=2025-04-01"</code></pre>
         </div>
 
         <div class="endpoint">
@@ -259,6 +340,7 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
             <pre><code>[
   {
     "name": "RMB Penetration Score",
+    "series_id": "IDX_RMB_PENETRATION",
     "value": 8.48,
     "description": "RMB's penetration into global finance",
     "method": "Equal-weight average of normalized components...",
@@ -270,32 +352,149 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
     "components_detailed": {
       "payments": {
         "raw_value": 2.88,
-        "normalized": 5.87,
-        "baseline": "USD SWIFT share ~49.1%"
+        "baseline": 49.1,
+        "baseline_date": "2024-11-30",
+        "baseline_source": "series",
+        "normalized": 5.87
       }
     },
-    "timestamp": "2025-10-27T12:00:00Z"
+    "baselines": {
+      "swift_share_usd": {"series_id": "SWIFT_USD", "value": 49.1, "date": "2024-11-30", "source": "series"},
+      "cofer_share_usd": {"series_id": "COFER_USD", "value": 57.7, "date": "2024-09-30", "source": "fallback"}
+    },
+    "timestamp": "2025-10-27T12:00:00Z",
+    "previous_value": 8.31,
+    "previous_date": "2025-08-31",
+    "trend": "stable"
   }
 ]</code></pre>
         </div>
 
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/indices/history</span></h3>
+            <p>Get each index as of every date since 2016. Components are aligned by carrying their latest release forward (SWIFT up to 92 days, COFER and WGC up to 190 days); dates with a stale component are left out.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>index</td><td>string</td><td>IDX_RMB_PENETRATION or IDX_DIVERSIFICATION_PRESSURE (default: both)</td></tr>
+                <tr><td>start</td><td>date</td><td>First date, YYYY-MM-DD (default: 2016-01-01)</td></tr>
+                <tr><td>end</td><td>date</td><td>Last date, YYYY-MM-DD (default: latest)</td></tr>
+            </table>
+            <h4>Example</h4>
+            <pre><code>curl "https://web-production-4c1d00.up.railway.app/api/indices/history?index=IDX_RMB_PENETRATION&start=2020-01-01"</code></pre>
+            <h4>Response</h4>
+            <pre><code>{
+  "indices": {
+    "IDX_RMB_PENETRATION": [
+      {
+        "date": "2024-09-30",
+        "value": 8.48,
+        "components": {"swift_payment_share_rmb": 4.69, "cofer_reserve_share_rmb": 2.14, "cips_participants": 1467}
+      }
+    ]
+  },
+  "start": "2020-01-01",
+  "end": "",
+  "count": 1
+}</code></pre>
+        </div>
+
         <div class="endpoint">
             <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/signals/latest</span></h3>
-            <p>Get signal analysis (Good/Watch/Crisis) for all 7 indicators</p>
+            <p>Get signal analysis (Good/Watch/Crisis) for all 7 indicators. Each signal is classified by its level, then escalated if a rate-of-change, momentum or z-score rule fires; <code>rule</code> names the rule that set the status. Pass <code>as_of</code> (RFC3339 or YYYY-MM-DD) to evaluate the signals on the data recorded by then, e.g. to reproduce an alert; as for <code>/api/history</code>, data from before vintage tracking only counts from its last fetch.</p>
             <h4>Response</h4>
             <pre><code>{
   "dtwexbgs": {
-    "series_id": "dtwexbgs",
+    "series_id": "DTWEXBGS",
     "value": 121.45,
     "as_of": "2025-10-27",
-    "status": "good",
-    "why": "USD index stable, not signaling acute stress",
-    "action": "open_hedge_checklist",
-    "action_label": "Set Alert"
+    "status": "neutral",
+    "why": "USD in normal range (110-122)",
+    "action": "none",
+    "action_label": "",
+    "rule": "dtwexbgs_level",
+    "rule_type": "level",
+    "rule_value": 121.45
+  },
+  "vix": {
+    "series_id": "VIXCLS",
+    "value": 19.2,
+    "as_of": "2025-10-27",
+    "status": "watch",
+    "why": "VIX up ≥15% in 2 sessions",
+    "action": "prepare_checklist",
+    "action_label": "Prepare T-Bill Ladder",
+    "rule": "vix_spike_2d",
+    "rule_type": "pct_change",
+    "rule_value": 18.4
   }
 }</code></pre>
         </div>
 
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/signals/history</span></h3>
+            <p>Signal status transitions, newest first. A transition is recorded each time new data moves a signal to a different status.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>signal</td><td>string</td><td>Signal key, e.g. vix, dtwexbgs (default: all)</td></tr>
+                <tr><td>days</td><td>int</td><td>Look-back window (default: 30)</td></tr>
+                <tr><td>limit</td><td>int</td><td>Maximum transitions (default: 100, max: 1000)</td></tr>
+            </table>
+            <h4>Response</h4>
+            <pre><code>{
+  "transitions": [
+    {
+      "id": 42,
+      "signal_key": "vix",
+      "series_id": "VIXCLS",
+      "from_status": "good",
+      "to_status": "watch",
+      "value": 22.1,
+      "as_of": "2025-10-24",
+      "rule": "vix_level",
+      "why": "VIX 20-30, elevated uncertainty",
+      "created_at": "2025-10-24T21:05:00Z"
+    }
+  ],
+  "count": 1,
+  "since": "2025-09-27T12:00:00Z"
+}</code></pre>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/stream</span></h3>
+            <p>Server-Sent Events pushed as data is ingested: <code>point</code> (new or revised observations of a series), <code>index</code> (new index values) and <code>signal</code> (a signal transition, same shape as <code>/api/signals/history</code>). A <code>heartbeat</code> event is sent every 15 seconds.</p>
+            <p>Every event has an <code>id</code>. On reconnect, browsers send it back as <code>Last-Event-ID</code> and the stream replays what was missed from the last 1000 events. If the gap is no longer available, a <code>reset</code> event is sent first; refetch <code>/api/latest</code> and <code>/api/signals/latest</code> when you get one.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>series</td><td>string</td><td>Comma-separated series IDs to receive, e.g. VIXCLS,IDX_RMB_PENETRATION (default: all)</td></tr>
+                <tr><td>last_event_id</td><td>int</td><td>Resume after this event when not sending the <code>Last-Event-ID</code> header</td></tr>
+            </table>
+            <h4>Events</h4>
+            <pre><code>id: 1792212554497
+event: point
+data: {"series_id":"VIXCLS","latest":{"date":"2025-10-27","value":31.2},"previous":{"date":"2025-10-24","value":22.1},"points":[{"date":"2025-10-27","value":31.2}],"changed":1,"at":"2025-10-27T21:05:00Z"}
+
+id: 1792212554498
+event: signal
+data: {"id":43,"signal_key":"vix","series_id":"VIXCLS","from_status":"watch","to_status":"crisis","value":31.2,"as_of":"2025-10-27","rule":"vix_level","why":"VIX ≥30, market panic/fear","created_at":"2025-10-27T21:05:00Z"}</code></pre>
+            <pre><code>const stream = new EventSource('/api/stream?series=VIXCLS');
+stream.addEventListener('signal', e => console.log(JSON.parse(e.data)));</code></pre>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/alerts-feed.{rss|atom|json}</span></h3>
+            <p>The 50 newest alerts feed events (signal transitions; users' own alert triggers are private and not included) as RSS 2.0, Atom or JSON Feed 1.1. Each item links to its permalink at <code>/alerts-feed/{id}</code>.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>series</td><td>string</td><td>Only events for this series ID, e.g. VIXCLS (default: all)</td></tr>
+                <tr><td>status</td><td>string</td><td>Only events ending in this status: crisis, watch, neutral, good or alert (default: all)</td></tr>
+            </table>
+        </div>
+
         <h2>📥 Export Endpoints</h2>
 
         <div class="endpoint">
@@ -312,7 +511,7 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
 
         <div class="endpoint">
             <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/export/all?format={csv|json}</span></h3>
-            <p>Export all series data (last 365 days)</p>
+            <p>Export the last 365 days of every catalog series that has data, with each series' name and unit</p>
             <h4>Example</h4>
             <pre><code>curl "https://web-production-4c1d00.up.railway.app/api/export/all?format=json" -o reserve_watch_full.json</code></pre>
         </div>
@@ -321,7 +520,7 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
 
         <div class="endpoint">
             <h3><span class="method method-post">POST</span><span class="endpoint-path">/api/alerts</span></h3>
-            <p>Create a new threshold alert</p>
+            <p>Create a new threshold alert. Alerts fire when a new observation crosses the threshold, not while the series stays beyond it. An alert created while the series is already beyond its threshold waits for the next crossing.</p>
             <h4>Request Body</h4>
             <pre><code>{
   "user_email": "user@example.com",
@@ -329,8 +528,24 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
   "series_id": "DTWEXBGS",
   "condition": "above",
   "threshold": 125.0,
-  "webhook_url": "https://hooks.zapier.com/..."
+  "webhook_url": "https://hooks.zapier.com/...",
+  "rearm_band": 1.0,
+  "confirmations": 2,
+  "cooldown_minutes": 1440
 }</code></pre>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>rearm_band</td><td>float</td><td>How far back past the threshold the value must go before the alert can fire again (default: 0)</td></tr>
+                <tr><td>confirmations</td><td>int</td><td>Consecutive observations beyond the threshold needed to fire (default: 1)</td></tr>
+                <tr><td>cooldown_minutes</td><td>int</td><td>Minimum time between triggers (default: 60)</td></tr>
+                <tr><td>expression</td><td>string</td><td>Condition over several series, used instead of series_id/condition/threshold (see below)</td></tr>
+                <tr><td>channels</td><td>array</td><td>Where to deliver triggers, e.g. <code>[{"type": "slack", "target": "https://hooks.slack.com/..."}]</code>. Types: email (address), sms (phone number), webhook, slack, discord, teams (webhook URL). webhook_url is kept as a webhook channel.</td></tr>
+            </table>
+            <h4>Expression Alerts</h4>
+            <p>Compare series and derived values with <code>&gt; &gt;= &lt; &lt;= == !=</code>, combine comparisons with <code>AND</code>, <code>OR</code>, <code>NOT</code> and parentheses, and use <code>+ - * /</code>. A bare series ID is its latest observation. Functions: <code>pct_change(S, n)</code>, <code>change(S, n)</code>, <code>avg(S, n)</code>, <code>zscore(S, n)</code>, <code>spread(a, b)</code>, <code>abs(x)</code>; windows <code>n</code> are at most 1000 observations. Expressions are validated when the alert is created.</p>
+            <pre><code>{"user_email": "user@example.com", "name": "USD ±2% in 10 days", "expression": "abs(pct_change(DTWEXBGS, 10)) >= 2"}
+{"user_email": "user@example.com", "name": "Risk-off", "expression": "VIXCLS > 25 AND BAMLC0A4CBBB > 300"}
+{"user_email": "user@example.com", "name": "USD/EUR reserve gap", "expression": "spread(COFER_USD, COFER_EUR) < 40"}</code></pre>
         </div>
 
         <div class="endpoint">
@@ -343,6 +558,56 @@ json.NewDecoder(resp.Body).Decode(&result)</code></pre>
             <p>Delete a specific alert</p>
         </div>
 
+        <div class="endpoint">
+            <h3><span class="method method-patch">PATCH</span><span class="endpoint-path">/api/alerts/{id}?email={email}</span></h3>
+            <p>Update an alert. Send only the fields to change, using the same names as create. Changing the series, condition, threshold or expression re-arms the alert. On an expression alert, <code>series_id</code> and <code>threshold</code> are rejected unless <code>condition</code> switches it to <code>above</code>/<code>below</code>, which needs both.</p>
+            <pre><code>{"threshold": 30, "cooldown_minutes": 120}</code></pre>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-post">POST</span><span class="endpoint-path">/api/alerts/{id}/pause?email={email}</span></h3>
+            <p>Stop evaluating an alert. <code>POST /api/alerts/{id}/resume</code> starts it again, re-armed so values seen while paused don't trigger it.</p>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/alerts/{id}/history?email={email}</span></h3>
+            <p>An alert's triggers, newest first, with the value, threshold and per-channel delivery outcomes. <code>limit</code> defaults to 50 (max 500).</p>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-post">POST</span><span class="endpoint-path">/api/alerts/{id}/test?email={email}</span></h3>
+            <p>Send a test notification to every channel using the current value. Tests are marked <code>"test": true</code> in webhook payloads and are not recorded in the history.</p>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-get">GET</span><span class="endpoint-path">/api/deliveries?email={email}</span></h3>
+            <p>Webhook delivery log for a user's alerts, newest first. A failed delivery stays <code>pending</code> and is retried with exponential backoff (1, 2, 4 … minutes, at most 6 hours apart). After 8 failed attempts it becomes <code>dead</code>. A delivery is <code>sending</code> while an attempt is in flight.</p>
+            <h4>Parameters</h4>
+            <table>
+                <tr><th>Param</th><th>Type</th><th>Description</th></tr>
+                <tr><td>alert_id</td><td>int</td><td>Only deliveries for this alert</td></tr>
+                <tr><td>status</td><td>string</td><td>sending, pending, success or dead</td></tr>
+                <tr><td>limit</td><td>int</td><td>Maximum deliveries (default: 100, max: 1000)</td></tr>
+            </table>
+        </div>
+
+        <div class="endpoint">
+            <h3><span class="method method-post">POST</span><span class="endpoint-path">/api/deliveries/{id}/replay?email={email}</span></h3>
+            <p>Re-send a logged delivery's payload, freshly signed, as a new delivery with <code>replay_of</code> set</p>
+        </div>
+
+        <div class="endpoint">
+            <h3>Verifying Webhooks</h3>
+            <p>Each alert gets a <code>WebhookSecret</code> when it is created. Every webhook carries these headers:</p>
+            <table>
+                <tr><th>Header</th><th>Description</th></tr>
+                <tr><td>X-Reserve-Watch-Timestamp</td><td>Unix time the request was signed</td></tr>
+                <tr><td>X-Reserve-Watch-Signature</td><td><code>sha256=</code> + hex HMAC-SHA256 of <code>{timestamp}.{raw body}</code>, keyed with the alert's secret</td></tr>
+                <tr><td>X-Reserve-Watch-Delivery</td><td>Delivery ID (changes on replay)</td></tr>
+            </table>
+            <p>Recompute the signature and compare it in constant time, and reject old timestamps. Retries and replays keep the same <code>trigger_id</code> in the body, so you can de-duplicate on it.</p>
+        </div>
+
         <h2>📖 OpenAPI 3.0 Specification</h2>
         <div class="endpoint">
             <p>Full OpenAPI spec available below (copy to your favorite API client)</p>
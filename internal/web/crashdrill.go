@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"html/template"
 	"net/http"
+
+	"reserve-watch/internal/analytics"
 )
 
 type ChecklistItem struct {
@@ -19,20 +21,36 @@ func (s *Server) handleCrashDrill(w http.ResponseWriter, r *http.Request) {
 	vixData, _ := s.store.GetLatestPoint("VIXCLS")
 	bbbData, _ := s.store.GetLatestPoint("BAMLC0A4CBBB")
 
-	// Determine crisis level
+	// Determine crisis level from the same signal rules as Trigger Watch
 	crisisLevel := "normal"
 	alertMessage := "Markets are stable. Review protocols for preparedness."
 
-	if vixData != nil && bbbData != nil {
-		if vixData.Value > 30 || bbbData.Value > 400 {
-			crisisLevel = "critical"
-			alertMessage = "🚨 CRITICAL TRIGGERS ACTIVATED - Execute emergency protocols immediately"
-		} else if vixData.Value > 20 || bbbData.Value > 200 {
-			crisisLevel = "elevated"
-			alertMessage = "⚠️ Elevated risk detected - Review and prepare contingency plans"
+	worst := analytics.StatusGood
+	rs := analytics.Rules()
+	for _, seriesID := range []string{"VIXCLS", "BAMLC0A4CBBB"} {
+		signal, ok := rs.EvaluateLatest(s.store, seriesID)
+		if !ok {
+			continue
+		}
+		switch signal.Status {
+		case analytics.StatusCrisis:
+			worst = analytics.StatusCrisis
+		case analytics.StatusWatch:
+			if worst != analytics.StatusCrisis {
+				worst = analytics.StatusWatch
+			}
 		}
 	}
 
+	switch worst {
+	case analytics.StatusCrisis:
+		crisisLevel = "critical"
+		alertMessage = "🚨 CRITICAL TRIGGERS ACTIVATED - Execute emergency protocols immediately"
+	case analytics.StatusWatch:
+		crisisLevel = "elevated"
+		alertMessage = "⚠️ Elevated risk detected - Review and prepare contingency plans"
+	}
+
 	checklist := []ChecklistItem{
 		{
 			Icon:        "🏦",
@@ -448,7 +466,7 @@ const crashDrillTemplate = `<!DOCTYPE html>
         <div class="intro">
             <h2>📋 About This Protocol</h2>
             <p>The Crash-Drill Autopilot is a comprehensive emergency checklist designed to protect your wealth during financial system stress. Each item is prioritized and includes step-by-step instructions for rapid deployment.</p>
-            <p style="margin-top: 15px;"><strong>Best Practice:</strong> Review this checklist quarterly during calm markets. When crisis indicators activate (VIX >30, BBB OAS >400bps), execute immediately.</p>
+            <p style="margin-top: 15px;"><strong>Best Practice:</strong> Review this checklist quarterly during calm markets. When crisis indicators activate (see Trigger Watch for current thresholds), execute immediately.</p>
         </div>
 
         {{range .Checklist}}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
 )
 
@@ -125,36 +127,35 @@ func (s *Server) handleExportJSON(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleExportAll exports all series data
+// handleExportAll exports the last year of every series in the catalog
 func (s *Server) handleExportAll(w http.ResponseWriter, r *http.Request) {
 	format := r.URL.Query().Get("format")
 	if format == "" {
 		format = "json"
 	}
 
-	seriesIDs := []string{
-		"DTWEXBGS",     // FRED USD Index
-		"DXY_REALTIME", // Yahoo Finance DXY
-		"IMF_COFER",    // IMF COFER
-		"SWIFT_RMB",    // SWIFT RMB Tracker
-		"CIPS_NETWORK", // CIPS Network
-		"WGC_GOLD",     // World Gold Council
-		"VIXCLS",       // VIX
-		"BAMLC0A4CBBB", // BBB OAS
+	catalog, err := s.store.ListSeries()
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to list series for export: %v", err)
+		http.Error(w, "Failed to retrieve data", http.StatusInternalServerError)
+		return
 	}
 
-	allData := make(map[string]interface{})
+	var exported []store.Series
+	allData := make(map[string][]store.SeriesPoint)
+	for _, series := range catalog {
+		if series.Observations == 0 {
+			continue
+		}
 
-	for _, seriesID := range seriesIDs {
-		points, err := s.store.GetRecentPoints(seriesID, 365) // 1 year of data
+		points, err := s.store.GetRecentPoints(series.ID, 365) // 1 year of data
 		if err != nil {
-			util.ErrorLogger.Printf("Failed to get points for %s: %v", seriesID, err)
+			util.ErrorLogger.Printf("Failed to get points for %s: %v", series.ID, err)
 			continue
 		}
 
-		if len(points) > 0 {
-			allData[seriesID] = points
-		}
+		exported = append(exported, series)
+		allData[series.ID] = points
 	}
 
 	if format == "csv" {
@@ -166,20 +167,18 @@ func (s *Server) handleExportAll(w http.ResponseWriter, r *http.Request) {
 		defer writer.Flush()
 
 		// Write header
-		writer.Write([]string{"Series", "Date", "Value"})
-
-		// Write all data
-		for seriesID, data := range allData {
-			if points, ok := data.([]interface{}); ok {
-				for _, p := range points {
-					if point, ok := p.(map[string]interface{}); ok {
-						writer.Write([]string{
-							seriesID,
-							fmt.Sprintf("%v", point["Date"]),
-							fmt.Sprintf("%v", point["Value"]),
-						})
-					}
-				}
+		writer.Write([]string{"Series", "Name", "Unit", "Date", "Value"})
+
+		// Write all data in catalog order
+		for _, series := range exported {
+			for _, point := range allData[series.ID] {
+				writer.Write([]string{
+					series.ID,
+					series.Name,
+					series.Unit,
+					point.Date,
+					strconv.FormatFloat(point.Value, 'f', -1, 64),
+				})
 			}
 		}
 	} else {
@@ -188,8 +187,9 @@ func (s *Server) handleExportAll(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Disposition", "attachment; filename=\"reserve-watch-export.json\"")
 
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"exported_at":  "now",
-			"series_count": len(allData),
+			"exported_at":  time.Now().UTC().Format(time.RFC3339),
+			"series_count": len(exported),
+			"series":       exported,
 			"data":         allData,
 		})
 	}
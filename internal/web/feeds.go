@@ -0,0 +1,246 @@
+package web
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"reserve-watch/internal/util"
+)
+
+const (
+	feedTitle       = "Reserve Watch Alerts"
+	feedDescription = "Signal status changes from Reserve Watch"
+)
+
+// RSS 2.0
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Atom 1.0
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title    string       `xml:"title"`
+	ID       string       `xml:"id"`
+	Link     atomLink     `xml:"link"`
+	Updated  string       `xml:"updated"`
+	Summary  string       `xml:"summary"`
+	Category atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// JSON Feed 1.1
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentText   string   `json:"content_text"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags"`
+}
+
+// requestBaseURL returns the scheme and host the request was made to, honouring proxies
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// feedItems loads the newest events for a feed, honouring the page's series/status filters
+func (s *Server) feedItems(w http.ResponseWriter, r *http.Request) ([]FeedItem, bool) {
+	filter, _ := feedFilter(r)
+	filter.Offset = 0
+	items, _, err := s.loadFeedItems(filter)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load alerts feed: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return items, true
+}
+
+// feedEntryTitle is the item title without the page's emoji, e.g. "VIX (Volatility Index): Watch"
+func feedEntryTitle(item FeedItem) string {
+	return item.Name + ": " + item.StatusName
+}
+
+func feedSummary(item FeedItem) string {
+	summary := item.Change + ". " + item.Detail
+	if item.Reason != "" {
+		summary = strings.TrimSuffix(item.Reason, ".") + ". " + summary
+	}
+	return summary
+}
+
+// feedUpdated is the time of the newest item, or now for an empty feed
+func feedUpdated(items []FeedItem) time.Time {
+	if len(items) > 0 {
+		return items[0].Time
+	}
+	return time.Now()
+}
+
+// handleAlertsFeedRSS serves the alerts feed as RSS 2.0
+func (s *Server) handleAlertsFeedRSS(w http.ResponseWriter, r *http.Request) {
+	items, ok := s.feedItems(w, r)
+	if !ok {
+		return
+	}
+	base := requestBaseURL(r)
+
+	feed := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feedTitle,
+			Link:          base + "/alerts-feed",
+			Description:   feedDescription,
+			SelfLink:      atomLink{Href: base + r.URL.RequestURI(), Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: feedUpdated(items).UTC().Format(time.RFC1123Z),
+		},
+	}
+	for _, item := range items {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       feedEntryTitle(item),
+			Link:        base + item.Path,
+			GUID:        rssGUID{IsPermaLink: true, Value: base + item.Path},
+			Description: feedSummary(item),
+			Category:    item.Status,
+			PubDate:     item.Time.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	writeXMLFeed(w, "application/rss+xml; charset=utf-8", feed)
+}
+
+// handleAlertsFeedAtom serves the alerts feed as Atom 1.0
+func (s *Server) handleAlertsFeedAtom(w http.ResponseWriter, r *http.Request) {
+	items, ok := s.feedItems(w, r)
+	if !ok {
+		return
+	}
+	base := requestBaseURL(r)
+
+	feed := atomFeed{
+		Title:   feedTitle,
+		ID:      base + "/alerts-feed",
+		Updated: feedUpdated(items).UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: base + r.URL.RequestURI(), Rel: "self", Type: "application/atom+xml"},
+			{Href: base + "/alerts-feed", Rel: "alternate", Type: "text/html"},
+		},
+	}
+	for _, item := range items {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:    feedEntryTitle(item),
+			ID:       base + item.Path,
+			Link:     atomLink{Href: base + item.Path},
+			Updated:  item.Time.UTC().Format(time.RFC3339),
+			Summary:  feedSummary(item),
+			Category: atomCategory{Term: item.Status},
+		})
+	}
+
+	writeXMLFeed(w, "application/atom+xml; charset=utf-8", feed)
+}
+
+// handleAlertsFeedJSON serves the alerts feed as JSON Feed 1.1
+func (s *Server) handleAlertsFeedJSON(w http.ResponseWriter, r *http.Request) {
+	items, ok := s.feedItems(w, r)
+	if !ok {
+		return
+	}
+	base := requestBaseURL(r)
+
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feedTitle,
+		HomePageURL: base + "/alerts-feed",
+		FeedURL:     base + r.URL.RequestURI(),
+		Description: feedDescription,
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range items {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            item.ID,
+			URL:           base + item.Path,
+			Title:         feedEntryTitle(item),
+			ContentText:   feedSummary(item),
+			DatePublished: item.Time.UTC().Format(time.RFC3339),
+			Tags:          []string{item.Status},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+	json.NewEncoder(w).Encode(feed)
+}
+
+func writeXMLFeed(w http.ResponseWriter, contentType string, feed interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		util.ErrorLogger.Printf("Failed to encode feed: %v", err)
+	}
+}
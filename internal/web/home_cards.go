@@ -2,9 +2,9 @@ package web
 
 import (
 	"fmt"
-	"time"
 
 	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/store"
 )
 
 // buildDataSourceCards creates all homepage data cards with signal analysis
@@ -13,6 +13,7 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 
 	// Fetch signal analysis for all indicators
 	signals, _ := analytics.GetAllSignals(s.store)
+	catalog := s.seriesCatalog()
 
 	// Helper function to calculate delta % vs 10 days ago
 	calculateDelta := func(seriesID string) string {
@@ -79,8 +80,6 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		}
 	}
 
-	now := time.Now().Format("2006-01-02 15:04")
-
 	// 1. Real-time DXY from Yahoo Finance
 	realtimeData, _ := s.store.GetLatestPoint("DXY_REALTIME")
 	signal1 := signals["dtwexbgs"]
@@ -88,9 +87,9 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "🟢 Live Market Price (DXY) - Indicative",
 			Value:         fmt.Sprintf("%.2f", realtimeData.Value),
-			Source:        "Yahoo Finance",
+			Source:        catalog["DXY_REALTIME"].Provider,
 			Date:          realtimeData.Date,
-			Link:          "https://finance.yahoo.com/quote/DX-Y.NYB",
+			Link:          catalog["DXY_REALTIME"].Link,
 			HasData:       true,
 			SoWhat:        "Market-driven USD strength affects export pricing, import costs, and EM debt burden.",
 			DoThisNow:     "Set alert: USD +2% in 10 days → Review FX hedges",
@@ -100,10 +99,11 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 			Status:        string(signal1.Status),
 			StatusBadge:   getStatusBadge(string(signal1.Status)),
 			Why:           signal1.Why,
+			Rule:          signal1.Rule,
 			ActionLabel:   signal1.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal1.Action),
-			SourceUpdated: realtimeData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(realtimeData),
+			IngestedAt:    ingestedLabel(realtimeData),
 			Delta:         calculateDelta("DXY_REALTIME"),
 			SparklineData: getSparklineData("DXY_REALTIME"),
 		})
@@ -112,8 +112,8 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:       "🟢 Live Market Price (DXY) - Indicative",
 			Value:       "⏳ Gathering data...",
-			Source:      "Yahoo Finance",
-			Link:        "https://finance.yahoo.com/quote/DX-Y.NYB",
+			Source:      catalog["DXY_REALTIME"].Provider,
+			Link:        catalog["DXY_REALTIME"].Link,
 			HasData:     false,
 			SoWhat:      "Real-time USD index data will appear here once fetched from Yahoo Finance API.",
 			DoThisNow:   "Data is being collected - check back shortly",
@@ -128,9 +128,9 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "📊 Nominal Broad U.S. Dollar Index",
 			Value:         fmt.Sprintf("%.2f", fredData.Value),
-			Source:        "FRED DTWEXBGS",
+			Source:        catalog["DTWEXBGS"].Provider,
 			Date:          fredData.Date,
-			Link:          "https://fred.stlouisfed.org/series/DTWEXBGS",
+			Link:          catalog["DTWEXBGS"].Link,
 			HasData:       true,
 			SoWhat:        "Stronger USD tightens financial conditions abroad. This is the broad trade-weighted dollar.",
 			DoThisNow:     "Set alert: USD +2% in 10 days → Review FX exposures",
@@ -140,10 +140,11 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 			Status:        string(signal.Status),
 			StatusBadge:   getStatusBadge(string(signal.Status)),
 			Why:           signal.Why,
+			Rule:          signal.Rule,
 			ActionLabel:   signal.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal.Action),
-			SourceUpdated: fredData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(fredData),
+			IngestedAt:    ingestedLabel(fredData),
 			Delta:         calculateDelta("DTWEXBGS"),
 			SparklineData: getSparklineData("DTWEXBGS"),
 		})
@@ -155,9 +156,9 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "💰 CNY Global Reserve Share",
 			Value:         fmt.Sprintf("%.2f%%", coferData.Value),
-			Source:        "IMF COFER",
+			Source:        catalog["COFER_CNY"].Provider,
 			Date:          coferData.Date,
-			Link:          "https://data.imf.org/?sk=E6A5F467-C14B-4AA8-9F6D-5A09EC4E62A4",
+			Link:          catalog["COFER_CNY"].Link,
 			HasData:       true,
 			SoWhat:        "CNY's reserve share is small but rising. Long-run currency preference signal.",
 			DoThisNow:     "Watch for CNY >3% → Enable RMB settlement",
@@ -167,10 +168,11 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 			Status:        string(signal.Status),
 			StatusBadge:   getStatusBadge(string(signal.Status)),
 			Why:           signal.Why,
+			Rule:          signal.Rule,
 			ActionLabel:   signal.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal.Action),
-			SourceUpdated: coferData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(coferData),
+			IngestedAt:    ingestedLabel(coferData),
 			Delta:         calculateDelta("COFER_CNY"),
 			SparklineData: getSparklineData("COFER_CNY"),
 		})
@@ -182,9 +184,9 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "💳 RMB Global Payment Share",
 			Value:         fmt.Sprintf("%.2f%%", swiftData.Value),
-			Source:        "SWIFT RMB Tracker",
+			Source:        catalog["SWIFT_RMB"].Provider,
 			Date:          swiftData.Date,
-			Link:          "https://www.swift.com/swift-resource/248201/download",
+			Link:          catalog["SWIFT_RMB"].Link,
 			HasData:       true,
 			SoWhat:        "RMB use in payments is growing; upticks often precede vendors asking for RMB terms.",
 			DoThisNow:     "Set alert: RMB >3% → Offer RMB payment terms",
@@ -194,10 +196,11 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 			Status:        string(signal.Status),
 			StatusBadge:   getStatusBadge(string(signal.Status)),
 			Why:           signal.Why,
+			Rule:          signal.Rule,
 			ActionLabel:   signal.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal.Action),
-			SourceUpdated: swiftData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(swiftData),
+			IngestedAt:    ingestedLabel(swiftData),
 			Delta:         calculateDelta("SWIFT_RMB"),
 			SparklineData: getSparklineData("SWIFT_RMB"),
 		})
@@ -209,9 +212,9 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "🌐 CIPS Network Participants",
 			Value:         fmt.Sprintf("%.0f", cipsData.Value),
-			Source:        "CIPS",
+			Source:        catalog["CIPS_PARTICIPANTS"].Provider,
 			Date:          cipsData.Date,
-			Link:          "https://www.cips.com.cn/en/index/index.html",
+			Link:          catalog["CIPS_PARTICIPANTS"].Link,
 			HasData:       true,
 			SoWhat:        "More participants = easier RMB settlement with China-linked counterparties.",
 			DoThisNow:     "Track growth → Prepare bank enablement",
@@ -221,10 +224,11 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 			Status:        string(signal.Status),
 			StatusBadge:   getStatusBadge(string(signal.Status)),
 			Why:           signal.Why,
+			Rule:          signal.Rule,
 			ActionLabel:   signal.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal.Action),
-			SourceUpdated: cipsData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(cipsData),
+			IngestedAt:    ingestedLabel(cipsData),
 			Delta:         calculateDelta("CIPS_PARTICIPANTS"),
 			SparklineData: getSparklineData("CIPS_PARTICIPANTS"),
 		})
@@ -236,22 +240,23 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 		cards = append(cards, DataSourceCard{
 			Label:         "🥇 Central Bank Gold Purchases (QTD)",
 			Value:         fmt.Sprintf("%.0f tonnes", wgcData.Value),
-			Source:        "World Gold Council",
+			Source:        catalog["WGC_CB_PURCHASES"].Provider,
 			Date:          wgcData.Date,
-			Link:          "https://www.gold.org/goldhub/research/gold-demand-trends",
+			Link:          catalog["WGC_CB_PURCHASES"].Link,
 			HasData:       true,
 			SoWhat:        "Official sector keeps buying gold → structural diversification pressure.",
-			DoThisNow:     "Set alert: CB buys >100t/month → Prepare gold proof docs",
+			DoThisNow:     "Set alert: CB buys ≥250t/quarter → Prepare gold proof docs",
 			AlertName:     "Gold Buying Surge",
 			AlertSignal:   "wgc_cb_purchases_spike",
 			ChecklistID:   "gold-proof-holdings",
 			Status:        string(signal.Status),
 			StatusBadge:   getStatusBadge(string(signal.Status)),
 			Why:           signal.Why,
+			Rule:          signal.Rule,
 			ActionLabel:   signal.ActionLabel,
 			ActionURL:     analytics.GetActionURL(signal.Action),
-			SourceUpdated: wgcData.Date,
-			IngestedAt:    now,
+			SourceUpdated: sourceUpdatedLabel(wgcData),
+			IngestedAt:    ingestedLabel(wgcData),
 			Delta:         calculateDelta("WGC_CB_PURCHASES"),
 			SparklineData: getSparklineData("WGC_CB_PURCHASES"),
 		})
@@ -259,3 +264,20 @@ func (s *Server) buildDataSourceCards() []DataSourceCard {
 
 	return cards
 }
+
+// sourceUpdatedLabel is when the source last updated a card's series, falling back
+// to the observation date for sources that don't report it
+func sourceUpdatedLabel(p *store.SeriesPoint) string {
+	if p.SourceUpdatedAt == nil {
+		return p.Date
+	}
+	return p.SourceUpdatedAt.UTC().Format("2006-01-02 15:04")
+}
+
+// ingestedLabel is when we last fetched a card's series
+func ingestedLabel(p *store.SeriesPoint) string {
+	if p.IngestedAt.IsZero() {
+		return ""
+	}
+	return p.IngestedAt.Format("2006-01-02 15:04")
+}
@@ -3,22 +3,30 @@ package web
 import (
 	"html/template"
 	"net/http"
+	"slices"
+	"strings"
+
+	"reserve-watch/internal/util"
 )
 
 type DataSource struct {
 	Name      string
-	Series    string
+	Series    string // catalog IDs, filled in from source and seriesIDs
 	Link      string
 	Frequency string
 	Provider  string
 	Notes     string
+
+	source    string   // ingest source the series come from
+	seriesIDs []string // the source's series described here; nil for all of them
 }
 
 func (s *Server) handleMethodology(w http.ResponseWriter, r *http.Request) {
 	sources := []DataSource{
 		{
 			Name:      "Nominal Broad U.S. Dollar Index",
-			Series:    "DTWEXBGS",
+			source:    "fred",
+			seriesIDs: []string{"DTWEXBGS"},
 			Link:      "https://fred.stlouisfed.org/series/DTWEXBGS",
 			Frequency: "Daily (business days)",
 			Provider:  "Federal Reserve Economic Data (FRED)",
@@ -26,31 +34,31 @@ func (s *Server) handleMethodology(w http.ResponseWriter, r *http.Request) {
 		},
 		{
 			Name:      "Real-Time DXY Index",
-			Series:    "DX-Y.NYB",
+			source:    "yahoo_finance",
 			Link:      "https://finance.yahoo.com/quote/DX-Y.NYB",
 			Frequency: "Real-time (market hours)",
 			Provider:  "Yahoo Finance / ICE Futures",
 			Notes:     "Live market pricing of USD Dollar Index futures during active trading hours.",
 		},
 		{
-			Name:      "Currency Composition of Foreign Exchange Reserves (CNY)",
-			Series:    "COFER",
+			Name:      "Currency Composition of Foreign Exchange Reserves",
+			source:    "imf_cofer",
 			Link:      "https://data.imf.org/?sk=E6A5F467-C14B-4AA8-9F6D-5A09EC4E62A4",
 			Frequency: "Quarterly",
 			Provider:  "International Monetary Fund (IMF)",
-			Notes:     "Percentage of global foreign exchange reserves held in Chinese Yuan (CNY/RMB). Updated quarterly with ~2-month lag.",
+			Notes:     "Shares of allocated global FX reserves held in USD, EUR, JPY, GBP, CNY, CAD, AUD, CHF and other currencies, plus total, allocated and unallocated reserves. Shares are computed against allocated reserves. Updated quarterly with ~3-month lag.",
 		},
 		{
 			Name:      "RMB Global Payment Share",
-			Series:    "SWIFT RMB Tracker",
+			source:    "swift_rmb_tracker",
 			Link:      "https://www.swift.com/swift-resource/248201/download",
 			Frequency: "Monthly",
 			Provider:  "SWIFT (Society for Worldwide Interbank Financial Telecommunication)",
-			Notes:     "RMB's share of global payments by value. Published monthly via PDF report with ~1-month lag. Requires PDF parsing.",
+			Notes:     "RMB's share of global payments by value. Published monthly via PDF report with ~1-month lag; share and rank are extracted from the latest report and dated to the report month.",
 		},
 		{
 			Name:      "CIPS Network Statistics",
-			Series:    "CIPS Participants, Daily Average Volume",
+			source:    "cips",
 			Link:      "https://www.cips.com.cn/en/index/index.html",
 			Frequency: "Annual (participants), Daily average (volume)",
 			Provider:  "Cross-Border Interbank Payment System (CIPS)",
@@ -58,12 +66,36 @@ func (s *Server) handleMethodology(w http.ResponseWriter, r *http.Request) {
 		},
 		{
 			Name:      "Central Bank Gold Purchases",
-			Series:    "WGC Central Bank Demand",
+			source:    "world_gold_council",
+			seriesIDs: []string{"WGC_CB_PURCHASES"},
 			Link:      "https://www.gold.org/goldhub/research/gold-demand-trends",
 			Frequency: "Quarterly",
 			Provider:  "World Gold Council",
 			Notes:     "Net gold purchases by central banks globally (tonnes). Indicates reserve diversification away from fiat currencies.",
 		},
+		{
+			Name:      "Gold Share of Official Reserves",
+			source:    "world_gold_council",
+			seriesIDs: []string{"WGC_GOLD_RESERVE_SHARE"},
+			Link:      "https://www.gold.org/goldhub/data/gold-reserves-by-country",
+			Frequency: "Monthly/Quarterly",
+			Provider:  "World Gold Council (from IMF IFS)",
+			Notes:     "Gold as a percentage of total official reserves worldwide. Feeds the Reserve Diversification Pressure index.",
+		},
+	}
+
+	catalog, err := s.store.ListSeries()
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load series catalog: %v", err)
+	}
+	for i := range sources {
+		var ids []string
+		for _, series := range catalog {
+			if series.Source == sources[i].source && (sources[i].seriesIDs == nil || slices.Contains(sources[i].seriesIDs, series.ID)) {
+				ids = append(ids, series.ID)
+			}
+		}
+		sources[i].Series = strings.Join(ids, ", ")
 	}
 
 	tmpl := template.Must(template.New("methodology").Parse(methodologyTemplate))
@@ -326,7 +358,7 @@ const methodologyTemplate = `<!DOCTYPE html>
                 <h3 style="color: #333; margin-bottom: 10px;">SWIFT RMB Tracker</h3>
                 <p style="margin-bottom: 10px;">RMB payment share data is sourced from SWIFT's monthly RMB Tracker reports (publicly available PDFs).</p>
                 <p><strong>Source:</strong> <a href="https://www.swift.com/swift-resource/248201/download" target="_blank" style="color: #667eea;">SWIFT RMB Tracker</a></p>
-                <p><strong>Note:</strong> Data is manually extracted from public reports. SWIFT does not provide an official API.</p>
+                <p><strong>Note:</strong> SWIFT does not provide an official API. The RMB share and rank are extracted automatically from the latest PDF report.</p>
             </div>
 
             <div style="background: #f8f9fa; padding: 20px; border-left: 4px solid #667eea; margin: 20px 0;">
@@ -338,7 +370,7 @@ const methodologyTemplate = `<!DOCTYPE html>
 
             <div style="background: #f8f9fa; padding: 20px; border-left: 4px solid #667eea; margin: 20px 0;">
                 <h3 style="color: #333; margin-bottom: 10px;">World Gold Council (WGC)</h3>
-                <p style="margin-bottom: 10px;">Central bank gold purchase data is sourced from World Gold Council's publicly available reports and data.</p>
+                <p style="margin-bottom: 10px;">Central bank gold purchases and gold's share of official reserves are read from World Gold Council's downloadable Goldhub data files (XLSX/CSV).</p>
                 <p><strong>Terms:</strong> <a href="https://www.gold.org/terms-and-conditions" target="_blank" style="color: #667eea;">WGC Terms & Conditions</a></p>
                 <p><strong>Attribution:</strong> Data sourced from World Gold Council. © World Gold Council. All rights reserved.</p>
             </div>
@@ -0,0 +1,207 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+// handleSeriesList lists the series catalog: GET /api/series[?source=fred]
+func (s *Server) handleSeriesList(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	catalog, err := s.store.ListSeries()
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to list series: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to list series"})
+		return
+	}
+
+	series := []store.Series{}
+	source := r.URL.Query().Get("source")
+	for _, e := range catalog {
+		if source == "" || e.Source == source {
+			series = append(series, e)
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"series": series,
+		"count":  len(series),
+	})
+}
+
+// handleSeriesAPI routes series requests:
+// GET /api/series/{id}                      - catalog entry
+// GET /api/series/{id[,id...]}/observations - observations, see handleSeriesObservations
+func (s *Server) handleSeriesAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	// Extract series ID and action from path: /api/series/{id}[/observations]
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || len(parts) > 4 || parts[2] == "" || (len(parts) == 4 && parts[3] != "observations") {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Not found"})
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if len(parts) == 4 {
+		s.handleSeriesObservations(w, r, strings.Split(parts[2], ","))
+		return
+	}
+
+	series, err := s.store.GetSeries(parts[2])
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load series %s: %v", parts[2], err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to load series"})
+		return
+	}
+	if series == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Unknown series: " + parts[2]})
+		return
+	}
+
+	json.NewEncoder(w).Encode(series)
+}
+
+// maxObservationSeries caps how many series one observations request may align
+const maxObservationSeries = 10
+
+// handleSeriesObservations serves observations of one or more comma-separated series,
+// oldest first. Query params: start, end (YYYY-MM-DD), limit (default 1000, max 10000),
+// cursor (next_cursor of the previous page), frequency (weekly, monthly, quarterly),
+// aggregation (last, mean, sum; default last), transform (pct_change, yoy, log,
+// index), as_of (RFC3339 or YYYY-MM-DD). Several series are aligned on the union of
+// their dates, with null where a series has no observation.
+func (s *Server) handleSeriesObservations(w http.ResponseWriter, r *http.Request, ids []string) {
+	badRequest := func(msg string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	}
+
+	if len(ids) > maxObservationSeries {
+		badRequest(fmt.Sprintf("At most %d series per request", maxObservationSeries))
+		return
+	}
+	catalog := s.seriesCatalog()
+	for _, id := range ids {
+		if _, ok := catalog[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Unknown series: " + id})
+			return
+		}
+	}
+
+	q := r.URL.Query()
+	query := analytics.ObservationQuery{
+		Start:       q.Get("start"),
+		End:         q.Get("end"),
+		After:       q.Get("cursor"),
+		Limit:       1000,
+		Frequency:   q.Get("frequency"),
+		Aggregation: q.Get("aggregation"),
+		Transform:   q.Get("transform"),
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > 10000 {
+			badRequest("limit must be between 1 and 10000")
+			return
+		}
+		query.Limit = limit
+	}
+	if err := query.Validate(); err != nil {
+		badRequest(err.Error())
+		return
+	}
+	if query.Frequency != "" && query.Aggregation == "" {
+		query.Aggregation = analytics.AggregateLast
+	}
+
+	db, asOf, err := s.pointInTime(q.Get("as_of"))
+	if err != nil {
+		badRequest(err.Error())
+		return
+	}
+
+	// One extra observation per series tells whether there is another page
+	pageSize := query.Limit
+	query.Limit++
+	series := make(map[string][]store.SeriesPoint, len(ids))
+	for _, id := range ids {
+		points, err := analytics.LoadObservations(db, id, query)
+		if err != nil {
+			util.ErrorLogger.Printf("Failed to load observations of %s: %v", id, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to load observations"})
+			return
+		}
+		series[id] = points
+	}
+
+	aligned := analytics.AlignObservations(series)
+	var nextCursor *string
+	if len(aligned) > pageSize {
+		aligned = aligned[:pageSize]
+		nextCursor = &aligned[pageSize-1].Date
+	}
+
+	// A single series reads more naturally as date/value pairs
+	var observations interface{} = aligned
+	if len(ids) == 1 {
+		single := make([]map[string]interface{}, len(aligned))
+		for i, o := range aligned {
+			single[i] = map[string]interface{}{"date": o.Date, "value": o.Values[ids[0]]}
+		}
+		observations = single
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"series":       ids,
+		"start":        query.Start,
+		"end":          query.End,
+		"frequency":    query.Frequency,
+		"aggregation":  query.Aggregation,
+		"transform":    query.Transform,
+		"as_of":        asOf,
+		"count":        len(aligned),
+		"observations": observations,
+		"next_cursor":  nextCursor,
+	})
+}
+
+// seriesCatalog returns the catalog keyed by ID for resolving labels on pages.
+// Errors are logged and yield an empty catalog, so pages fall back to raw IDs.
+func (s *Server) seriesCatalog() map[string]store.Series {
+	catalog := make(map[string]store.Series)
+	series, err := s.store.ListSeries()
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to load series catalog: %v", err)
+		return catalog
+	}
+	for _, e := range series {
+		catalog[e.ID] = e
+	}
+	return catalog
+}
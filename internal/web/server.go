@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"strings"
 	"time"
 
+	"reserve-watch/internal/alerts"
 	"reserve-watch/internal/analytics"
 	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
@@ -20,9 +22,11 @@ type Server struct {
 	stripeKey          string
 	stripePriceMonthly string
 	stripePriceAnnual  string
+	notifiers          alerts.Notifiers
+	stream             *streamHub
 }
 
-func NewServer(store *store.Store, port string, stripeKey string, priceMonthly string, priceAnnual string) *Server {
+func NewServer(store *store.Store, port string, stripeKey string, priceMonthly string, priceAnnual string, notifiers alerts.Notifiers) *Server {
 	// Initialize Stripe
 	if stripeKey != "" {
 		stripe.Key = stripeKey
@@ -34,6 +38,8 @@ func NewServer(store *store.Store, port string, stripeKey string, priceMonthly s
 		stripeKey:          stripeKey,
 		stripePriceMonthly: priceMonthly,
 		stripePriceAnnual:  priceAnnual,
+		notifiers:          notifiers,
+		stream:             newStreamHub(store),
 	}
 }
 
@@ -55,15 +61,26 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/api/latest", s.handleAPILatest)
 	mux.HandleFunc("/api/latest/realtime", s.handleAPIRealtimeLatest)
 	mux.HandleFunc("/api/history", s.handleAPIHistory)
+	mux.HandleFunc("/api/series", s.handleSeriesList)
+	mux.HandleFunc("/api/series/", s.handleSeriesAPI)
+	mux.HandleFunc("/api/stream", s.handleStream)
 	mux.HandleFunc("/api/indices", s.handleAPIIndices)
+	mux.HandleFunc("/api/indices/history", s.handleAPIIndicesHistory)
 	mux.HandleFunc("/api/alerts", s.handleAlertsAPI)
-	mux.HandleFunc("/api/alerts/", s.handleDeleteAlert)
+	mux.HandleFunc("/api/alerts/", s.handleAlertAPI)
+	mux.HandleFunc("/api/deliveries", s.handleDeliveriesAPI)
+	mux.HandleFunc("/api/deliveries/", s.handleReplayDelivery)
 	mux.HandleFunc("/api/export/csv", s.handleExportCSV)
 	mux.HandleFunc("/api/export/json", s.handleExportJSON)
 	mux.HandleFunc("/api/export/all", s.handleExportAll)
 	mux.HandleFunc("/api/signals/latest", s.handleAPISignals)
+	mux.HandleFunc("/api/signals/history", s.handleAPISignalsHistory)
 	mux.HandleFunc("/referrals", s.handleReferrals)
 	mux.HandleFunc("/alerts-feed", s.handleAlertsFeed)
+	mux.HandleFunc("/alerts-feed/", s.handleAlertsFeed)
+	mux.HandleFunc("/alerts-feed.rss", s.handleAlertsFeedRSS)
+	mux.HandleFunc("/alerts-feed.atom", s.handleAlertsFeedAtom)
+	mux.HandleFunc("/alerts-feed.json", s.handleAlertsFeedJSON)
 
 	util.InfoLogger.Printf("Web server starting on port %s", s.port)
 	return http.ListenAndServe(":"+s.port, s.corsMiddleware(mux))
@@ -119,7 +136,7 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Last-Event-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -146,6 +163,7 @@ type DataSourceCard struct {
 	Status        string // good, neutral, watch, crisis
 	StatusBadge   string // CSS class for badge color
 	Why           string // Human-readable explanation
+	Rule          string // ID of the signal rule that set Status
 	ActionLabel   string // "Set Alert", "Review Hedges", etc
 	ActionURL     string // Link to action
 	SourceUpdated string // When source last updated
@@ -299,7 +317,7 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 // API: Get latest USD index value
 func (s *Server) handleAPILatest(w http.ResponseWriter, r *http.Request) {
 	latest, err := s.store.GetLatestPoint("DTWEXBGS")
-	if err != nil {
+	if err != nil || latest == nil {
 		http.Error(w, `{"error":"No data available"}`, http.StatusNotFound)
 		return
 	}
@@ -310,8 +328,8 @@ func (s *Server) handleAPILatest(w http.ResponseWriter, r *http.Request) {
 		"name":              "US Dollar Index",
 		"value":             latest.Value,
 		"asOf":              latest.Date,
-		"source_updated_at": latest.Date,
-		"ingested_at":       time.Now().Format(time.RFC3339),
+		"source_updated_at": sourceUpdatedAt(latest),
+		"ingested_at":       ingestedAt(latest),
 	})
 }
 
@@ -319,7 +337,7 @@ func (s *Server) handleAPILatest(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleAPIRealtimeLatest(w http.ResponseWriter, r *http.Request) {
 	latest, err := s.store.GetLatestPoint("DXY_REALTIME")
 	if err != nil || latest == nil {
-		http.Error(w, `{"error":"No real-time data available yet","message":"Check will run at next scheduled time (6 AM EST daily)"}`, http.StatusNotFound)
+		http.Error(w, `{"error":"No real-time data available yet","message":"Real-time DXY is fetched every 15 minutes"}`, http.StatusNotFound)
 		return
 	}
 
@@ -330,22 +348,42 @@ func (s *Server) handleAPIRealtimeLatest(w http.ResponseWriter, r *http.Request)
 		"source":            "Yahoo Finance",
 		"value":             latest.Value,
 		"asOf":              latest.Date,
-		"source_updated_at": latest.Date,
-		"ingested_at":       time.Now().Format(time.RFC3339),
+		"source_updated_at": sourceUpdatedAt(latest),
+		"ingested_at":       ingestedAt(latest),
 		"disclaimer":        "Indicative/demo data - Yahoo Finance/ICE DXY. Not for redistribution.",
 	})
 }
 
 // API: Get historical data
+// Query params: series (default DTWEXBGS), limit (default 30, max 365), as_of (RFC3339 or YYYY-MM-DD)
 func (s *Server) handleAPIHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	seriesID := q.Get("series")
+	if seriesID == "" {
+		seriesID = "DTWEXBGS"
+	}
+
 	// Get limit from query params (default 30)
-	limitStr := r.URL.Query().Get("limit")
+	limitStr := q.Get("limit")
 	limit := 30
 	if limitStr != "" {
 		fmt.Sscanf(limitStr, "%d", &limit)
 	}
+	if limit < 1 {
+		limit = 30
+	} else if limit > 365 {
+		limit = 365
+	}
+
+	db, asOf, err := s.pointInTime(q.Get("as_of"))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 
-	points, err := s.store.GetRecentPoints("DTWEXBGS", limit)
+	points, err := db.GetRecentPoints(seriesID, limit)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -356,17 +394,67 @@ func (s *Server) handleAPIHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	name := seriesID
+	if series, ok := s.seriesCatalog()[seriesID]; ok {
+		name = series.Name
+	}
+
+	// Freshness of the newest point; null when there are none
+	var sourceUpdated *string
+	var ingested interface{}
+	if len(points) > 0 {
+		sourceUpdated, ingested = sourceUpdatedAt(&points[0]), ingestedAt(&points[0])
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"symbol":            "DTWEXBGS",
-		"name":              "US Dollar Index",
+		"symbol":            seriesID,
+		"name":              name,
 		"count":             len(points),
 		"data":              points,
-		"source_updated_at": time.Now().Format(time.RFC3339),
-		"ingested_at":       time.Now().Format(time.RFC3339),
+		"source_updated_at": sourceUpdated,
+		"ingested_at":       ingested,
+		"as_of":             asOf,
 	})
 }
 
+// pointInTime parses an as_of query param and returns the store to read points
+// from: a view of the data as it had been recorded at that time, or the store
+// itself when asOf is empty. A bare date means the end of that day, UTC. The
+// returned time is RFC3339, or nil (JSON null) for current data.
+func (s *Server) pointInTime(asOf string) (*store.Store, *string, error) {
+	if asOf == "" {
+		return s.store, nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		day, dayErr := time.Parse("2006-01-02", asOf)
+		if dayErr != nil {
+			return nil, nil, fmt.Errorf("invalid as_of %q: use RFC3339 or YYYY-MM-DD", asOf)
+		}
+		t = day.Add(24*time.Hour - time.Millisecond)
+	}
+
+	formatted := t.UTC().Format(time.RFC3339)
+	return s.store.AsOf(t), &formatted, nil
+}
+
+// sourceUpdatedAt is when the source published or revised a point, or nil (JSON
+// null) if the source doesn't report it
+func sourceUpdatedAt(p *store.SeriesPoint) *string {
+	if p.SourceUpdatedAt == nil {
+		return nil
+	}
+	v := p.SourceUpdatedAt.UTC().Format(time.RFC3339)
+	return &v
+}
+
+// ingestedAt is when we last fetched a point
+func ingestedAt(p *store.SeriesPoint) string {
+	return p.IngestedAt.UTC().Format(time.RFC3339)
+}
+
 // API: Get proprietary indices
 func (s *Server) handleAPIIndices(w http.ResponseWriter, r *http.Request) {
 	indices, err := analytics.CalculateAllIndices(s.store)
@@ -383,6 +471,52 @@ func (s *Server) handleAPIIndices(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// API: Get persisted index history
+// Query params: index (IDX_RMB_PENETRATION, IDX_DIVERSIFICATION_PRESSURE; default both), start, end (YYYY-MM-DD)
+func (s *Server) handleAPIIndicesHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start := q.Get("start")
+	if start == "" {
+		start = analytics.IndexHistoryStart
+	}
+	end := q.Get("end")
+
+	seriesIDs := []string{analytics.SeriesRMBPenetration, analytics.SeriesDiversificationPressure}
+	if index := strings.ToUpper(q.Get("index")); index != "" {
+		if index != analytics.SeriesRMBPenetration && index != analytics.SeriesDiversificationPressure {
+			http.Error(w, `{"error":"Unknown index"}`, http.StatusBadRequest)
+			return
+		}
+		seriesIDs = []string{index}
+	}
+
+	history := make(map[string][]analytics.IndexPoint)
+	count := 0
+	for _, id := range seriesIDs {
+		points, err := analytics.GetIndexHistory(s.store, id, start, end)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":   "Failed to fetch index history",
+				"details": err.Error(),
+			})
+			return
+		}
+		history[id] = points
+		count += len(points)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"indices":   history,
+		"start":     start,
+		"end":       end,
+		"count":     count,
+		"timestamp": time.Now().Format(time.RFC3339),
+	})
+}
+
 const homeTemplate = `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -841,7 +975,7 @@ const homeTemplate = `<!DOCTYPE html>
                     {{end}}
                     
                     {{if .Why}}
-                    <div class="status-why">{{.Why}}</div>
+                    <div class="status-why"{{if .Rule}} data-rule="{{.Rule}}" title="Rule: {{.Rule}}"{{end}}>{{.Why}}</div>
                     {{end}}
                     
                     <div class="stat-date">
@@ -3,16 +3,27 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/store"
 	"reserve-watch/internal/util"
 )
 
-// handleAPISignals returns human-readable signal analysis for all indicators
+// handleAPISignals returns human-readable signal analysis for all indicators.
+// Query params: as_of (RFC3339 or YYYY-MM-DD) evaluates them on the data recorded by then
 func (s *Server) handleAPISignals(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	signals, err := analytics.GetAllSignals(s.store)
+	db, _, err := s.pointInTime(r.URL.Query().Get("as_of"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	signals, err := analytics.GetAllSignals(db)
 	if err != nil {
 		util.ErrorLogger.Printf("Failed to get signals: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -24,3 +35,41 @@ func (s *Server) handleAPISignals(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(signals)
 }
+
+// handleAPISignalsHistory lists recorded signal status transitions, newest first.
+// Query params: signal (e.g. vix), days (default 30), limit (default 100)
+func (s *Server) handleAPISignalsHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	filter := store.SignalTransitionFilter{
+		SignalKey: q.Get("signal"),
+		Since:     time.Now().AddDate(0, 0, -30),
+		Limit:     100,
+	}
+	if days, err := strconv.Atoi(q.Get("days")); err == nil && days > 0 {
+		filter.Since = time.Now().AddDate(0, 0, -days)
+	}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 && limit <= 1000 {
+		filter.Limit = limit
+	}
+
+	transitions, err := s.store.GetSignalTransitions(filter)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to get signal transitions: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Failed to load signal history",
+		})
+		return
+	}
+	if transitions == nil {
+		transitions = []store.SignalTransition{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"transitions": transitions,
+		"count":       len(transitions),
+		"since":       filter.Since.UTC().Format(time.RFC3339),
+	})
+}
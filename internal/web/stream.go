@@ -0,0 +1,259 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"reserve-watch/internal/analytics"
+	"reserve-watch/internal/store"
+	"reserve-watch/internal/util"
+)
+
+const (
+	streamBufferSize        = 1000             // recent events kept for Last-Event-ID resume
+	streamClientQueue       = 256              // events a client may fall behind before it is dropped
+	streamHeartbeatInterval = 15 * time.Second // interval between heartbeats
+	streamMaxPoints         = 100              // observations included per series update
+)
+
+// Stream event types
+const (
+	streamPoint     = "point"     // new or revised observations of a series
+	streamIndex     = "index"     // new or revised values of a persisted index
+	streamSignal    = "signal"    // a signal changed status
+	streamReset     = "reset"     // events since Last-Event-ID are gone; refetch state
+	streamHeartbeat = "heartbeat" // keeps idle connections and proxies alive
+)
+
+type streamEvent struct {
+	ID       int64
+	Type     string
+	SeriesID string
+	Data     []byte
+}
+
+type streamObservation struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// streamUpdate is the data of point and index events
+type streamUpdate struct {
+	SeriesID string              `json:"series_id"`
+	Latest   streamObservation   `json:"latest"`
+	Previous *streamObservation  `json:"previous"`
+	Points   []streamObservation `json:"points"`  // changed observations, at most streamMaxPoints
+	Changed  int                 `json:"changed"` // total changed observations
+	At       time.Time           `json:"at"`
+}
+
+// streamHub fans series updates and signal transitions out to /api/stream clients
+// and keeps a buffer of recent events for clients that reconnect
+type streamHub struct {
+	mu      sync.Mutex
+	nextID  int64
+	buffer  []streamEvent // oldest first
+	clients map[*streamClient]struct{}
+}
+
+type streamClient struct {
+	series map[string]bool // nil streams every series
+	events chan streamEvent
+}
+
+func newStreamHub(db *store.Store) *streamHub {
+	h := &streamHub{
+		// IDs start from the clock so they keep increasing across restarts
+		nextID:  time.Now().UnixMilli(),
+		clients: make(map[*streamClient]struct{}),
+	}
+	db.Events().Subscribe("stream", h.seriesUpdated)
+	db.SignalEvents().Subscribe("stream", h.signalTransitions)
+	return h
+}
+
+func (h *streamHub) seriesUpdated(events []store.SeriesUpdated) {
+	for _, e := range events {
+		update := streamUpdate{
+			SeriesID: e.SeriesID,
+			Latest:   streamObservation{Date: e.Latest.Date, Value: e.Latest.Value},
+			Changed:  len(e.Points),
+			At:       e.At.UTC(),
+		}
+		if e.Previous != nil {
+			update.Previous = &streamObservation{Date: e.Previous.Date, Value: e.Previous.Value}
+		}
+		points := e.Points
+		if len(points) > streamMaxPoints {
+			points = points[len(points)-streamMaxPoints:]
+		}
+		for _, p := range points {
+			update.Points = append(update.Points, streamObservation{Date: p.Date, Value: p.Value})
+		}
+
+		eventType := streamPoint
+		if analytics.IsIndexSeries(e.SeriesID) {
+			eventType = streamIndex
+		}
+		h.publish(eventType, e.SeriesID, update)
+	}
+}
+
+func (h *streamHub) signalTransitions(transitions []store.SignalTransition) {
+	for _, t := range transitions {
+		h.publish(streamSignal, t.SeriesID, t)
+	}
+}
+
+// publish assigns the next ID, buffers the event and sends it to matching clients.
+// A client too far behind is dropped; it resumes from the buffer when it reconnects.
+func (h *streamHub) publish(eventType, seriesID string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		util.ErrorLogger.Printf("Failed to encode %s stream event: %v", eventType, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e := streamEvent{ID: h.nextID, Type: eventType, SeriesID: seriesID, Data: payload}
+	h.nextID++
+
+	h.buffer = append(h.buffer, e)
+	if len(h.buffer) > streamBufferSize {
+		h.buffer = h.buffer[len(h.buffer)-streamBufferSize:]
+	}
+
+	for c := range h.clients {
+		if !c.wants(e) {
+			continue
+		}
+		select {
+		case c.events <- e:
+		default:
+			delete(h.clients, c)
+			close(c.events)
+		}
+	}
+}
+
+// subscribe registers a client and returns the buffered events it missed after
+// lastID (0 for a new connection). reset reports that some missed events are no
+// longer buffered; current is the newest event ID at the time of subscribing.
+func (h *streamHub) subscribe(series map[string]bool, lastID int64) (c *streamClient, missed []streamEvent, reset bool, current int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	c = &streamClient{series: series, events: make(chan streamEvent, streamClientQueue)}
+	h.clients[c] = struct{}{}
+	current = h.nextID - 1
+
+	if lastID == 0 || lastID == current {
+		return c, nil, false, current
+	}
+	if len(h.buffer) == 0 || lastID < h.buffer[0].ID-1 || lastID > current {
+		reset = true
+	}
+	for _, e := range h.buffer {
+		if e.ID > lastID && c.wants(e) {
+			missed = append(missed, e)
+		}
+	}
+	return c, missed, reset, current
+}
+
+func (h *streamHub) unsubscribe(c *streamClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.events)
+	}
+}
+
+func (c *streamClient) wants(e streamEvent) bool {
+	return c.series == nil || c.series[e.SeriesID]
+}
+
+// handleStream serves Server-Sent Events: GET /api/stream?series=VIXCLS,DTWEXBGS
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var series map[string]bool
+	if param := r.URL.Query().Get("series"); param != "" {
+		series = make(map[string]bool)
+		for _, id := range strings.Split(param, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				series[id] = true
+			}
+		}
+	}
+
+	// Browsers send Last-Event-ID when they reconnect; the query parameter lets
+	// clients resume a fresh connection
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	lastID, _ := strconv.ParseInt(lastEventID, 10, 64)
+
+	client, missed, reset, current := s.stream.subscribe(series, lastID)
+	defer s.stream.unsubscribe(client)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if reset {
+		// With nothing to replay, move the client's cursor past the gap so its next
+		// reconnect resumes normally
+		if len(missed) == 0 {
+			fmt.Fprintf(w, "id: %d\n", current)
+		}
+		fmt.Fprintf(w, "event: %s\ndata: {\"last_event_id\":%d}\n\n", streamReset, lastID)
+	}
+	for _, e := range missed {
+		writeStreamEvent(w, e)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-client.events:
+			if !ok {
+				return
+			}
+			writeStreamEvent(w, e)
+		case now := <-heartbeat.C:
+			fmt.Fprintf(w, "event: %s\ndata: {\"time\":%q}\n\n", streamHeartbeat, now.UTC().Format(time.RFC3339))
+		}
+		flusher.Flush()
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, e streamEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+}
@@ -0,0 +1,110 @@
+package web
+
+import "testing"
+
+func newTestStreamHub(nextID int64) *streamHub {
+	return &streamHub{nextID: nextID, clients: make(map[*streamClient]struct{})}
+}
+
+func TestStreamResumeWithinBuffer(t *testing.T) {
+	h := newTestStreamHub(100)
+	for _, id := range []string{"VIXCLS", "DTWEXBGS", "VIXCLS", "DTWEXBGS", "VIXCLS"} {
+		h.publish(streamPoint, id, map[string]string{"series_id": id})
+	}
+
+	c, missed, reset, current := h.subscribe(nil, 101)
+	defer h.unsubscribe(c)
+	if reset || current != 104 {
+		t.Errorf("Expected no reset at current 104, got reset=%v current=%d", reset, current)
+	}
+	if len(missed) != 3 || missed[0].ID != 102 || missed[2].ID != 104 {
+		t.Errorf("Expected events 102-104 to be replayed, got %+v", missed)
+	}
+
+	filtered, missed, _, _ := h.subscribe(map[string]bool{"VIXCLS": true}, 101)
+	defer h.unsubscribe(filtered)
+	if len(missed) != 2 || missed[0].ID != 102 || missed[1].ID != 104 {
+		t.Errorf("Expected only the VIXCLS events 102 and 104, got %+v", missed)
+	}
+
+	upToDate, missed, reset, _ := h.subscribe(nil, 104)
+	defer h.unsubscribe(upToDate)
+	if reset || len(missed) != 0 {
+		t.Errorf("Expected nothing to replay for an up-to-date client, got reset=%v missed=%d", reset, len(missed))
+	}
+}
+
+func TestStreamResumeBeforeBuffer(t *testing.T) {
+	h := newTestStreamHub(1)
+	for i := 0; i < streamBufferSize+10; i++ {
+		h.publish(streamPoint, "VIXCLS", i)
+	}
+
+	c, missed, reset, current := h.subscribe(nil, 5)
+	defer h.unsubscribe(c)
+	if !reset {
+		t.Error("Expected a reset when resuming from an event no longer buffered")
+	}
+	if len(missed) != streamBufferSize || missed[0].ID != 11 || current != streamBufferSize+10 {
+		t.Errorf("Expected the whole buffer from event 11 to %d, got %d events (current %d)", streamBufferSize+10, len(missed), current)
+	}
+
+	// The event just before the oldest buffered one leaves no gap
+	c2, missed, reset, _ := h.subscribe(nil, 10)
+	defer h.unsubscribe(c2)
+	if reset || len(missed) != streamBufferSize {
+		t.Errorf("Expected a full replay without reset, got reset=%v missed=%d", reset, len(missed))
+	}
+}
+
+func TestStreamResumeAfterRestart(t *testing.T) {
+	// A restarted hub starts from a lower ID than a client saw before the restart
+	h := newTestStreamHub(500)
+
+	c, missed, reset, current := h.subscribe(nil, 900)
+	defer h.unsubscribe(c)
+	if !reset || len(missed) != 0 || current != 499 {
+		t.Errorf("Expected a reset with nothing to replay at current 499, got reset=%v missed=%d current=%d", reset, len(missed), current)
+	}
+
+	h.publish(streamSignal, "VIXCLS", nil)
+	c2, missed, reset, _ := h.subscribe(nil, 900)
+	defer h.unsubscribe(c2)
+	if !reset || len(missed) != 0 {
+		t.Errorf("Expected a reset and no events newer than the client's ID, got reset=%v missed=%d", reset, len(missed))
+	}
+}
+
+func TestStreamDropsSlowClient(t *testing.T) {
+	h := newTestStreamHub(1)
+
+	slow, _, _, _ := h.subscribe(nil, 0)
+	other, _, _, _ := h.subscribe(map[string]bool{"DTWEXBGS": true}, 0)
+	defer h.unsubscribe(other)
+
+	for i := 0; i <= streamClientQueue; i++ {
+		h.publish(streamPoint, "VIXCLS", i)
+	}
+
+	h.mu.Lock()
+	_, slowKept := h.clients[slow]
+	_, otherKept := h.clients[other]
+	h.mu.Unlock()
+	if slowKept {
+		t.Error("Expected the client whose queue overflowed to be removed")
+	}
+	if !otherKept {
+		t.Error("Expected a client not sent the events to be kept")
+	}
+
+	received := 0
+	for range slow.events {
+		received++
+	}
+	if received != streamClientQueue {
+		t.Errorf("Expected %d queued events before the channel closed, got %d", streamClientQueue, received)
+	}
+
+	// Unsubscribing a dropped client must not close its channel again
+	h.unsubscribe(slow)
+}
@@ -3,7 +3,11 @@ package web
 import (
 	"fmt"
 	"html/template"
+	"math"
 	"net/http"
+	"strings"
+
+	"reserve-watch/internal/analytics"
 )
 
 type TriggerMetric struct {
@@ -15,64 +19,50 @@ type TriggerMetric struct {
 	Description string
 }
 
-func (s *Server) handleTriggerWatch(w http.ResponseWriter, r *http.Request) {
-	// Get VIX from FRED
-	vixData, _ := s.store.GetLatestPoint("VIXCLS")
+// triggerSeries are the market-stress series shown on Trigger Watch; thresholds come from the signal rules
+var triggerSeries = []struct {
+	SeriesID string
+	Decimals int
+	Unit     string
+}{
+	{"VIXCLS", 2, ""},
+	{"BAMLC0A4CBBB", 0, "bps"},
+}
 
-	// Get BBB OAS from FRED
-	bbbData, _ := s.store.GetLatestPoint("BAMLC0A4CBBB")
+func (s *Server) handleTriggerWatch(w http.ResponseWriter, r *http.Request) {
+	rs := analytics.Rules()
 
 	var triggers []TriggerMetric
-
-	// VIX Trigger
-	if vixData != nil {
-		status := "safe"
-		statusColor := "#4CAF50"
-		threshold := "< 20"
-
-		if vixData.Value > 30 {
-			status = "critical"
-			statusColor = "#f44336"
-			threshold = "> 30 (CRITICAL)"
-		} else if vixData.Value > 20 {
-			status = "warning"
-			statusColor = "#ff9800"
-			threshold = "> 20 (WARNING)"
+	for _, ts := range triggerSeries {
+		rule, ok := rs.Rule(ts.SeriesID)
+		if !ok {
+			continue
+		}
+		signal, ok := rs.EvaluateLatest(s.store, ts.SeriesID)
+		if !ok {
+			continue
 		}
 
-		triggers = append(triggers, TriggerMetric{
-			Name:        "VIX (Volatility Index)",
-			Value:       formatFloat(vixData.Value, 2),
-			Threshold:   threshold,
-			Status:      status,
-			StatusColor: statusColor,
-			Description: "Market fear gauge. >20 = elevated vol, >30 = panic",
-		})
-	}
-
-	// BBB OAS Trigger
-	if bbbData != nil {
-		status := "safe"
-		statusColor := "#4CAF50"
-		threshold := "< 200bps"
+		status, statusColor := triggerStatus(signal.Status)
 
-		if bbbData.Value > 400 {
-			status = "critical"
-			statusColor = "#f44336"
-			threshold = "> 400bps (CRITICAL)"
-		} else if bbbData.Value > 200 {
-			status = "warning"
-			statusColor = "#ff9800"
-			threshold = "> 200bps (WARNING)"
+		threshold := "No watch or crisis level set"
+		if lowest, ok := lowestTrigger(rule); ok {
+			threshold = "< " + formatFloat(lowest, ts.Decimals) + ts.Unit
+		}
+		if signal.RuleType != analytics.RuleLevel && signal.Rule != "" {
+			// A rate-of-change or z-score rule escalated the level status
+			threshold = signal.Why + " (" + strings.ToUpper(status) + ")"
+		} else if band, ok := rule.Match(signal.Value); ok && band.Min != nil && status != "safe" {
+			threshold = "≥ " + formatFloat(*band.Min, ts.Decimals) + ts.Unit + " (" + strings.ToUpper(status) + ")"
 		}
 
 		triggers = append(triggers, TriggerMetric{
-			Name:        "BBB OAS (Credit Spread)",
-			Value:       formatFloat(bbbData.Value, 0) + "bps",
+			Name:        rule.Name,
+			Value:       formatFloat(signal.Value, ts.Decimals) + ts.Unit,
 			Threshold:   threshold,
 			Status:      status,
 			StatusColor: statusColor,
-			Description: "Credit risk gauge. >200 = stress, >400 = crisis",
+			Description: triggerDescription(rule, ts.Decimals),
 		})
 	}
 
@@ -90,6 +80,45 @@ func (s *Server) handleTriggerWatch(w http.ResponseWriter, r *http.Request) {
 	tmpl.Execute(w, data)
 }
 
+// triggerStatus maps a signal status to the Trigger Watch status and color
+func triggerStatus(status analytics.SignalStatus) (string, string) {
+	switch status {
+	case analytics.StatusCrisis:
+		return "critical", "#f44336"
+	case analytics.StatusWatch:
+		return "warning", "#ff9800"
+	default:
+		return "safe", "#4CAF50"
+	}
+}
+
+// lowestTrigger returns the lowest lower bound of a watch or crisis band; false if
+// no such band has one
+func lowestTrigger(rule analytics.SeriesRule) (float64, bool) {
+	lowest, found := math.Inf(1), false
+	for _, b := range rule.Bands {
+		if b.Min != nil && (b.Status == analytics.StatusWatch || b.Status == analytics.StatusCrisis) {
+			lowest, found = math.Min(lowest, *b.Min), true
+		}
+	}
+	return lowest, found
+}
+
+// triggerDescription appends the rule's cut-offs, e.g. "Market fear gauge. ≥20 = watch, ≥30 = crisis"
+func triggerDescription(rule analytics.SeriesRule, decimals int) string {
+	var cutoffs []string
+	for i := len(rule.Bands) - 1; i >= 0; i-- {
+		b := rule.Bands[i]
+		if b.Min != nil && (b.Status == analytics.StatusWatch || b.Status == analytics.StatusCrisis) {
+			cutoffs = append(cutoffs, "≥"+formatFloat(*b.Min, decimals)+" = "+string(b.Status))
+		}
+	}
+	if len(cutoffs) == 0 {
+		return rule.Description
+	}
+	return rule.Description + ". " + strings.Join(cutoffs, ", ")
+}
+
 func formatFloat(val float64, decimals int) string {
 	format := "%." + string(rune(decimals+'0')) + "f"
 	return fmt.Sprintf(format, val)
@@ -0,0 +1,25 @@
+package web
+
+import (
+	"testing"
+
+	"reserve-watch/internal/analytics"
+)
+
+func TestLowestTrigger(t *testing.T) {
+	watch, crisis := 20.0, 30.0
+	rule := analytics.SeriesRule{Bands: []analytics.Band{
+		{Min: &crisis, Status: analytics.StatusCrisis},
+		{Min: &watch, Status: analytics.StatusWatch},
+		{Status: analytics.StatusGood},
+	}}
+	if lowest, ok := lowestTrigger(rule); !ok || lowest != 20 {
+		t.Errorf("Expected the watch band's 20, got %v %v", lowest, ok)
+	}
+
+	// Rules are user-editable; one without watch/crisis bounds has no trigger level
+	rule = analytics.SeriesRule{Bands: []analytics.Band{{Min: &watch, Status: analytics.StatusNeutral}, {Status: analytics.StatusWatch}}}
+	if lowest, ok := lowestTrigger(rule); ok {
+		t.Errorf("Expected no trigger level, got %v", lowest)
+	}
+}
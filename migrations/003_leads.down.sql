@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS email_log;
+DROP TABLE IF EXISTS leads;
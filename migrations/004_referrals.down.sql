@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS social_posts;
+DROP TABLE IF EXISTS referral_credits;
+DROP TABLE IF EXISTS referrals;
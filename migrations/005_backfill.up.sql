@@ -0,0 +1,9 @@
+-- Historical backfill coverage per series, so interrupted backfills can resume
+CREATE TABLE IF NOT EXISTS backfill_progress (
+    series_id TEXT PRIMARY KEY,
+    start_date TEXT NOT NULL,
+    covered_through TEXT NOT NULL,
+    points_saved INTEGER DEFAULT 0,
+    completed_at TEXT,
+    updated_at TEXT DEFAULT (datetime('now'))
+);
@@ -0,0 +1 @@
+-- Irreversible: the original "2024-Q2" period labels are not kept once normalized to quarter-end dates
@@ -0,0 +1,13 @@
+-- COFER points used to be dated by IMF period ("2024-Q2"); normalize them to quarter-end dates
+UPDATE OR IGNORE series_points
+SET date = substr(date, 1, 4) || CASE substr(date, 7, 1)
+    WHEN '1' THEN '-03-31'
+    WHEN '2' THEN '-06-30'
+    WHEN '3' THEN '-09-30'
+    ELSE '-12-31'
+END
+WHERE series_name LIKE 'COFER%' AND date GLOB '[0-9][0-9][0-9][0-9]-Q[1-4]';
+
+-- Rows that collided with an already-normalized date are duplicates
+DELETE FROM series_points
+WHERE series_name LIKE 'COFER%' AND date GLOB '[0-9][0-9][0-9][0-9]-Q[1-4]';
@@ -0,0 +1,17 @@
+-- Every signal status change, so status history survives restarts and rule reloads.
+-- The latest row per signal_key is that signal's current state.
+CREATE TABLE IF NOT EXISTS signal_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    signal_key TEXT NOT NULL, -- 'dtwexbgs', 'vix', etc.
+    series_id TEXT NOT NULL,
+    from_status TEXT NOT NULL DEFAULT '', -- '' for the first recorded state
+    to_status TEXT NOT NULL,
+    value REAL NOT NULL,
+    as_of TEXT NOT NULL,
+    rule TEXT NOT NULL DEFAULT '',
+    why TEXT NOT NULL DEFAULT '',
+    created_at TEXT DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_signal_transitions_key ON signal_transitions(signal_key, id);
+CREATE INDEX IF NOT EXISTS idx_signal_transitions_created ON signal_transitions(created_at);
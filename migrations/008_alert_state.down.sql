@@ -0,0 +1,7 @@
+ALTER TABLE alerts DROP COLUMN rearm_band;
+ALTER TABLE alerts DROP COLUMN confirmations;
+ALTER TABLE alerts DROP COLUMN cooldown_minutes;
+ALTER TABLE alerts DROP COLUMN state;
+ALTER TABLE alerts DROP COLUMN breach_count;
+ALTER TABLE alerts DROP COLUMN last_observed_date;
+ALTER TABLE alerts DROP COLUMN state_changed_at;
@@ -0,0 +1,11 @@
+-- Alert trigger settings and state machine. An alert fires when the value crosses its
+-- threshold, then stays 'triggered' until the value comes back past the re-arm band.
+--   state: 'armed' (on the safe side), 'pending' (beyond the threshold, awaiting
+--          confirmations or the cooldown), 'triggered' (fired, waiting to re-arm)
+ALTER TABLE alerts ADD COLUMN rearm_band REAL NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN confirmations INTEGER NOT NULL DEFAULT 1; -- consecutive observations beyond the threshold
+ALTER TABLE alerts ADD COLUMN cooldown_minutes INTEGER NOT NULL DEFAULT 60;
+ALTER TABLE alerts ADD COLUMN state TEXT NOT NULL DEFAULT 'armed';
+ALTER TABLE alerts ADD COLUMN breach_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN last_observed_date TEXT NOT NULL DEFAULT ''; -- newest observation evaluated
+ALTER TABLE alerts ADD COLUMN state_changed_at TEXT;
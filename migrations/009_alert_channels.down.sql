@@ -0,0 +1,2 @@
+ALTER TABLE alerts DROP COLUMN channels;
+ALTER TABLE alert_history DROP COLUMN deliveries;
@@ -0,0 +1,7 @@
+-- Delivery channels per alert, e.g. [{"type": "slack", "target": "https://hooks.slack.com/..."}].
+-- webhook_url stays as a plain webhook channel.
+ALTER TABLE alerts ADD COLUMN channels TEXT NOT NULL DEFAULT '';
+
+-- Per-channel outcome of each trigger, e.g. [{"channel": "email", "target": "...", "status": "success"}].
+-- webhook_status summarises them: 'success', 'failed' (any channel failed) or 'skipped'.
+ALTER TABLE alert_history ADD COLUMN deliveries TEXT NOT NULL DEFAULT '';
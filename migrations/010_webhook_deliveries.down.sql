@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+ALTER TABLE alerts DROP COLUMN webhook_secret;
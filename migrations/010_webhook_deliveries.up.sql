@@ -0,0 +1,27 @@
+-- Per-alert secret for signing webhook payloads (HMAC-SHA256). Alerts without one get a
+-- random secret. Re-running this is harmless since only empty secrets are filled.
+ALTER TABLE alerts ADD COLUMN webhook_secret TEXT NOT NULL DEFAULT '';
+UPDATE alerts SET webhook_secret = 'whsec_' || lower(hex(randomblob(24))) WHERE webhook_secret = '';
+
+-- Webhook delivery log and outbox. Failed deliveries stay 'pending' with a backoff
+-- next_attempt_at until they succeed or run out of attempts ('dead').
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    alert_id INTEGER NOT NULL,
+    history_id INTEGER NOT NULL DEFAULT 0, -- alert_history row of the trigger
+    replay_of INTEGER NOT NULL DEFAULT 0, -- delivery this one replays
+    target TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'success' or 'dead'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TEXT,
+    delivered_at TEXT,
+    created_at TEXT DEFAULT (datetime('now')),
+    updated_at TEXT DEFAULT (datetime('now')),
+    FOREIGN KEY(alert_id) REFERENCES alerts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_alert ON webhook_deliveries(alert_id, id);
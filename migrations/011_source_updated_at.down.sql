@@ -0,0 +1 @@
+-- Irreversible: the cleared values were fetch times, which ingested_at already records
@@ -0,0 +1,3 @@
+-- source_updated_at used to be filled with the fetch time. Clear it so only timestamps
+-- reported by the source remain; the next fetch fills in sources that report one.
+UPDATE series_points SET source_updated_at = NULL;
@@ -0,0 +1,16 @@
+-- Series catalog: what each series_points.series_name means. Rows mirror the series
+-- declared by ingest sources and the computed indices, and are refreshed on startup.
+CREATE TABLE IF NOT EXISTS series (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    label TEXT NOT NULL DEFAULT '', -- short name for tiles, posts and exports
+    unit TEXT NOT NULL DEFAULT '',
+    frequency TEXT NOT NULL DEFAULT '',
+    source TEXT NOT NULL DEFAULT '', -- ingest source, e.g. 'fred'
+    provider TEXT NOT NULL DEFAULT '', -- publisher credited in attribution
+    license TEXT NOT NULL DEFAULT '',
+    license_url TEXT NOT NULL DEFAULT '',
+    link TEXT NOT NULL DEFAULT '', -- the series on the provider's site
+    position INTEGER NOT NULL DEFAULT 0, -- display order
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS series_point_vintages;
@@ -0,0 +1,21 @@
+-- Every value a (series, date) observation has had, so reads can be reproduced as of
+-- a past time. series_points keeps the current value; a row is added here whenever
+-- SavePoints inserts an observation or changes its value.
+CREATE TABLE IF NOT EXISTS series_point_vintages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    series_name TEXT NOT NULL,
+    date TEXT NOT NULL,
+    value REAL NOT NULL,
+    meta TEXT,
+    source_updated_at TEXT,
+    recorded_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) -- millisecond precision
+);
+
+CREATE INDEX IF NOT EXISTS idx_point_vintages ON series_point_vintages(series_name, date, recorded_at);
+
+-- Existing values become the first vintage, recorded when they were last fetched
+INSERT INTO series_point_vintages (series_name, date, value, meta, source_updated_at, recorded_at)
+SELECT series_name, date, value, meta, source_updated_at,
+       strftime('%Y-%m-%d %H:%M:%f', COALESCE(ingested_at, 'now'))
+FROM series_points
+ORDER BY series_name, date;
@@ -0,0 +1,2 @@
+DELETE FROM series_point_vintages WHERE deleted = 1;
+ALTER TABLE series_point_vintages DROP COLUMN deleted;
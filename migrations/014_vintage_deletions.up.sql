@@ -0,0 +1,3 @@
+-- A vintage with deleted = 1 records that the observation was removed at recorded_at,
+-- so point-in-time reads after that no longer return it
+ALTER TABLE series_point_vintages ADD COLUMN deleted INTEGER NOT NULL DEFAULT 0;
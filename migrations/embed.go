@@ -0,0 +1,12 @@
+// Package migrations embeds the numbered schema migrations so the binary doesn't
+// depend on a migrations directory next to it.
+//
+// Each version NNN has NNN_name.up.sql, applied in a transaction and recorded in
+// schema_migrations with its checksum, and NNN_name.down.sql to reverse it. An
+// applied up migration must never be edited; add a new version instead.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
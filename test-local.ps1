@@ -4,7 +4,7 @@ Write-Host "`n🚀 Reserve Watch - Local Test Suite`n" -ForegroundColor Cyan
 
 # Step 1: Build
 Write-Host "Step 1: Building..." -ForegroundColor Yellow
-go build -o reserve-watch.exe cmd/runner/main.go
+go build -o reserve-watch.exe ./cmd/runner
 
 if ($LASTEXITCODE -ne 0) {
     Write-Host "❌ Build failed!" -ForegroundColor Red